@@ -97,15 +97,21 @@ func main() {
 	// Initialize Redis connection
 	var likeCounter *cache.LikeCounter
 	var commentCounter *cache.CommentCounter
+	var pollCounter *cache.PollCounter
+	var trendingCounter *cache.TrendingCounter
+	var rankSnapshots *cache.RankSnapshots
 	redisClient, err := cache.NewRedisClient()
 	if err != nil {
 		log.Printf("WARNING: Failed to connect to Redis: %v", err)
-		log.Println("Like/Comment counters will use Cassandra fallback (slower)")
+		log.Println("Like/Comment/Poll counters will use Cassandra fallback (slower)")
 	} else {
 		defer redisClient.Close()
 		log.Println("Successfully connected to Redis")
 		likeCounter = cache.NewLikeCounter(redisClient)
 		commentCounter = cache.NewCommentCounter(redisClient)
+		pollCounter = cache.NewPollCounter(redisClient)
+		trendingCounter = cache.NewTrendingCounter(redisClient)
+		rankSnapshots = cache.NewRankSnapshots(redisClient)
 	}
 
 	// Initialize Cloudflare R2 media storage
@@ -123,6 +129,16 @@ func main() {
 	}
 	mediaHandler := &handlers.MediaHandler{Store: mediaStore}
 
+	// Sign pagination cursors with a key shared by every API instance. Without
+	// a key of its own, one is derived from JWT_SECRET rather than reusing it.
+	if cursorKey := os.Getenv("CURSOR_SIGNING_KEY"); cursorKey != "" {
+		data.SetCursorSigningKey([]byte(cursorKey))
+	} else if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
+		data.SetCursorSigningKey(data.DeriveCursorSigningKey([]byte(jwtSecret)))
+	} else {
+		slog.Warn("CURSOR_SIGNING_KEY not set — pagination cursors will not survive restarts")
+	}
+
 	// Initialize push service
 	deviceRepo := data.NewDeviceRepository(session)
 
@@ -130,12 +146,17 @@ func main() {
 	geoClient := geocoding.NewNominatimClient("Geoloc/1.0 (dev@geoloc.app)")
 
 	// Initialize repositories
-	postRepo := data.NewPostRepository(session)
+	postRepo := data.NewPostRepository(session, likeCounter)
 	userRepo := data.NewUserRepository(session)
 	likeRepo := data.NewLikeRepository(session, likeCounter)
 	commentRepo := data.NewCommentRepository(session, commentCounter)
+	pollRepo := data.NewPollRepository(session, pollCounter)
+	bookmarkRepo := data.NewBookmarkRepository(session)
+	mentionRepo := data.NewMentionRepository(session)
+	hashtagRepo := data.NewHashtagRepository(session)
 	followRepo := data.NewFollowRepository(session)
 	locFollowRepo := data.NewLocationFollowRepository(session)
+	timelineRepo := data.NewTimelineRepository(session)
 
 	var rawRedisClient *redis.Client
 	if redisClient != nil {
@@ -168,6 +189,28 @@ func main() {
 	locRepo := data.NewLocationRepository(session, geoClient)
 	resetRepo := data.NewPasswordResetRepository(session)
 	modRepo := data.NewModerationRepository(session)
+	closeFriendRepo := data.NewCloseFriendRepository(session)
+	zoneRepo := data.NewPrivacyZoneRepository(session)
+	suggestionRepo := data.NewSuggestionRepository(session)
+	draftRepo := data.NewDraftRepository(session, rawRedisClient)
+	mentioner := &handlers.Mentioner{
+		Users:      userRepo,
+		Posts:      postRepo,
+		Mentions:   mentionRepo,
+		Moderation: modRepo,
+		Notifier:   notifDispatcher,
+	}
+	publisher := &handlers.PostPublisher{
+		Posts:     postRepo,
+		Users:     userRepo,
+		Zones:     zoneRepo,
+		Timeline:  timelineRepo,
+		Notifier:  notifDispatcher,
+		Indexer:   searchIndexer,
+		Store:     mediaStore,
+		Mentioner: mentioner,
+		Trending:  trendingCounter,
+	}
 	dmRepo := data.NewDMRepository(session)
 
 	var dmKafka *kafka.DMMessageProducer
@@ -188,7 +231,26 @@ func main() {
 	// Initialize Elasticsearch and search service
 	esClient := search.NewESClient()
 	searchSvc := search.NewService(esClient, rawRedisClient)
-	searchHandler := handlers.NewNewSearchHandler(searchSvc, session, userRepo, locRepo, likeRepo, commentRepo, mediaStore)
+	searchHandler := handlers.NewNewSearchHandler(searchSvc, session, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore)
+
+	// How long after creation an author may edit a post (0 = no limit)
+	postEditWindow := time.Hour
+	if v := os.Getenv("POST_EDIT_WINDOW"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			postEditWindow = d
+		} else {
+			slog.Warn("Invalid POST_EDIT_WINDOW, using default", "value", v, "default", postEditWindow)
+		}
+	}
+
+	// Users who may read any comment's edit history (comma-separated user IDs).
+	// Post history needs no moderators: it is as visible as the post.
+	moderators := make(map[string]bool)
+	for _, id := range strings.Split(os.Getenv("MODERATOR_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			moderators[id] = true
+		}
+	}
 
 	// Setup Gin router
 	router := gin.Default()
@@ -277,27 +339,32 @@ func main() {
 	api.Use(auth.AuthRequired())
 	{
 		// Feed (now protected — filters blocked/muted users)
-		api.GET("/feed", handlers.GetFeed(postRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, mediaStore))
+		api.GET("/feed", handlers.GetFeed(postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, rankSnapshots, mediaStore))
+		api.GET("/feed/following", handlers.GetFollowingFeed(timelineRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, mediaStore))
 
 		// Geocode
 		api.GET("/geocode/address", handlers.GetAddress(locRepo))
 
 		// Profile
 		api.GET("/users/me", handlers.GetCurrentUser(userRepo, mediaStore))
-		api.PUT("/users/me", handlers.UpdateProfile(userRepo, followRepo, searchIndexer, mediaStore))
-		api.DELETE("/users/me", handlers.DeleteAccount(userRepo))
+		api.PUT("/users/me", handlers.UpdateProfile(userRepo, followRepo, timelineRepo, searchIndexer, mediaStore))
+		api.DELETE("/users/me", handlers.DeleteAccount(userRepo, searchIndexer))
 
 		// User routes
-		api.GET("/users/:id", handlers.GetUser(userRepo, mediaStore))
-		api.GET("/users/username/:username", handlers.GetUserByUsername(userRepo, mediaStore))
-		api.GET("/users/:id/posts", handlers.GetUserPosts(postRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
-		api.GET("/users/:id/liked-posts", handlers.GetLikedPosts(likeRepo, postRepo, userRepo, locRepo))
+		api.GET("/users/:id", handlers.GetUser(userRepo, followRepo, mediaStore))
+		api.GET("/users/username/:username", handlers.GetUserByUsername(userRepo, followRepo, mediaStore))
+		api.GET("/users/:id/posts", handlers.GetUserPosts(postRepo, followRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
+		api.GET("/users/:id/liked-posts", handlers.GetLikedPosts(likeRepo, followRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, commentRepo, mediaStore))
 
 		// Follow routes
-		api.POST("/users/:id/follow", handlers.FollowUser(followRepo, notifDispatcher))
+		api.POST("/users/:id/follow", handlers.FollowUser(followRepo, userRepo, timelineRepo, notifDispatcher))
 		api.DELETE("/users/:id/follow", handlers.UnfollowUser(followRepo))
-		api.GET("/users/:id/followers", handlers.GetFollowers(followRepo))
-		api.GET("/users/:id/following", handlers.GetFollowing(followRepo))
+		api.GET("/users/:id/followers", handlers.GetFollowers(followRepo, userRepo, mediaStore))
+		api.GET("/users/:id/following", handlers.GetFollowing(followRepo, userRepo, mediaStore))
+		api.GET("/users/suggestions", handlers.GetSuggestions(suggestionRepo, userRepo, followRepo, modRepo, mediaStore))
+		api.GET("/users/me/follow-requests", handlers.GetFollowRequests(followRepo, userRepo, mediaStore))
+		api.POST("/users/me/follow-requests/:id/approve", handlers.ApproveFollowRequest(followRepo, timelineRepo, notifDispatcher))
+		api.DELETE("/users/me/follow-requests/:id", handlers.DenyFollowRequest(followRepo))
 
 		// Block/Mute routes
 		api.POST("/users/:id/block", handlers.BlockUser(modRepo))
@@ -306,35 +373,102 @@ func main() {
 		api.DELETE("/users/:id/mute", handlers.UnmuteUser(modRepo))
 		api.GET("/users/me/blocked", handlers.GetBlockedUsers(modRepo))
 		api.GET("/users/me/muted", handlers.GetMutedUsers(modRepo))
+		api.POST("/users/me/muted-words", handlers.MuteWord(modRepo))
+		api.DELETE("/users/me/muted-words/:word", handlers.UnmuteWord(modRepo))
+		api.GET("/users/me/muted-words", handlers.GetMutedWords(modRepo))
+
+		// Close friends (audience for close_friends posts)
+		api.POST("/users/:id/close-friend", handlers.AddCloseFriend(closeFriendRepo, userRepo))
+		api.DELETE("/users/:id/close-friend", handlers.RemoveCloseFriend(closeFriendRepo))
+		api.GET("/users/me/close-friends", handlers.GetCloseFriends(closeFriendRepo))
+
+		// Privacy zones (posts inside are published at a coarser location)
+		api.GET("/users/me/privacy-zones", handlers.GetPrivacyZones(zoneRepo))
+		api.POST("/users/me/privacy-zones", handlers.CreatePrivacyZone(zoneRepo))
+		api.DELETE("/users/me/privacy-zones/:id", handlers.DeletePrivacyZone(zoneRepo))
+
+		// Mentions tab: posts and comments that mention the current user
+		api.GET("/users/me/mentions", handlers.GetMentions(mentionRepo, postRepo, commentRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, modRepo, mediaStore))
+
+		// Bookmarks (private to their owner)
+		api.GET("/users/me/bookmarks", handlers.GetBookmarks(bookmarkRepo, postRepo, pollRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
+		api.GET("/users/me/bookmark-collections", handlers.GetBookmarkCollections(bookmarkRepo))
+		api.POST("/users/me/bookmark-collections", handlers.CreateBookmarkCollection(bookmarkRepo))
+		api.PUT("/users/me/bookmark-collections/:id", handlers.RenameBookmarkCollection(bookmarkRepo))
+		api.DELETE("/users/me/bookmark-collections/:id", handlers.DeleteBookmarkCollection(bookmarkRepo))
 
 		// Post routes
-		api.POST("/posts", handlers.CreatePost(postRepo, userRepo, notifDispatcher, searchIndexer, mediaStore))
-		api.GET("/posts/:id", handlers.GetPost(postRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
-		api.DELETE("/posts/:id", handlers.DeletePost(postRepo))
+		api.POST("/posts", handlers.CreatePost(publisher))
+		api.GET("/posts/:id", handlers.GetPost(postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
+		api.PUT("/posts/:id", handlers.UpdatePost(postRepo, mentioner, searchIndexer, mediaStore, postEditWindow))
+		api.GET("/posts/:id/revisions", handlers.GetPostRevisions(postRepo, mediaStore))
+		api.DELETE("/posts/:id", handlers.DeletePost(postRepo, searchIndexer))
+
+		// Drafts and scheduled posts
+		api.GET("/posts/drafts", handlers.GetDrafts(draftRepo))
+		api.POST("/posts/drafts", handlers.CreateDraft(draftRepo, publisher))
+		api.GET("/posts/drafts/:id", handlers.GetDraft(draftRepo))
+		api.PUT("/posts/drafts/:id", handlers.UpdateDraft(draftRepo, publisher))
+		api.DELETE("/posts/drafts/:id", handlers.DeleteDraft(draftRepo))
+		api.POST("/posts/drafts/:id/publish", handlers.PublishDraft(draftRepo, publisher))
 
 		// Post likes (legacy + new idempotent toggle)
-		api.POST("/posts/:id/like", handlers.LikePost(likeRepo))
+		api.POST("/posts/:id/like", handlers.LikePost(likeRepo, postRepo))
 		api.DELETE("/posts/:id/like", handlers.UnlikePost(likeRepo))
 		api.POST("/posts/:id/toggle-like", handlers.TogglePostLike(likeRepo, postRepo, notifDispatcher))
 
+		// Post reactions (❤️ is the same as a like)
+		api.PUT("/posts/:id/reaction", handlers.SetPostReaction(likeRepo, postRepo, notifDispatcher))
+		api.DELETE("/posts/:id/reaction", handlers.RemovePostReaction(likeRepo))
+		api.GET("/posts/:id/likes", handlers.GetPostLikers(likeRepo, postRepo, userRepo, followRepo, modRepo, mediaStore))
+
+		// Reposts (quote posts are created with POST /posts and quoted_post_id)
+		api.POST("/posts/:id/repost", handlers.RepostPost(postRepo, timelineRepo, notifDispatcher))
+		api.DELETE("/posts/:id/repost", handlers.UnrepostPost(postRepo))
+
+		// Polls (created with POST /posts and a poll)
+		api.POST("/posts/:id/poll/vote", handlers.VotePoll(pollRepo, postRepo))
+
+		// Bookmarks
+		api.POST("/posts/:id/bookmark", handlers.BookmarkPost(bookmarkRepo, postRepo))
+		api.DELETE("/posts/:id/bookmark", handlers.UnbookmarkPost(bookmarkRepo))
+
 		// Post comments
-		api.POST("/posts/:id/comments", handlers.CreateComment(commentRepo, postRepo, notifDispatcher))
-		api.GET("/posts/:id/comments", handlers.GetComments(commentRepo, userRepo, likeRepo, mediaStore))
+		api.POST("/posts/:id/comments", handlers.CreateComment(commentRepo, postRepo, followRepo, mentioner, notifDispatcher))
+		api.GET("/posts/:id/comments", handlers.GetComments(commentRepo, postRepo, userRepo, likeRepo, rankSnapshots, mediaStore))
+		api.PUT("/posts/:id/pinned-comment", handlers.PinComment(commentRepo, postRepo, notifDispatcher))
+		api.DELETE("/posts/:id/pinned-comment", handlers.UnpinComment(commentRepo, postRepo))
+		api.GET("/posts/:id/comment-settings", handlers.GetCommentSettings(commentRepo, postRepo, followRepo))
+		api.PUT("/posts/:id/comment-settings", handlers.UpdateCommentSettings(commentRepo, postRepo))
 
 		// Comment routes
-		api.POST("/comments/:id/reply", handlers.ReplyToComment(commentRepo, notifDispatcher))
-		api.GET("/comments/:id/replies", handlers.GetReplies(commentRepo, userRepo, likeRepo, mediaStore))
-		api.PUT("/comments/:id", handlers.EditComment(commentRepo))
+		api.POST("/comments/:id/reply", handlers.ReplyToComment(commentRepo, postRepo, followRepo, mentioner, notifDispatcher))
+		api.GET("/comments/:id/replies", handlers.GetReplies(commentRepo, postRepo, userRepo, likeRepo, mediaStore))
+		api.PUT("/comments/:id", handlers.EditComment(commentRepo, mentioner))
 		api.DELETE("/comments/:id", handlers.DeleteComment(commentRepo))
-		api.POST("/comments/:id/like", handlers.LikeComment(likeRepo))
+		api.POST("/comments/:id/hide", handlers.HideComment(commentRepo, postRepo, notifDispatcher))
+		api.DELETE("/comments/:id/hide", handlers.UnhideComment(commentRepo, postRepo))
+		api.GET("/comments/:id/revisions", handlers.GetCommentRevisions(commentRepo, postRepo, moderators))
+		api.GET("/comments/:id/context", handlers.GetCommentContext(commentRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, mediaStore))
+		api.POST("/comments/:id/like", handlers.LikeComment(likeRepo, commentRepo, postRepo))
 		api.DELETE("/comments/:id/like", handlers.UnlikeComment(likeRepo))
-		api.POST("/comments/:id/toggle-like", handlers.ToggleCommentLike(likeRepo, commentRepo, notifDispatcher))
+		api.POST("/comments/:id/toggle-like", handlers.ToggleCommentLike(likeRepo, commentRepo, postRepo, notifDispatcher))
+		api.PUT("/comments/:id/reaction", handlers.SetCommentReaction(likeRepo, commentRepo, postRepo, notifDispatcher))
+		api.DELETE("/comments/:id/reaction", handlers.RemoveCommentReaction(likeRepo))
+		api.GET("/comments/:id/likes", handlers.GetCommentLikers(likeRepo, commentRepo, postRepo, userRepo, followRepo, modRepo, mediaStore))
 
 		// Location follow routes
 		api.POST("/locations/follow", handlers.FollowLocation(locFollowRepo))
 		api.DELETE("/locations/:geohash/follow", handlers.UnfollowLocation(locFollowRepo))
 		api.GET("/locations/following", handlers.GetFollowedLocations(locFollowRepo))
 
+		// Hashtag pages and follows (served from Cassandra, not Elasticsearch)
+		api.GET("/hashtags/:tag", handlers.GetHashtag(hashtagRepo))
+		api.GET("/hashtags/:tag/posts", handlers.GetHashtagPosts(hashtagRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, mediaStore))
+		api.POST("/hashtags/:tag/follow", handlers.FollowHashtag(hashtagRepo))
+		api.DELETE("/hashtags/:tag/follow", handlers.UnfollowHashtag(hashtagRepo))
+		api.GET("/users/me/hashtags", handlers.GetFollowedHashtags(hashtagRepo))
+
 		// Direct messages (E2EE ciphertext)
 		handlers.RegisterDMRoutes(api, dmHandler)
 
@@ -348,13 +482,19 @@ func main() {
 
 		// Search routes (legacy Cassandra-backed)
 		api.GET("/search/users", handlers.SearchUsers(userRepo, mediaStore))
-		api.GET("/search/posts", handlers.SearchPosts(postRepo, userRepo, likeRepo, commentRepo, mediaStore))
+		api.GET("/search/posts", handlers.SearchPosts(postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
 
 		// Search routes (Elasticsearch-backed)
 		api.GET("/search/nearby", searchHandler.SearchNearbyHandler)
 		api.GET("/search", searchHandler.SearchHandler)
 		api.GET("/autocomplete", searchHandler.AutocompleteHandler)
 
+		// Map viewport (Elasticsearch-backed)
+		api.GET("/map/posts", handlers.GetMapPosts(searchSvc, modRepo))
+
+		// Trending hashtags (Redis counts, Elasticsearch fallback)
+		api.GET("/trending", handlers.GetTrending(trendingCounter, searchSvc, modRepo))
+
 		// Media (R2 signed URLs + direct upload helpers)
 		handlers.RegisterMediaRoutes(api, mediaHandler)
 
@@ -412,9 +552,73 @@ func main() {
 			nearbyFanoutHandler := kafka.NewNearbyFanoutHandler(locFollowRepo, notifProducer)
 			go kafka.RunConsumerGroup(consumerCtx, brokers, prefix+"-notif-nearby-fanout", "notification.nearby.fanout", nearbyFanoutHandler.Handle)
 			log.Println("Started notif-nearby-fanout consumer group")
+
+			hashtagFanoutHandler := kafka.NewHashtagFanoutHandler(hashtagRepo, notifProducer)
+			go kafka.RunConsumerGroup(consumerCtx, brokers, prefix+"-notif-hashtag-fanout", "notification.hashtag.fanout", hashtagFanoutHandler.Handle)
+			log.Println("Started notif-hashtag-fanout consumer group")
 		}
 	}
 
+	// Clean up expired ephemeral posts (likes, comments, counters, search documents)
+	sweepCtx, sweepCancel := context.WithCancel(context.Background())
+	sweepInterval := time.Minute
+	if v := os.Getenv("POST_EXPIRY_SWEEP_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			sweepInterval = d
+		} else {
+			slog.Warn("Invalid POST_EXPIRY_SWEEP_INTERVAL, using default", "value", v, "default", sweepInterval)
+		}
+	}
+	if sweepInterval > 0 {
+		go handlers.RunPostExpirySweeper(sweepCtx, postRepo, searchIndexer, sweepInterval)
+		slog.Info("Post expiry sweeper started", "interval", sweepInterval)
+	}
+
+	// Publish scheduled drafts when they are due (the schedule lives in Redis)
+	scheduleCtx, scheduleCancel := context.WithCancel(context.Background())
+	scheduleInterval := 15 * time.Second
+	if v := os.Getenv("SCHEDULED_POST_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			scheduleInterval = d
+		} else {
+			slog.Warn("Invalid SCHEDULED_POST_INTERVAL, using default", "value", v, "default", scheduleInterval)
+		}
+	}
+	if scheduleInterval > 0 && rawRedisClient != nil {
+		go handlers.RunScheduledPostWorker(scheduleCtx, draftRepo, publisher, scheduleInterval)
+		slog.Info("Scheduled post worker started", "interval", scheduleInterval)
+	}
+
+	// Close polls when they are due and tell their voters the results
+	pollCtx, pollCancel := context.WithCancel(context.Background())
+	pollInterval := time.Minute
+	if v := os.Getenv("POLL_CLOSE_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			pollInterval = d
+		} else {
+			slog.Warn("Invalid POLL_CLOSE_INTERVAL, using default", "value", v, "default", pollInterval)
+		}
+	}
+	if pollInterval > 0 {
+		go handlers.RunPollCloser(pollCtx, pollRepo, notifDispatcher, pollInterval)
+		slog.Info("Poll closer started", "interval", pollInterval)
+	}
+
+	// Compute friend suggestions for users queued by reads of missing or stale ones
+	suggestionCtx, suggestionCancel := context.WithCancel(context.Background())
+	suggestionInterval := time.Minute
+	if v := os.Getenv("SUGGESTION_REFRESH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			suggestionInterval = d
+		} else {
+			slog.Warn("Invalid SUGGESTION_REFRESH_INTERVAL, using default", "value", v, "default", suggestionInterval)
+		}
+	}
+	if suggestionInterval > 0 {
+		go handlers.RunSuggestionWorker(suggestionCtx, suggestionRepo, followRepo, locFollowRepo, postRepo, locRepo, modRepo, suggestionInterval)
+		slog.Info("Suggestion worker started", "interval", suggestionInterval)
+	}
+
 	// Start server
 	port := getEnv("PORT", "8080")
 	baseURL := getEnv("BASE_URL", "http://localhost:8080")
@@ -447,6 +651,10 @@ func main() {
 	}
 
 	// Cleanup background resources
+	sweepCancel()
+	scheduleCancel()
+	pollCancel()
+	suggestionCancel()
 	if consumerCancel != nil {
 		consumerCancel()
 	}
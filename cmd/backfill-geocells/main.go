@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/joho/godotenv"
+
+	"social-geo-go/internal/data"
+)
+
+// backfill-geocells copies every post in posts_by_id into the posts_by_geocell
+// partitions (3, 4 and 6-char geohash prefixes) used by radius-aware feed queries.
+func main() {
+	appEnv := os.Getenv("APP_ENV")
+	if appEnv == "" {
+		appEnv = "development"
+	}
+	if err := godotenv.Load(".env." + appEnv); err != nil {
+		log.Printf("No .env.%s file found", appEnv)
+	}
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	ctx := context.Background()
+
+	cassandraPort, err := strconv.Atoi(getEnv("CASSANDRA_PORT", "9042"))
+	if err != nil {
+		log.Fatalf("Invalid CASSANDRA_PORT: %v", err)
+	}
+
+	cluster := gocql.NewCluster(getEnv("CASSANDRA_HOST", "localhost"))
+	cluster.Port = cassandraPort
+	cluster.Keyspace = getEnv("CASSANDRA_KEYSPACE", "geoloc")
+	cluster.Consistency = gocql.Quorum
+	cluster.Timeout = 10 * time.Second
+	cluster.ConnectTimeout = 10 * time.Second
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		log.Fatalf("Failed to connect to Cassandra: %v", err)
+	}
+	defer session.Close()
+
+	var (
+		postID, userID, quotedID gocql.UUID
+		content, geohash         string
+		visibility, locPrecision string
+		ipAddress, ua            string
+		mediaURLs                []string
+		latitude, longitude      float64
+		createdAt, expiresAt     time.Time
+	)
+
+	iter := session.Query(`
+		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, visibility, location_precision, quoted_post_id, ip_address, user_agent, created_at, expires_at
+		FROM posts_by_id
+	`).WithContext(ctx).PageSize(500).Iter()
+
+	var scanned, written, failed int
+
+	for iter.Scan(&postID, &userID, &content, &mediaURLs, &latitude, &longitude, &geohash, &visibility, &locPrecision, &quotedID, &ipAddress, &ua, &createdAt, &expiresAt) {
+		scanned++
+
+		// Posts with a hidden location are kept out of proximity tables
+		if locPrecision == data.LocationPrecisionHidden {
+			mediaURLs, expiresAt, quotedID = nil, time.Time{}, gocql.UUID{}
+			continue
+		}
+
+		// Ephemeral posts keep their remaining lifetime; TTL 0 keeps a row forever
+		ttl := 0
+		var expiresAtCol interface{} = gocql.UnsetValue
+		if !expiresAt.IsZero() {
+			ttl = int(time.Until(expiresAt).Seconds())
+			if ttl <= 0 {
+				mediaURLs, expiresAt, quotedID = nil, time.Time{}, gocql.UUID{}
+				continue
+			}
+			expiresAtCol = expiresAt
+		}
+		var quotedCol interface{} = gocql.UnsetValue
+		if quotedID != (gocql.UUID{}) {
+			quotedCol = quotedID
+		}
+
+		for _, precision := range data.GeocellPrecisions {
+			err := session.Query(`
+				INSERT INTO posts_by_geocell (geohash_prefix, created_at, post_id, user_id, content, media_urls, latitude, longitude, full_geohash, visibility, location_precision, expires_at, quoted_post_id, ip_address, user_agent)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				USING TTL ?
+			`, data.EncodeGeohash(latitude, longitude, precision), createdAt, postID, userID, content, mediaURLs,
+				latitude, longitude, geohash, visibility, locPrecision, expiresAtCol, quotedCol, ipAddress, ua, ttl).WithContext(ctx).Exec()
+			if err != nil {
+				failed++
+				log.Printf("Failed writing geocell row for post %s: %v", postID, err)
+				continue
+			}
+			written++
+		}
+
+		mediaURLs = nil
+		expiresAt = time.Time{}
+		quotedID = gocql.UUID{}
+	}
+
+	if err := iter.Close(); err != nil {
+		log.Fatalf("Failed while scanning posts_by_id: %v", err)
+	}
+
+	log.Printf("Geocell backfill complete: scanned=%d written=%d failed=%d", scanned, written, failed)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/data"
 )
 
 var imageURLs = []string{
@@ -87,24 +89,32 @@ func main() {
 
 		// Get post details for updating other tables
 		var userID gocql.UUID
-		var geohash string
+		var latitude, longitude float64
+		var locationPrecision string
 		var createdAt time.Time
 		err = session.Query(`
-			SELECT user_id, geohash, created_at FROM posts_by_id WHERE post_id = ?
-		`, pid).Scan(&userID, &geohash, &createdAt)
+			SELECT user_id, latitude, longitude, location_precision, created_at FROM posts_by_id WHERE post_id = ?
+		`, pid).Scan(&userID, &latitude, &longitude, &locationPrecision, &createdAt)
 		if err != nil {
 			log.Printf("[%d/%d] Post %s: Failed to get post details: %v", i+1, len(postIDs), pid, err)
 			continue
 		}
 
-		// Update posts_by_geohash
-		geohashPrefix := geohash
-		if len(geohash) >= 5 {
-			geohashPrefix = geohash[:5]
+		// Posts with a hidden location have no proximity rows. The stored
+		// geohash may be coarsened, so partitions come from the coordinates.
+		if locationPrecision != data.LocationPrecisionHidden {
+			// Update posts_by_geohash
+			session.Query(`
+				UPDATE posts_by_geohash SET media_urls = ? WHERE geohash_prefix = ? AND created_at = ? AND post_id = ?
+			`, selectedImages, data.GetGeohashPrefix(latitude, longitude), createdAt, pid).Exec() //nolint:errcheck
+
+			// Update posts_by_geocell
+			for _, precision := range data.GeocellPrecisions {
+				session.Query(`
+					UPDATE posts_by_geocell SET media_urls = ? WHERE geohash_prefix = ? AND created_at = ? AND post_id = ?
+				`, selectedImages, data.EncodeGeohash(latitude, longitude, precision), createdAt, pid).Exec() //nolint:errcheck
+			}
 		}
-		session.Query(`
-			UPDATE posts_by_geohash SET media_urls = ? WHERE geohash_prefix = ? AND created_at = ? AND post_id = ?
-		`, selectedImages, geohashPrefix, createdAt, pid).Exec() //nolint:errcheck
 
 		// Update posts_by_user
 		session.Query(`
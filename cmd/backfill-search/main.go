@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -16,6 +17,10 @@ import (
 )
 
 func main() {
+	reconcile := flag.Bool("reconcile", false, "remove ES documents whose Cassandra rows are gone or no longer public instead of backfilling")
+	interval := flag.Duration("interval", 0, "with -reconcile, repeat every interval (0 = run once)")
+	flag.Parse()
+
 	appEnv := os.Getenv("APP_ENV")
 	if appEnv == "" {
 		appEnv = "development"
@@ -68,6 +73,7 @@ func main() {
 	esClient := search.NewESClient()
 	userRepo := data.NewUserRepository(session)
 	followRepo := data.NewFollowRepository(session)
+	postRepo := data.NewPostRepository(session, nil)
 
 	var rdb *redis.Client
 	redisAddr := fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT"))
@@ -81,6 +87,20 @@ func main() {
 		rdb = nil
 	}
 
+	if *reconcile {
+		for {
+			postsChecked, postsRemoved := reconcilePosts(ctx, session, esClient, postRepo, postsIndex)
+			usersChecked, usersRemoved := reconcileUsers(ctx, session, esClient, rdb, usersIndex)
+			log.Printf("Reconcile complete: posts checked=%d removed=%d; users checked=%d removed=%d",
+				postsChecked, postsRemoved, usersChecked, usersRemoved)
+
+			if *interval <= 0 {
+				return
+			}
+			time.Sleep(*interval)
+		}
+	}
+
 	postsIndexed, postsFailed := backfillPosts(ctx, session, esClient, userRepo, rdb, postsIndex)
 	usersIndexed, usersFailed := backfillUsers(ctx, session, esClient, followRepo, rdb, usersIndex)
 
@@ -97,23 +117,33 @@ func backfillPosts(
 	postsIndex string,
 ) (indexed, failed int) {
 	iter := session.Query(`
-		SELECT post_id, user_id, content, latitude, longitude, geohash, created_at
+		SELECT post_id, user_id, content, latitude, longitude, geohash, visibility, created_at, edited_at
 		FROM posts_by_id
 	`).WithContext(ctx).Iter()
 
 	var (
-		postID    gocql.UUID
-		userID    gocql.UUID
-		content   string
-		latitude  float64
-		longitude float64
-		geohash   string
-		createdAt time.Time
+		postID     gocql.UUID
+		userID     gocql.UUID
+		content    string
+		latitude   float64
+		longitude  float64
+		geohash    string
+		visibility string
+		createdAt  time.Time
+		editedAt   time.Time
 	)
 
-	for iter.Scan(&postID, &userID, &content, &latitude, &longitude, &geohash, &createdAt) {
+	for iter.Scan(&postID, &userID, &content, &latitude, &longitude, &geohash, &visibility, &createdAt, &editedAt) {
+		// Only public posts of public accounts are searchable
+		if visibility != "" && visibility != data.VisibilityPublic {
+			continue
+		}
+
 		username := ""
 		if user, err := userRepo.GetUserByID(ctx, userID.String()); err == nil && user != nil {
+			if user.IsPrivate {
+				continue
+			}
 			username = user.Username
 		}
 
@@ -131,6 +161,10 @@ func backfillPosts(
 		}
 
 		doc := search.PostDocumentFromEvent(event)
+		if !editedAt.IsZero() {
+			edited := editedAt
+			doc.EditedAt = &edited
+		}
 		if err := esClient.IndexDocument(ctx, postsIndex, event.PostID, doc); err != nil {
 			log.Printf("Failed to index post %s: %v", event.PostID, err)
 			failed++
@@ -205,3 +239,209 @@ func backfillUsers(
 	}
 	return indexed, failed
 }
+
+// reconcileBatchSize is how many ES documents are checked against Cassandra per round trip
+const reconcileBatchSize = 100
+
+// reconcilePosts removes post documents whose posts_by_id row no longer exists,
+// or whose post is no longer open to everyone (its visibility was narrowed or
+// its author went private).
+func reconcilePosts(ctx context.Context, session *gocql.Session, esClient *search.ESClient, postRepo *data.PostRepository, postsIndex string) (checked, removed int) {
+	err := scanIndex(ctx, esClient, postsIndex, "post_id", nil, func(hits []indexHit) error {
+		ids := hitIDs(hits)
+		live, err := searchablePostIDs(ctx, session, postRepo, ids)
+		if err != nil {
+			return err
+		}
+
+		stale := missingIDs(ids, live)
+		if err := esClient.BulkDelete(ctx, postsIndex, stale); err != nil {
+			return err
+		}
+
+		checked += len(ids)
+		removed += len(stale)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Post reconciliation stopped early: %v", err)
+	}
+	return checked, removed
+}
+
+// reconcileUsers removes user documents for accounts that are gone or soft-deleted.
+func reconcileUsers(ctx context.Context, session *gocql.Session, esClient *search.ESClient, rdb *redis.Client, usersIndex string) (checked, removed int) {
+	err := scanIndex(ctx, esClient, usersIndex, "user_id", []string{"username"}, func(hits []indexHit) error {
+		live, err := activeUserIDs(ctx, session, hitIDs(hits))
+		if err != nil {
+			return err
+		}
+
+		for _, hit := range hits {
+			if live[hit.ID] {
+				continue
+			}
+			username, _ := hit.Source["username"].(string)
+			event := search.UserDeletedEvent{UserID: hit.ID, Username: username}
+			if err := search.RemoveUserFromEvent(ctx, esClient, rdb, usersIndex, event); err != nil {
+				return err
+			}
+			removed++
+		}
+
+		checked += len(hits)
+		return nil
+	})
+	if err != nil {
+		log.Printf("User reconciliation stopped early: %v", err)
+	}
+	return checked, removed
+}
+
+// indexHit is one document returned while scanning an index
+type indexHit struct {
+	ID     string
+	Source map[string]interface{}
+}
+
+// scanIndex pages through every document in an index, ordered by sortField (a
+// keyword field holding the same value as _id), and hands each page to fn.
+// Only the listed source fields are fetched.
+func scanIndex(ctx context.Context, esClient *search.ESClient, index, sortField string, sourceFields []string, fn func(hits []indexHit) error) error {
+	var source interface{} = false
+	if len(sourceFields) > 0 {
+		source = sourceFields
+	}
+
+	var after string
+	for {
+		query := map[string]interface{}{
+			"size":    reconcileBatchSize,
+			"_source": source,
+			"query":   map[string]interface{}{"match_all": map[string]interface{}{}},
+			"sort":    []interface{}{map[string]interface{}{sortField: "asc"}},
+		}
+		if after != "" {
+			query["search_after"] = []interface{}{after}
+		}
+
+		body, err := esClient.Search(ctx, index, query)
+		if err != nil {
+			return err
+		}
+		resp, err := search.ParseESResponse(body)
+		if err != nil {
+			return err
+		}
+		if len(resp.Hits.Hits) == 0 {
+			return nil
+		}
+
+		hits := make([]indexHit, 0, len(resp.Hits.Hits))
+		for _, hit := range resp.Hits.Hits {
+			hits = append(hits, indexHit{ID: hit.ID, Source: hit.Source})
+		}
+		if err := fn(hits); err != nil {
+			return err
+		}
+
+		after = hits[len(hits)-1].ID
+		if len(hits) < reconcileBatchSize {
+			return nil
+		}
+	}
+}
+
+func hitIDs(hits []indexHit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+// searchablePostIDs marks the posts that still exist and are open to everyone
+func searchablePostIDs(ctx context.Context, session *gocql.Session, postRepo *data.PostRepository, ids []string) (map[string]bool, error) {
+	live := make(map[string]bool, len(ids))
+	uuids := parseUUIDs(ids, live)
+	if len(uuids) == 0 {
+		return live, nil
+	}
+
+	iter := session.Query(`
+		SELECT post_id, user_id, visibility FROM posts_by_id WHERE post_id IN ?
+	`, uuids).WithContext(ctx).Iter()
+
+	var (
+		postID     gocql.UUID
+		userID     gocql.UUID
+		visibility string
+	)
+	var posts []data.Post
+	for iter.Scan(&postID, &userID, &visibility) {
+		posts = append(posts, data.Post{ID: postID.String(), UserID: userID.String(), Visibility: visibility})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to look up posts: %w", err)
+	}
+
+	for i := range posts {
+		open, err := postRepo.IsOpenToEveryone(ctx, &posts[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to check post visibility: %w", err)
+		}
+		if open {
+			live[posts[i].ID] = true
+		}
+	}
+	return live, nil
+}
+
+func activeUserIDs(ctx context.Context, session *gocql.Session, ids []string) (map[string]bool, error) {
+	live := make(map[string]bool, len(ids))
+	uuids := parseUUIDs(ids, live)
+	if len(uuids) == 0 {
+		return live, nil
+	}
+
+	iter := session.Query(`
+		SELECT id, is_deleted FROM users WHERE id IN ?
+	`, uuids).WithContext(ctx).Iter()
+
+	var userID gocql.UUID
+	var isDeleted bool
+	for iter.Scan(&userID, &isDeleted) {
+		if !isDeleted {
+			live[userID.String()] = true
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to look up users: %w", err)
+	}
+	return live, nil
+}
+
+// parseUUIDs converts ids for an IN query. Ids that are not UUIDs cannot be
+// checked and are marked live so they are never deleted by mistake.
+func parseUUIDs(ids []string, live map[string]bool) []gocql.UUID {
+	uuids := make([]gocql.UUID, 0, len(ids))
+	for _, id := range ids {
+		uid, err := gocql.ParseUUID(id)
+		if err != nil {
+			live[id] = true
+			continue
+		}
+		uuids = append(uuids, uid)
+	}
+	return uuids
+}
+
+func missingIDs(ids []string, live map[string]bool) []string {
+	var missing []string
+	for _, id := range ids {
+		if !live[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
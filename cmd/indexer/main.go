@@ -104,19 +104,32 @@ func main() {
 		cancel()
 	}()
 
-	slog.Info("indexer consumers starting", "group", groupID, "topics", []string{"posts.created", "users.indexed"})
+	slog.Info("indexer consumers starting", "group", groupID, "topics", []string{"posts.lifecycle", "users.indexed", "users.deleted"})
 
-	errCh := make(chan error, 2)
+	errCh := make(chan error, 3+len(legacyPostTopics))
 	go func() {
-		errCh <- runConsumer(consumerCtx, brokers, groupID, "posts.created", func(msg kafkago.Message) error {
-			return processPostMessage(consumerCtx, esClient, rdb, msg, postsIndex)
+		errCh <- runConsumer(consumerCtx, brokers, groupID, "posts.lifecycle", func(msg kafkago.Message) error {
+			return processPostLifecycleMessage(consumerCtx, esClient, rdb, msg, postsIndex)
 		})
 	}()
+	// Drain events published to the per-type topics before posts.lifecycle
+	for topic, eventType := range legacyPostTopics {
+		go func() {
+			errCh <- runConsumer(consumerCtx, brokers, groupID, topic, func(msg kafkago.Message) error {
+				return processPostEvent(consumerCtx, esClient, rdb, eventType, msg, postsIndex)
+			})
+		}()
+	}
 	go func() {
 		errCh <- runConsumer(consumerCtx, brokers, groupID, "users.indexed", func(msg kafkago.Message) error {
 			return processUserMessage(consumerCtx, esClient, rdb, msg, usersIndex)
 		})
 	}()
+	go func() {
+		errCh <- runConsumer(consumerCtx, brokers, groupID, "users.deleted", func(msg kafkago.Message) error {
+			return processUserDeleteMessage(consumerCtx, esClient, rdb, msg, usersIndex)
+		})
+	}()
 
 	<-errCh
 }
@@ -170,6 +183,34 @@ func runConsumer(ctx context.Context, brokers []string, groupID, topic string, h
 	}
 }
 
+// legacyPostTopics maps the per-type post topics, which the API no longer
+// writes, to the event type of their messages
+var legacyPostTopics = map[string]string{
+	"posts.created": search.PostEventCreated,
+	"posts.updated": search.PostEventUpdated,
+	"posts.deleted": search.PostEventDeleted,
+}
+
+// processPostLifecycleMessage handles one message of posts.lifecycle. Every
+// event of a post is on the same partition, so a delete is never overtaken by
+// the create before it.
+func processPostLifecycleMessage(ctx context.Context, esClient *search.ESClient, rdb *redis.Client, msg kafkago.Message, postsIndex string) error {
+	return processPostEvent(ctx, esClient, rdb, search.PostEventType(msg), msg, postsIndex)
+}
+
+func processPostEvent(ctx context.Context, esClient *search.ESClient, rdb *redis.Client, eventType string, msg kafkago.Message, postsIndex string) error {
+	switch eventType {
+	case search.PostEventCreated:
+		return processPostMessage(ctx, esClient, rdb, msg, postsIndex)
+	case search.PostEventUpdated:
+		return processPostUpdateMessage(ctx, esClient, msg, postsIndex)
+	case search.PostEventDeleted:
+		return processPostDeleteMessage(ctx, esClient, msg, postsIndex)
+	}
+	slog.Warn("unknown post event type, skipping", "event_type", eventType, "offset", msg.Offset)
+	return nil
+}
+
 func processPostMessage(ctx context.Context, esClient *search.ESClient, rdb *redis.Client, msg kafkago.Message, postsIndex string) error {
 	var event search.PostCreatedEvent
 	if err := json.Unmarshal(msg.Value, &event); err != nil {
@@ -209,6 +250,47 @@ func processPostMessage(ctx context.Context, esClient *search.ESClient, rdb *red
 	return nil
 }
 
+func processPostUpdateMessage(ctx context.Context, esClient *search.ESClient, msg kafkago.Message, postsIndex string) error {
+	var event search.PostUpdatedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("unmarshal event: %w", err)
+	}
+
+	slog.Info("updating post",
+		"post_id", event.PostID,
+		"user_id", event.UserID,
+		"content_length", len(event.Content),
+	)
+
+	if err := esClient.UpdateDocument(ctx, postsIndex, event.PostID, search.PostUpdateFromEvent(event)); err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			// Not indexed (yet); a backfill re-reads the current content from Cassandra
+			slog.Warn("post not in index, skipping update", "post_id", event.PostID)
+			return nil
+		}
+		return fmt.Errorf("update document: %w", err)
+	}
+
+	slog.Info("successfully updated post", "post_id", event.PostID)
+	return nil
+}
+
+func processPostDeleteMessage(ctx context.Context, esClient *search.ESClient, msg kafkago.Message, postsIndex string) error {
+	var event search.PostDeletedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("unmarshal event: %w", err)
+	}
+
+	slog.Info("deleting post", "post_id", event.PostID, "user_id", event.UserID)
+
+	if err := esClient.DeleteDocument(ctx, postsIndex, event.PostID); err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+
+	slog.Info("successfully deleted post", "post_id", event.PostID)
+	return nil
+}
+
 func processUserMessage(ctx context.Context, esClient *search.ESClient, rdb *redis.Client, msg kafkago.Message, usersIndex string) error {
 	var event search.UserIndexedEvent
 	if err := json.Unmarshal(msg.Value, &event); err != nil {
@@ -228,6 +310,22 @@ func processUserMessage(ctx context.Context, esClient *search.ESClient, rdb *red
 	return nil
 }
 
+func processUserDeleteMessage(ctx context.Context, esClient *search.ESClient, rdb *redis.Client, msg kafkago.Message, usersIndex string) error {
+	var event search.UserDeletedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("unmarshal event: %w", err)
+	}
+
+	slog.Info("deleting user", "user_id", event.UserID)
+
+	if err := search.RemoveUserFromEvent(ctx, esClient, rdb, usersIndex, event); err != nil {
+		return fmt.Errorf("remove user: %w", err)
+	}
+
+	slog.Info("successfully deleted user", "user_id", event.UserID)
+	return nil
+}
+
 func backoffSleep(ctx context.Context) {
 	timer := time.NewTimer(1 * time.Second)
 	defer timer.Stop()
@@ -8,6 +8,8 @@ import (
 
 	"github.com/gocql/gocql"
 	"github.com/mmcloughlin/geohash"
+
+	"social-geo-go/internal/data"
 )
 
 // Test location: Jakarta, Indonesia (06°21′55″S 106°49′37″E)
@@ -129,6 +131,18 @@ func main() {
 				log.Printf("Failed to insert post_by_geohash: %v", err)
 			}
 
+			// Insert into posts_by_geocell
+			for _, precision := range data.GeocellPrecisions {
+				err = session.Query(`
+					INSERT INTO posts_by_geocell (geohash_prefix, created_at, post_id, user_id, content, latitude, longitude, full_geohash)
+					VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+					data.EncodeGeohash(lat, lng, precision), createdAt, postID, userID, content, lat, lng, fullGeohash,
+				).Exec()
+				if err != nil {
+					log.Printf("Failed to insert post_by_geocell: %v", err)
+				}
+			}
+
 			// Insert into posts_by_id
 			err = session.Query(`
 				INSERT INTO posts_by_id (post_id, user_id, content, latitude, longitude, geohash, created_at)
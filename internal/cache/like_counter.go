@@ -127,8 +127,84 @@ func (lc *LikeCounter) SetLikeCount(ctx context.Context, targetType, targetID st
 	return lc.client.Set(ctx, key, count, 0).Err()
 }
 
-// DeleteLikeCount removes the like count key (for cleanup/testing)
+// DeleteLikeCount removes the like and reaction count keys (for cleanup/testing)
 func (lc *LikeCounter) DeleteLikeCount(ctx context.Context, targetType, targetID string) error {
-	key := likeCountKey(targetType, targetID)
-	return lc.client.Del(ctx, key).Err()
+	return lc.client.Del(ctx, likeCountKey(targetType, targetID), reactionCountKey(targetType, targetID)).Err()
+}
+
+// reactionCountKey generates the Redis key for a target's reaction counts.
+// Likes (the ❤️ reaction) keep their own like_count key.
+func reactionCountKey(targetType, targetID string) string {
+	return fmt.Sprintf("reaction_counts:%s:%s", targetType, targetID)
+}
+
+// IncrementReactionCount atomically increments the count of one reaction on a target
+func (lc *LikeCounter) IncrementReactionCount(ctx context.Context, targetType, targetID, reaction string) error {
+	if err := lc.client.HIncrBy(ctx, reactionCountKey(targetType, targetID), reaction, 1).Err(); err != nil {
+		return fmt.Errorf("failed to increment reaction count: %w", err)
+	}
+	return nil
+}
+
+// DecrementReactionCount atomically decrements the count of one reaction on a
+// target (minimum 0)
+func (lc *LikeCounter) DecrementReactionCount(ctx context.Context, targetType, targetID, reaction string) error {
+	script := redis.NewScript(`
+		local current = redis.call('HGET', KEYS[1], ARGV[1])
+		if current == false or tonumber(current) <= 1 then
+			redis.call('HDEL', KEYS[1], ARGV[1])
+			return 0
+		end
+		return redis.call('HINCRBY', KEYS[1], ARGV[1], -1)
+	`)
+
+	if err := script.Run(ctx, lc.client, []string{reactionCountKey(targetType, targetID)}, reaction).Err(); err != nil {
+		return fmt.Errorf("failed to decrement reaction count: %w", err)
+	}
+	return nil
+}
+
+// GetReactionCountsBatch retrieves the reaction counts of several targets in
+// a single round-trip. Only reactions other than likes are included.
+func (lc *LikeCounter) GetReactionCountsBatch(ctx context.Context, targetType string, targetIDs []string) (map[string]map[string]int64, error) {
+	if len(targetIDs) == 0 {
+		return make(map[string]map[string]int64), nil
+	}
+
+	pipe := lc.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(targetIDs))
+	for i, id := range targetIDs {
+		cmds[i] = pipe.HGetAll(ctx, reactionCountKey(targetType, id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to batch get reaction counts: %w", err)
+	}
+
+	counts := make(map[string]map[string]int64, len(targetIDs))
+	for i, id := range targetIDs {
+		targetCounts := make(map[string]int64)
+		for reaction, v := range cmds[i].Val() {
+			if count, err := strconv.ParseInt(v, 10, 64); err == nil && count > 0 {
+				targetCounts[reaction] = count
+			}
+		}
+		counts[id] = targetCounts
+	}
+	return counts, nil
+}
+
+// SetReactionCounts replaces the reaction counts of a target (useful for
+// initialization/recovery)
+func (lc *LikeCounter) SetReactionCounts(ctx context.Context, targetType, targetID string, counts map[string]int64) error {
+	key := reactionCountKey(targetType, targetID)
+
+	pipe := lc.client.TxPipeline()
+	pipe.Del(ctx, key)
+	for reaction, count := range counts {
+		if count > 0 {
+			pipe.HSet(ctx, key, reaction, count)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
 }
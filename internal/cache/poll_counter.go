@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PollCounter handles Redis-based poll vote tallies. Each poll is a hash of
+// option index to vote count.
+type PollCounter struct {
+	client *redis.Client
+}
+
+// NewPollCounter creates a new PollCounter with the given Redis client
+func NewPollCounter(redisClient *RedisClient) *PollCounter {
+	return &PollCounter{client: redisClient.Client()}
+}
+
+// pollCountKey generates the Redis key for a poll's tallies
+func pollCountKey(postID string) string {
+	return fmt.Sprintf("poll_votes:%s", postID)
+}
+
+// IncrementVoteCount atomically adds a vote for an option of a poll. The
+// tallies are kept until expireAt. Returns the option's new count.
+func (pc *PollCounter) IncrementVoteCount(ctx context.Context, postID string, option int, expireAt time.Time) (int64, error) {
+	key := pollCountKey(postID)
+
+	pipe := pc.client.TxPipeline()
+	incr := pipe.HIncrBy(ctx, key, strconv.Itoa(option), 1)
+	pipe.ExpireAt(ctx, key, expireAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to increment poll vote count: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// GetVoteCountsBatch retrieves the tallies of several polls in a single
+// round-trip. Each poll's counts are indexed by option; options without
+// votes are 0.
+func (pc *PollCounter) GetVoteCountsBatch(ctx context.Context, postIDs []string, optionCounts map[string]int) (map[string][]int64, error) {
+	if len(postIDs) == 0 {
+		return make(map[string][]int64), nil
+	}
+
+	pipe := pc.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(postIDs))
+	for i, id := range postIDs {
+		cmds[i] = pipe.HGetAll(ctx, pollCountKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to batch get poll vote counts: %w", err)
+	}
+
+	counts := make(map[string][]int64, len(postIDs))
+	for i, id := range postIDs {
+		tally := make([]int64, optionCounts[id])
+		for field, value := range cmds[i].Val() {
+			option, err := strconv.Atoi(field)
+			if err != nil || option < 0 || option >= len(tally) {
+				continue
+			}
+			tally[option], _ = strconv.ParseInt(value, 10, 64)
+		}
+		counts[id] = tally
+	}
+	return counts, nil
+}
+
+// SetVoteCounts replaces a poll's tallies, kept until expireAt (useful for
+// initialization/recovery)
+func (pc *PollCounter) SetVoteCounts(ctx context.Context, postID string, counts []int64, expireAt time.Time) error {
+	key := pollCountKey(postID)
+	values := make(map[string]interface{}, len(counts))
+	for option, count := range counts {
+		values[strconv.Itoa(option)] = count
+	}
+
+	pipe := pc.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(values) > 0 {
+		pipe.HSet(ctx, key, values)
+		pipe.ExpireAt(ctx, key, expireAt)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeleteVoteCounts removes a poll's tallies, once its final results are stored
+func (pc *PollCounter) DeleteVoteCounts(ctx context.Context, postID string) error {
+	return pc.client.Del(ctx, pollCountKey(postID)).Err()
+}
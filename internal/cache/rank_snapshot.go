@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rankSnapshotTTL is how long the scores of a ranked listing are kept after
+// its first page. Pages loaded later are ranked by live counts again.
+const rankSnapshotTTL = time.Hour
+
+// RankSnapshots keeps the scores a ranked listing (the hot feed, top comments)
+// gave its items on the first page, so later pages order the same items the
+// same way while likes and comments keep arriving. Each snapshot is a hash of
+// item ID to score.
+type RankSnapshots struct {
+	client *redis.Client
+}
+
+// NewRankSnapshots creates a new RankSnapshots with the given Redis client
+func NewRankSnapshots(redisClient *RedisClient) *RankSnapshots {
+	return &RankSnapshots{client: redisClient.Client()}
+}
+
+// rankSnapshotKey generates the Redis key for a ranked listing's scores
+func rankSnapshotKey(key string) string {
+	return "rank_snapshot:" + key
+}
+
+// SaveScores stores the scores of a ranked listing under key
+func (rs *RankSnapshots) SaveScores(ctx context.Context, key string, scores map[string]float64) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(scores))
+	for id, score := range scores {
+		// The shortest exact form, so a score read back compares equal to the
+		// one in the cursor
+		values[id] = strconv.FormatFloat(score, 'g', -1, 64)
+	}
+
+	redisKey := rankSnapshotKey(key)
+	pipe := rs.client.Pipeline()
+	pipe.HSet(ctx, redisKey, values)
+	pipe.Expire(ctx, redisKey, rankSnapshotTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save rank snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetScores returns the scores stored under key, or nil if there are none
+func (rs *RankSnapshots) GetScores(ctx context.Context, key string) (map[string]float64, error) {
+	values, err := rs.client.HGetAll(ctx, rankSnapshotKey(key)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rank snapshot: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	scores := make(map[string]float64, len(values))
+	for id, value := range values {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rank snapshot score for %s: %w", id, err)
+		}
+		scores[id] = score
+	}
+	return scores, nil
+}
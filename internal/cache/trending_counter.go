@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Trending counts are kept well past the windows read from them, so the
+// windows can be widened without losing history
+const (
+	trendingHourRetention = 48 * time.Hour
+	trendingDayRetention  = 31 * 24 * time.Hour
+)
+
+// TrendingCounter handles Redis-based hashtag usage counts per geohash cell.
+// Each cell has a hash of hashtag to post count for every hour and every
+// day, which readers combine into sliding windows.
+type TrendingCounter struct {
+	client *redis.Client
+}
+
+// NewTrendingCounter creates a new TrendingCounter with the given Redis client
+func NewTrendingCounter(redisClient *RedisClient) *TrendingCounter {
+	return &TrendingCounter{client: redisClient.Client()}
+}
+
+// trendingHourKey generates the Redis key for a cell's counts in one hour
+func trendingHourKey(cell string, hour time.Time) string {
+	return fmt.Sprintf("trending:h:%s:%s", cell, hour.UTC().Format("2006010215"))
+}
+
+// trendingDayKey generates the Redis key for a cell's counts in one day
+func trendingDayKey(cell string, day time.Time) string {
+	return fmt.Sprintf("trending:d:%s:%s", cell, day.UTC().Format("20060102"))
+}
+
+// RecordHashtags counts a post using tags, created at the given time, in
+// each of cells
+func (tc *TrendingCounter) RecordHashtags(ctx context.Context, cells, tags []string, at time.Time) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	hour := at.UTC().Truncate(time.Hour)
+	day := at.UTC().Truncate(24 * time.Hour)
+
+	pipe := tc.client.Pipeline()
+	for _, cell := range cells {
+		hourKey := trendingHourKey(cell, hour)
+		dayKey := trendingDayKey(cell, day)
+		for _, tag := range tags {
+			pipe.HIncrBy(ctx, hourKey, tag, 1)
+			pipe.HIncrBy(ctx, dayKey, tag, 1)
+		}
+		pipe.ExpireAt(ctx, hourKey, hour.Add(trendingHourRetention))
+		pipe.ExpireAt(ctx, dayKey, day.Add(trendingDayRetention))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record trending hashtags: %w", err)
+	}
+	return nil
+}
+
+// GetHashtagCounts sums the hashtag counts of cells in a single round-trip.
+// recent holds each hashtag's counts for the last recentHours hours, newest
+// (the current hour) first; baseline its total over the baselineDays days
+// before today.
+func (tc *TrendingCounter) GetHashtagCounts(ctx context.Context, cells []string, now time.Time, recentHours, baselineDays int) (recent map[string][]int64, baseline map[string]int64, err error) {
+	hour := now.UTC().Truncate(time.Hour)
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	pipe := tc.client.Pipeline()
+	hourCmds := make([][]*redis.MapStringStringCmd, recentHours)
+	dayCmds := make([]*redis.MapStringStringCmd, 0, len(cells)*baselineDays)
+	for i := 0; i < recentHours; i++ {
+		for _, cell := range cells {
+			hourCmds[i] = append(hourCmds[i], pipe.HGetAll(ctx, trendingHourKey(cell, hour.Add(-time.Duration(i)*time.Hour))))
+		}
+	}
+	for d := 1; d <= baselineDays; d++ {
+		for _, cell := range cells {
+			dayCmds = append(dayCmds, pipe.HGetAll(ctx, trendingDayKey(cell, today.AddDate(0, 0, -d))))
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, nil, fmt.Errorf("failed to get trending hashtag counts: %w", err)
+	}
+
+	recent = make(map[string][]int64)
+	for i, cmds := range hourCmds {
+		for _, cmd := range cmds {
+			for tag, v := range cmd.Val() {
+				count, _ := strconv.ParseInt(v, 10, 64)
+				if recent[tag] == nil {
+					recent[tag] = make([]int64, recentHours)
+				}
+				recent[tag][i] += count
+			}
+		}
+	}
+
+	baseline = make(map[string]int64)
+	for _, cmd := range dayCmds {
+		for tag, v := range cmd.Val() {
+			// Only hashtags used recently can trend
+			if recent[tag] == nil {
+				continue
+			}
+			count, _ := strconv.ParseInt(v, 10, 64)
+			baseline[tag] += count
+		}
+	}
+	return recent, baseline, nil
+}
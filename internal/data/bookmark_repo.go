@@ -0,0 +1,512 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gocql/gocql"
+)
+
+// Bookmark limits
+const (
+	MaxBookmarkCollections          = 50
+	MaxBookmarkCollectionNameLength = 50
+)
+
+// BookmarkCollection is a named group of a user's bookmarks
+type BookmarkCollection struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BookmarkCollectionRequest represents the request body for creating or
+// renaming a bookmark collection
+type BookmarkCollectionRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// BookmarkRequest represents the optional request body for bookmarking a post
+type BookmarkRequest struct {
+	CollectionID string `json:"collection_id"` // Empty saves the post outside any collection
+}
+
+// BookmarkResult represents the state of a user's bookmark after a change
+type BookmarkResult struct {
+	IsBookmarked bool   `json:"is_bookmarked"`
+	CollectionID string `json:"collection_id,omitempty"`
+	Changed      bool   `json:"changed"` // Whether the state actually changed
+}
+
+// BookmarkRepository stores users' bookmarks and bookmark collections.
+// Bookmarks are private: they are only ever returned to their owner.
+type BookmarkRepository struct {
+	session *gocql.Session
+}
+
+// NewBookmarkRepository creates a new BookmarkRepository
+func NewBookmarkRepository(session *gocql.Session) *BookmarkRepository {
+	return &BookmarkRepository{session: session}
+}
+
+// Bookmark saves a post for userID, in collectionID if it is set. Bookmarking
+// a saved post again moves it to collectionID, keeping the time it was first
+// saved, so retries are safe. Callers check the post may be viewed first.
+func (r *BookmarkRepository) Bookmark(ctx context.Context, userIDStr, postIDStr, collectionIDStr string) (*BookmarkResult, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	var collection interface{} = gocql.UnsetValue
+	if collectionIDStr != "" {
+		if _, err := r.GetCollection(ctx, userIDStr, collectionIDStr); err != nil {
+			return nil, err
+		}
+		collectionID, _ := gocql.ParseUUID(collectionIDStr)
+		collection = collectionID
+	}
+
+	now := time.Now()
+	existing := make(map[string]interface{})
+	applied, err := r.session.Query(`
+		INSERT INTO bookmark_state (user_id, post_id, collection_id, created_at)
+		VALUES (?, ?, ?, ?)
+		IF NOT EXISTS
+	`, userID, postID, collection, now).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bookmark post: %w", err)
+	}
+
+	if applied {
+		batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		batch.Query(`
+			INSERT INTO bookmarks_by_user (user_id, created_at, post_id) VALUES (?, ?, ?)
+		`, userID, now, postID)
+		if collectionIDStr != "" {
+			batch.Query(`
+				INSERT INTO bookmarks_by_collection (user_id, collection_id, created_at, post_id)
+				VALUES (?, ?, ?, ?)
+			`, userID, collection, now, postID)
+		}
+		if err := r.session.ExecuteBatch(batch); err != nil {
+			return nil, fmt.Errorf("failed to bookmark post: %w", err)
+		}
+		return &BookmarkResult{IsBookmarked: true, CollectionID: collectionIDStr, Changed: true}, nil
+	}
+
+	savedAt, _ := existing["created_at"].(time.Time)
+	current := bookmarkCollectionID(existing["collection_id"])
+	if current == collectionIDStr {
+		return &BookmarkResult{IsBookmarked: true, CollectionID: current}, nil
+	}
+
+	if err := r.moveBookmark(ctx, userID, postID, savedAt, current, collectionIDStr); err != nil {
+		return nil, err
+	}
+	return &BookmarkResult{IsBookmarked: true, CollectionID: collectionIDStr, Changed: true}, nil
+}
+
+// moveBookmark moves a saved post from one collection to another, either of
+// which may be none
+func (r *BookmarkRepository) moveBookmark(ctx context.Context, userID, postID gocql.UUID, savedAt time.Time, from, to string) error {
+	// The condition keeps a concurrent unbookmark from being undone
+	var target interface{}
+	if to != "" {
+		target, _ = gocql.ParseUUID(to)
+	}
+	applied, err := r.session.Query(`
+		UPDATE bookmark_state SET collection_id = ?
+		WHERE user_id = ? AND post_id = ?
+		IF created_at = ?
+	`, target, userID, postID, savedAt).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to move bookmark: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("failed to move bookmark: it was changed concurrently")
+	}
+
+	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	if from != "" {
+		fromID, _ := gocql.ParseUUID(from)
+		batch.Query(`
+			DELETE FROM bookmarks_by_collection
+			WHERE user_id = ? AND collection_id = ? AND created_at = ? AND post_id = ?
+		`, userID, fromID, savedAt, postID)
+	}
+	if to != "" {
+		batch.Query(`
+			INSERT INTO bookmarks_by_collection (user_id, collection_id, created_at, post_id)
+			VALUES (?, ?, ?, ?)
+		`, userID, target, savedAt, postID)
+	}
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to move bookmark: %w", err)
+	}
+	return nil
+}
+
+// Unbookmark removes userID's bookmark of a post, from its collection too.
+// Removing a bookmark that does not exist is a no-op.
+func (r *BookmarkRepository) Unbookmark(ctx context.Context, userIDStr, postIDStr string) (*BookmarkResult, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	var savedAt time.Time
+	var collectionID gocql.UUID
+	err = r.session.Query(`
+		SELECT created_at, collection_id FROM bookmark_state
+		WHERE user_id = ? AND post_id = ?
+	`, userID, postID).WithContext(ctx).Scan(&savedAt, &collectionID)
+	if err == gocql.ErrNotFound {
+		return &BookmarkResult{IsBookmarked: false}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bookmark state: %w", err)
+	}
+
+	applied, err := r.session.Query(`
+		DELETE FROM bookmark_state WHERE user_id = ? AND post_id = ? IF EXISTS
+	`, userID, postID).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	if !applied {
+		return &BookmarkResult{IsBookmarked: false}, nil
+	}
+
+	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`
+		DELETE FROM bookmarks_by_user WHERE user_id = ? AND created_at = ? AND post_id = ?
+	`, userID, savedAt, postID)
+	if current := bookmarkCollectionID(collectionID); current != "" {
+		batch.Query(`
+			DELETE FROM bookmarks_by_collection
+			WHERE user_id = ? AND collection_id = ? AND created_at = ? AND post_id = ?
+		`, userID, collectionID, savedAt, postID)
+	}
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+
+	return &BookmarkResult{IsBookmarked: false, Changed: true}, nil
+}
+
+// RemoveBookmarks removes userID's bookmarks of posts, such as posts found
+// deleted while listing bookmarks
+func (r *BookmarkRepository) RemoveBookmarks(ctx context.Context, userID string, postIDs []string) error {
+	for _, postID := range postIDs {
+		if _, err := r.Unbookmark(ctx, userID, postID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBookmarkKeys returns the posts userID has bookmarked, in collectionID if
+// it is set, most recently saved first, as keysets of (saved at, post ID) so
+// they can back a cursor. Results start after the given keyset.
+func (r *BookmarkRepository) GetBookmarkKeys(ctx context.Context, userIDStr, collectionIDStr string, limit int, after Keyset) ([]Keyset, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	stmt := `SELECT created_at, post_id FROM bookmarks_by_user WHERE user_id = ?`
+	args := []interface{}{userID}
+	if collectionIDStr != "" {
+		if _, err := r.GetCollection(ctx, userIDStr, collectionIDStr); err != nil {
+			return nil, err
+		}
+		collectionID, _ := gocql.ParseUUID(collectionIDStr)
+		stmt = `SELECT created_at, post_id FROM bookmarks_by_collection WHERE user_id = ? AND collection_id = ?`
+		args = append(args, collectionID)
+	}
+	if !after.IsZero() {
+		stmt += ` AND created_at <= ?`
+		args = append(args, after.CreatedAt)
+	}
+
+	iter := r.session.Query(stmt, args...).WithContext(ctx).PageSize(limit * 2).Iter()
+
+	var keys []Keyset
+	var savedAt time.Time
+	var postID gocql.UUID
+	for iter.Scan(&savedAt, &postID) {
+		if len(keys) > 0 && keysetScanDone(len(keys), limit, keys[len(keys)-1].CreatedAt, savedAt) {
+			break
+		}
+		if !after.Admits(savedAt, postID.String()) {
+			continue
+		}
+		keys = append(keys, Keyset{CreatedAt: savedAt, ID: postID.String()})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating bookmarks: %w", err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return KeysetBefore(keys[i].CreatedAt, keys[i].ID, keys[j].CreatedAt, keys[j].ID)
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	return keys, nil
+}
+
+// GetBookmarkedPosts returns which of postIDs userID has bookmarked
+func (r *BookmarkRepository) GetBookmarkedPosts(ctx context.Context, postIDs []string, userIDStr string) (map[string]bool, error) {
+	result := make(map[string]bool)
+
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return result, nil
+	}
+
+	ids := make([]gocql.UUID, 0, len(postIDs))
+	for _, id := range postIDs {
+		if pid, err := gocql.ParseUUID(id); err == nil {
+			ids = append(ids, pid)
+		}
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	iter := r.session.Query(`
+		SELECT post_id FROM bookmark_state WHERE user_id = ? AND post_id IN ?
+	`, userID, ids).WithContext(ctx).Iter()
+	var postID gocql.UUID
+	for iter.Scan(&postID) {
+		result[postID.String()] = true
+	}
+	if err := iter.Close(); err != nil {
+		return result, fmt.Errorf("failed to get bookmark state: %w", err)
+	}
+
+	return result, nil
+}
+
+// CreateCollection adds a bookmark collection for userID
+func (r *BookmarkRepository) CreateCollection(ctx context.Context, userIDStr, name string) (*BookmarkCollection, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	name, err = validateCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := r.GetCollections(ctx, userIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(collections) >= MaxBookmarkCollections {
+		return nil, fmt.Errorf("too many bookmark collections: the limit is %d", MaxBookmarkCollections)
+	}
+	if err := checkCollectionNameFree(collections, name, ""); err != nil {
+		return nil, err
+	}
+
+	collectionID := gocql.TimeUUID()
+	now := time.Now()
+	if err := r.session.Query(`
+		INSERT INTO bookmark_collections (user_id, collection_id, name, created_at)
+		VALUES (?, ?, ?, ?)
+	`, userID, collectionID, name, now).WithContext(ctx).Exec(); err != nil {
+		return nil, fmt.Errorf("failed to create bookmark collection: %w", err)
+	}
+
+	return &BookmarkCollection{ID: collectionID.String(), Name: name, CreatedAt: now}, nil
+}
+
+// GetCollections returns userID's bookmark collections, oldest first
+func (r *BookmarkRepository) GetCollections(ctx context.Context, userIDStr string) ([]BookmarkCollection, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT collection_id, name, created_at FROM bookmark_collections WHERE user_id = ?
+	`, userID).WithContext(ctx).Iter()
+
+	var collections []BookmarkCollection
+	var collection BookmarkCollection
+	var collectionID gocql.UUID
+	for iter.Scan(&collectionID, &collection.Name, &collection.CreatedAt) {
+		collection.ID = collectionID.String()
+		collections = append(collections, collection)
+		collection = BookmarkCollection{}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get bookmark collections: %w", err)
+	}
+
+	return collections, nil
+}
+
+// GetCollection returns one of userID's bookmark collections
+func (r *BookmarkRepository) GetCollection(ctx context.Context, userIDStr, collectionIDStr string) (*BookmarkCollection, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	collectionID, err := gocql.ParseUUID(collectionIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collection_id: %w", err)
+	}
+
+	collection := BookmarkCollection{ID: collectionIDStr}
+	err = r.session.Query(`
+		SELECT name, created_at FROM bookmark_collections WHERE user_id = ? AND collection_id = ?
+	`, userID, collectionID).WithContext(ctx).Scan(&collection.Name, &collection.CreatedAt)
+	if err == gocql.ErrNotFound {
+		return nil, fmt.Errorf("bookmark collection not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmark collection: %w", err)
+	}
+	return &collection, nil
+}
+
+// RenameCollection renames one of userID's bookmark collections
+func (r *BookmarkRepository) RenameCollection(ctx context.Context, userIDStr, collectionIDStr, name string) (*BookmarkCollection, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	collectionID, err := gocql.ParseUUID(collectionIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collection_id: %w", err)
+	}
+	name, err = validateCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := r.GetCollections(ctx, userIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCollectionNameFree(collections, name, collectionIDStr); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]interface{})
+	applied, err := r.session.Query(`
+		UPDATE bookmark_collections SET name = ?
+		WHERE user_id = ? AND collection_id = ?
+		IF EXISTS
+	`, name, userID, collectionID).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename bookmark collection: %w", err)
+	}
+	if !applied {
+		return nil, fmt.Errorf("bookmark collection not found")
+	}
+
+	return r.GetCollection(ctx, userIDStr, collectionIDStr)
+}
+
+// DeleteCollection removes one of userID's bookmark collections. The posts in
+// it stay bookmarked, outside any collection.
+func (r *BookmarkRepository) DeleteCollection(ctx context.Context, userIDStr, collectionIDStr string) error {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	collectionID, err := gocql.ParseUUID(collectionIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid collection_id: %w", err)
+	}
+
+	applied, err := r.session.Query(`
+		DELETE FROM bookmark_collections WHERE user_id = ? AND collection_id = ? IF EXISTS
+	`, userID, collectionID).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark collection: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("bookmark collection not found")
+	}
+
+	// Detach the collection's bookmarks. The condition skips bookmarks removed
+	// or moved meanwhile, and never recreates a removed one.
+	iter := r.session.Query(`
+		SELECT post_id FROM bookmarks_by_collection WHERE user_id = ? AND collection_id = ?
+	`, userID, collectionID).WithContext(ctx).Iter()
+	var postID gocql.UUID
+	for iter.Scan(&postID) {
+		if _, err := r.session.Query(`
+			UPDATE bookmark_state SET collection_id = null
+			WHERE user_id = ? AND post_id = ?
+			IF collection_id = ?
+		`, userID, postID, collectionID).WithContext(ctx).MapScanCAS(map[string]interface{}{}); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to detach bookmark: %w", err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to detach bookmarks: %w", err)
+	}
+
+	if err := r.session.Query(`
+		DELETE FROM bookmarks_by_collection WHERE user_id = ? AND collection_id = ?
+	`, userID, collectionID).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to delete bookmark collection: %w", err)
+	}
+	return nil
+}
+
+// validateCollectionName trims a collection name and checks its length
+func validateCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxBookmarkCollectionNameLength {
+		return "", fmt.Errorf("invalid name: must be 1-%d characters", MaxBookmarkCollectionNameLength)
+	}
+	return name, nil
+}
+
+// checkCollectionNameFree rejects a name already used, ignoring case, by a
+// collection other than exceptID
+func checkCollectionNameFree(collections []BookmarkCollection, name, exceptID string) error {
+	for _, c := range collections {
+		if c.ID != exceptID && strings.EqualFold(c.Name, name) {
+			return fmt.Errorf("bookmark collection %q already exists", name)
+		}
+	}
+	return nil
+}
+
+// bookmarkCollectionID returns a bookmark_state collection_id value as a
+// string, empty when the bookmark is in no collection
+func bookmarkCollectionID(v interface{}) string {
+	id, ok := v.(gocql.UUID)
+	if !ok || id == (gocql.UUID{}) {
+		return ""
+	}
+	return id.String()
+}
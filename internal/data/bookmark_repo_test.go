@@ -0,0 +1,106 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarkRepository_Integration(t *testing.T) {
+	repo := NewBookmarkRepository(testSession)
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	postID := uuid.New().String()
+	olderPostID := uuid.New().String()
+
+	t.Run("Bookmark Once", func(t *testing.T) {
+		result, err := repo.Bookmark(ctx, userID, olderPostID, "")
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		time.Sleep(2 * time.Millisecond)
+
+		result, err = repo.Bookmark(ctx, userID, postID, "")
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+
+		result, err = repo.Bookmark(ctx, userID, postID, "")
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+
+		keys, err := repo.GetBookmarkKeys(ctx, userID, "", 10, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, postID, keys[0].ID)
+		assert.Equal(t, olderPostID, keys[1].ID)
+
+		// Paging continues after the cursor
+		keys, err = repo.GetBookmarkKeys(ctx, userID, "", 10, keys[0])
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, olderPostID, keys[0].ID)
+	})
+
+	t.Run("Collections", func(t *testing.T) {
+		collection, err := repo.CreateCollection(ctx, userID, "  Trip ideas ")
+		require.NoError(t, err)
+		assert.Equal(t, "Trip ideas", collection.Name)
+
+		_, err = repo.CreateCollection(ctx, userID, "TRIP IDEAS")
+		assert.ErrorContains(t, err, "already exists")
+
+		// Moving a saved post keeps its place among all bookmarks
+		result, err := repo.Bookmark(ctx, userID, olderPostID, collection.ID)
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, collection.ID, result.CollectionID)
+
+		keys, err := repo.GetBookmarkKeys(ctx, userID, collection.ID, 10, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, olderPostID, keys[0].ID)
+
+		renamed, err := repo.RenameCollection(ctx, userID, collection.ID, "Holiday")
+		require.NoError(t, err)
+		assert.Equal(t, "Holiday", renamed.Name)
+
+		// Deleting a collection keeps its posts bookmarked
+		require.NoError(t, repo.DeleteCollection(ctx, userID, collection.ID))
+		_, err = repo.GetBookmarkKeys(ctx, userID, collection.ID, 10, Keyset{})
+		assert.ErrorContains(t, err, "not found")
+
+		keys, err = repo.GetBookmarkKeys(ctx, userID, "", 10, Keyset{})
+		require.NoError(t, err)
+		assert.Len(t, keys, 2)
+
+		result, err = repo.Bookmark(ctx, userID, olderPostID, "")
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+	})
+
+	t.Run("Unbookmark", func(t *testing.T) {
+		bookmarked, err := repo.GetBookmarkedPosts(ctx, []string{postID, olderPostID}, userID)
+		require.NoError(t, err)
+		assert.True(t, bookmarked[postID])
+		assert.True(t, bookmarked[olderPostID])
+
+		require.NoError(t, repo.RemoveBookmarks(ctx, userID, []string{postID}))
+
+		result, err := repo.Unbookmark(ctx, userID, postID)
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+
+		keys, err := repo.GetBookmarkKeys(ctx, userID, "", 10, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, olderPostID, keys[0].ID)
+
+		bookmarked, err = repo.GetBookmarkedPosts(ctx, []string{postID, olderPostID}, userID)
+		require.NoError(t, err)
+		assert.False(t, bookmarked[postID])
+	})
+}
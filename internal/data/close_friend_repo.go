@@ -0,0 +1,85 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// CloseFriendRepository manages each user's close friends list, the audience of
+// their close_friends posts
+type CloseFriendRepository struct {
+	session *gocql.Session
+}
+
+// NewCloseFriendRepository creates a new CloseFriendRepository
+func NewCloseFriendRepository(session *gocql.Session) *CloseFriendRepository {
+	return &CloseFriendRepository{session: session}
+}
+
+// AddCloseFriend adds friendID to userID's close friends
+func (r *CloseFriendRepository) AddCloseFriend(ctx context.Context, userID, friendID string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	fid, err := gocql.ParseUUID(friendID)
+	if err != nil {
+		return fmt.Errorf("invalid friend_id: %w", err)
+	}
+	if userID == friendID {
+		return fmt.Errorf("cannot add yourself as a close friend")
+	}
+
+	if err := r.session.Query(`
+		INSERT INTO close_friends (user_id, friend_id, added_at) VALUES (?, ?, ?)
+	`, uid, fid, time.Now()).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to add close friend: %w", err)
+	}
+	return nil
+}
+
+// RemoveCloseFriend removes friendID from userID's close friends
+func (r *CloseFriendRepository) RemoveCloseFriend(ctx context.Context, userID, friendID string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	fid, err := gocql.ParseUUID(friendID)
+	if err != nil {
+		return fmt.Errorf("invalid friend_id: %w", err)
+	}
+
+	if err := r.session.Query(`
+		DELETE FROM close_friends WHERE user_id = ? AND friend_id = ?
+	`, uid, fid).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to remove close friend: %w", err)
+	}
+	return nil
+}
+
+// GetCloseFriends returns the IDs on userID's close friends list
+func (r *CloseFriendRepository) GetCloseFriends(ctx context.Context, userID string) ([]string, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT friend_id FROM close_friends WHERE user_id = ?
+	`, uid).WithContext(ctx).Iter()
+
+	var friends []string
+	var friendID gocql.UUID
+	for iter.Scan(&friendID) {
+		friends = append(friends, friendID.String())
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return friends, nil
+}
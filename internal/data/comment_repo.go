@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -143,6 +144,9 @@ func (r *CommentRepository) EditComment(ctx context.Context, commentIDStr, userI
 	batch := r.session.NewBatch(gocql.LoggedBatch)
 	batch.WithContext(ctx)
 
+	// Keep the version being replaced
+	addCommentRevision(batch, cid, comment.UserID, comment.Content, CommentRevisionEdit, now)
+
 	// Update comments_by_id
 	batch.Query(`
 		UPDATE comments_by_id SET content = ?, updated_at = ? WHERE comment_id = ?
@@ -160,9 +164,59 @@ func (r *CommentRepository) EditComment(ctx context.Context, commentIDStr, userI
 
 	comment.Content = content
 	comment.UpdatedAt = &now
+	comment.IsEdited = true
 	return comment, nil
 }
 
+// addCommentRevision adds the write of a comment's previous version to batch
+func addCommentRevision(batch *gocql.Batch, commentID gocql.UUID, editorIDStr, content, action string, at time.Time) {
+	editorID, _ := gocql.ParseUUID(editorIDStr)
+	batch.Query(`
+		INSERT INTO comment_revisions (comment_id, revision_id, editor_id, content, action)
+		VALUES (?, ?, ?, ?, ?)
+	`, commentID, gocql.UUIDFromTime(at), editorID, content, action)
+}
+
+// GetCommentRevisions returns a comment's previous versions, newest first
+func (r *CommentRepository) GetCommentRevisions(ctx context.Context, commentIDStr string, limit int) ([]CommentRevision, error) {
+	commentID, err := gocql.ParseUUID(commentIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	iter := r.session.Query(`
+		SELECT revision_id, editor_id, content, action
+		FROM comment_revisions
+		WHERE comment_id = ?
+		LIMIT ?
+	`, commentID, limit).WithContext(ctx).Iter()
+
+	var revisions []CommentRevision
+	var revisionID, editorID gocql.UUID
+	var content, action string
+
+	for iter.Scan(&revisionID, &editorID, &content, &action) {
+		revisions = append(revisions, CommentRevision{
+			ID:        revisionID.String(),
+			CommentID: commentIDStr,
+			EditorID:  editorID.String(),
+			Content:   content,
+			Action:    action,
+			RevisedAt: revisionID.Time(),
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get comment revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
 // GetCommentByID retrieves a comment by its ID
 func (r *CommentRepository) GetCommentByID(ctx context.Context, id string) (*Comment, error) {
 	commentID, err := gocql.ParseUUID(id)
@@ -176,11 +230,11 @@ func (r *CommentRepository) GetCommentByID(ctx context.Context, id string) (*Com
 	var isDeleted bool
 
 	err = r.session.Query(`
-		SELECT comment_id, post_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted
+		SELECT comment_id, post_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
 		FROM comments_by_id
 		WHERE comment_id = ?
 	`, commentID).WithContext(ctx).Scan(
-		&commentID, &postID, &parentID, &userID, &comment.Content, &comment.Depth, &comment.CreatedAt, &updatedAt, &isDeleted,
+		&commentID, &postID, &parentID, &userID, &comment.Content, &comment.Depth, &comment.CreatedAt, &updatedAt, &isDeleted, &comment.IsHidden,
 	)
 
 	if err != nil {
@@ -196,6 +250,7 @@ func (r *CommentRepository) GetCommentByID(ctx context.Context, id string) (*Com
 	comment.IsDeleted = isDeleted
 	if !updatedAt.IsZero() {
 		comment.UpdatedAt = &updatedAt
+		comment.IsEdited = true
 	}
 	if parentID.String() != "00000000-0000-0000-0000-000000000000" {
 		comment.ParentID = parentID.String()
@@ -210,8 +265,42 @@ func (r *CommentRepository) GetCommentsForPost(ctx context.Context, postID strin
 	return comments, err
 }
 
+// Comment sort modes accepted by GET /api/v1/posts/:id/comments
+const (
+	CommentSortNewest = "newest"
+	CommentSortOldest = "oldest"
+	CommentSortTop    = "top"
+)
+
+// CommentListOptions describes who reads a comment listing. Hidden comments
+// are only shown to their author and the post author, and the pinned comment
+// is left out because it is listed ahead of the rest.
+type CommentListOptions struct {
+	ViewerID     string
+	PostAuthorID string
+	PinnedID     string
+}
+
+// shows reports whether a comment belongs in the listing
+func (o CommentListOptions) shows(c *Comment) bool {
+	if o.PinnedID != "" && c.ID == o.PinnedID {
+		return false
+	}
+	if !c.IsHidden {
+		return true
+	}
+	return o.ViewerID != "" && (o.ViewerID == c.UserID || o.ViewerID == o.PostAuthorID)
+}
+
 // GetCommentsForPostPaginated retrieves top-level comments with cursor pagination
 func (r *CommentRepository) GetCommentsForPostPaginated(ctx context.Context, postID string, limit int, cursor string) ([]Comment, string, bool, error) {
+	return r.GetCommentsForPostSorted(ctx, postID, CommentSortNewest, CommentListOptions{}, limit, cursor)
+}
+
+// GetCommentsForPostSorted retrieves top-level comments newest or oldest
+// first with cursor pagination. Top comments are ranked by the caller from
+// GetCommentThreads.
+func (r *CommentRepository) GetCommentsForPostSorted(ctx context.Context, postID, sortMode string, opts CommentListOptions, limit int, cursor string) ([]Comment, string, bool, error) {
 	pid, err := gocql.ParseUUID(postID)
 	if err != nil {
 		return nil, "", false, fmt.Errorf("invalid post_id: %w", err)
@@ -221,28 +310,48 @@ func (r *CommentRepository) GetCommentsForPostPaginated(ctx context.Context, pos
 		limit = 20
 	}
 
-	cursorTime, err := DecodeCursor(cursor)
+	oldest := sortMode == CommentSortOldest
+	scope := CursorScopeComments
+	if oldest {
+		scope = CursorScopeCommentsOldest
+	}
+
+	cur, err := DecodeCursor(scope, cursor)
 	if err != nil {
 		return nil, "", false, fmt.Errorf("invalid cursor: %w", err)
 	}
+	after := cur.Keyset
 
 	// We fetch a bit more initially to account for some comments being replies
 	// In Cassandra, ALLOW FILTERING is generally OK within a single partition
 	var iter *gocql.Iter
 	fetchLimit := limit * 3
 
-	if cursorTime.IsZero() {
+	switch {
+	case oldest && after.IsZero():
 		iter = r.session.Query(`
-			SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted
+			SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
+			FROM comments
+			WHERE post_id = ? AND depth = 1 ORDER BY created_at ASC ALLOW FILTERING
+		`, pid).WithContext(ctx).PageSize(fetchLimit).Iter()
+	case oldest:
+		iter = r.session.Query(`
+			SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
+			FROM comments
+			WHERE post_id = ? AND created_at >= ? AND depth = 1 ORDER BY created_at ASC ALLOW FILTERING
+		`, pid, after.CreatedAt).WithContext(ctx).PageSize(fetchLimit).Iter()
+	case after.IsZero():
+		iter = r.session.Query(`
+			SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
 			FROM comments
 			WHERE post_id = ? AND depth = 1 ALLOW FILTERING
 		`, pid).WithContext(ctx).PageSize(fetchLimit).Iter()
-	} else {
+	default:
 		iter = r.session.Query(`
-			SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted
+			SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
 			FROM comments
-			WHERE post_id = ? AND created_at < ? AND depth = 1 ALLOW FILTERING
-		`, pid, cursorTime).WithContext(ctx).PageSize(fetchLimit).Iter()
+			WHERE post_id = ? AND created_at <= ? AND depth = 1 ALLOW FILTERING
+		`, pid, after.CreatedAt).WithContext(ctx).PageSize(fetchLimit).Iter()
 	}
 
 	var roots []Comment
@@ -250,9 +359,19 @@ func (r *CommentRepository) GetCommentsForPostPaginated(ctx context.Context, pos
 	var content string
 	var depth int
 	var createdAt, updatedAt time.Time
-	var isDeleted bool
+	var isDeleted, isHidden bool
+
+	for iter.Scan(&commentID, &parentID, &userID, &content, &depth, &createdAt, &updatedAt, &isDeleted, &isHidden) {
+		if len(roots) > 0 && keysetScanDone(len(roots), limit+1, roots[len(roots)-1].CreatedAt, createdAt) {
+			break
+		}
+		if oldest && !admitsOldestFirst(after, createdAt, commentID.String()) {
+			continue
+		}
+		if !oldest && !after.Admits(createdAt, commentID.String()) {
+			continue
+		}
 
-	for iter.Scan(&commentID, &parentID, &userID, &content, &depth, &createdAt, &updatedAt, &isDeleted) {
 		c := Comment{
 			ID:        commentID.String(),
 			PostID:    postID,
@@ -261,21 +380,28 @@ func (r *CommentRepository) GetCommentsForPostPaginated(ctx context.Context, pos
 			Depth:     depth,
 			CreatedAt: createdAt,
 			IsDeleted: isDeleted,
+			IsHidden:  isHidden,
 		}
 		if !updatedAt.IsZero() {
 			c.UpdatedAt = &updatedAt
+			c.IsEdited = true
 		}
-		roots = append(roots, c)
-
-		if len(roots) >= limit+1 {
-			break
+		if !opts.shows(&c) {
+			continue
 		}
+		roots = append(roots, c)
 	}
 
 	if err := iter.Close(); err != nil {
 		return nil, "", false, fmt.Errorf("failed to get paginated comments: %w", err)
 	}
 
+	if oldest {
+		sortCommentsOldestFirst(roots)
+	} else {
+		sortCommentsByKeyset(roots)
+	}
+
 	hasMore := len(roots) > limit
 	if hasMore {
 		roots = roots[:limit]
@@ -283,20 +409,102 @@ func (r *CommentRepository) GetCommentsForPostPaginated(ctx context.Context, pos
 
 	var nextCursor string
 	if hasMore && len(roots) > 0 {
-		nextCursor = EncodeCursor(roots[len(roots)-1].CreatedAt)
+		last := roots[len(roots)-1]
+		nextCursor = EncodeCursor(Cursor{
+			Scope:  scope,
+			Keyset: Keyset{CreatedAt: last.CreatedAt, ID: last.ID},
+		})
 	}
 
-	// Fetch replies for each root
+	r.AttachReplies(ctx, roots, opts)
+
+	return roots, nextCursor, hasMore, nil
+}
+
+// AttachReplies fetches the first replies of each root comment
+func (r *CommentRepository) AttachReplies(ctx context.Context, roots []Comment, opts CommentListOptions) {
 	for i := range roots {
-		replies, _, _, _ := r.GetRepliesForComment(ctx, roots[i].ID, 3, "")
+		replies, _, _, _ := r.GetRepliesForComment(ctx, roots[i].ID, opts, 3, "")
 		roots[i].Replies = replies
 	}
+}
 
-	return roots, nextCursor, hasMore, nil
+// GetCommentThreads returns the top-level comments of a post created before
+// asOf, newest first, with the number of replies in each thread. At most max
+// comments of any depth are read, so threads older than that are left out.
+func (r *CommentRepository) GetCommentThreads(ctx context.Context, postID string, opts CommentListOptions, asOf time.Time, max int) ([]Comment, map[string]int64, error) {
+	pid, err := gocql.ParseUUID(postID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT comment_id, parent_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
+		FROM comments
+		WHERE post_id = ? AND created_at < ?
+	`, pid, asOf).WithContext(ctx).PageSize(max).Iter()
+
+	var roots []Comment
+	parentOf := make(map[string]string)
+	var replyIDs []string
+	var commentID, parentID, userID gocql.UUID
+	var content string
+	var depth int
+	var createdAt, updatedAt time.Time
+	var isDeleted, isHidden bool
+
+	for read := 0; read < max && iter.Scan(&commentID, &parentID, &userID, &content, &depth, &createdAt, &updatedAt, &isDeleted, &isHidden); read++ {
+		if depth > 1 {
+			parentOf[commentID.String()] = parentID.String()
+			// Deleted and hidden replies do not count toward a thread
+			if !isDeleted && !isHidden {
+				replyIDs = append(replyIDs, commentID.String())
+			}
+			continue
+		}
+
+		c := Comment{
+			ID:        commentID.String(),
+			PostID:    postID,
+			UserID:    userID.String(),
+			Content:   content,
+			Depth:     depth,
+			CreatedAt: createdAt,
+			IsDeleted: isDeleted,
+			IsHidden:  isHidden,
+		}
+		if !updatedAt.IsZero() {
+			c.UpdatedAt = &updatedAt
+			c.IsEdited = true
+		}
+		if opts.shows(&c) {
+			roots = append(roots, c)
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to get comment threads: %w", err)
+	}
+
+	// Walk each reply up to its top-level comment
+	replyCounts := make(map[string]int64)
+	for _, id := range replyIDs {
+		root := parentOf[id]
+		for hops := 0; hops < MaxCommentDepth; hops++ {
+			parent, ok := parentOf[root]
+			if !ok {
+				break
+			}
+			root = parent
+		}
+		replyCounts[root]++
+	}
+
+	return roots, replyCounts, nil
 }
 
 // GetRepliesForComment retrieves replies for a comment
-func (r *CommentRepository) GetRepliesForComment(ctx context.Context, parentIDStr string, limit int, cursor string) ([]Comment, string, bool, error) {
+func (r *CommentRepository) GetRepliesForComment(ctx context.Context, parentIDStr string, opts CommentListOptions, limit int, cursor string) ([]Comment, string, bool, error) {
 	parentComment, err := r.GetCommentByID(ctx, parentIDStr)
 	if err != nil {
 		return nil, "", false, err
@@ -309,25 +517,26 @@ func (r *CommentRepository) GetRepliesForComment(ctx context.Context, parentIDSt
 		limit = 10
 	}
 
-	cursorTime, err := DecodeCursor(cursor)
+	cur, err := DecodeCursor(CursorScopeReplies, cursor)
 	if err != nil {
 		return nil, "", false, fmt.Errorf("invalid cursor: %w", err)
 	}
+	after := cur.Keyset
 
 	var iter *gocql.Iter
 
-	if cursorTime.IsZero() {
+	if after.IsZero() {
 		iter = r.session.Query(`
-			SELECT comment_id, user_id, content, depth, created_at, updated_at, is_deleted
+			SELECT comment_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
 			FROM comments
 			WHERE post_id = ? AND parent_id = ? ALLOW FILTERING
 		`, pid, parentID).WithContext(ctx).PageSize(limit + 1).Iter()
 	} else {
 		iter = r.session.Query(`
-			SELECT comment_id, user_id, content, depth, created_at, updated_at, is_deleted
+			SELECT comment_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
 			FROM comments
-			WHERE post_id = ? AND parent_id = ? AND created_at < ? ALLOW FILTERING
-		`, pid, parentID, cursorTime).WithContext(ctx).PageSize(limit + 1).Iter()
+			WHERE post_id = ? AND parent_id = ? AND created_at <= ? ALLOW FILTERING
+		`, pid, parentID, after.CreatedAt).WithContext(ctx).PageSize(limit + 1).Iter()
 	}
 
 	var replies []Comment
@@ -335,9 +544,16 @@ func (r *CommentRepository) GetRepliesForComment(ctx context.Context, parentIDSt
 	var content string
 	var depth int
 	var createdAt, updatedAt time.Time
-	var isDeleted bool
+	var isDeleted, isHidden bool
+
+	for iter.Scan(&commentID, &userID, &content, &depth, &createdAt, &updatedAt, &isDeleted, &isHidden) {
+		if len(replies) > 0 && keysetScanDone(len(replies), limit+1, replies[len(replies)-1].CreatedAt, createdAt) {
+			break
+		}
+		if !after.Admits(createdAt, commentID.String()) {
+			continue
+		}
 
-	for iter.Scan(&commentID, &userID, &content, &depth, &createdAt, &updatedAt, &isDeleted) {
 		c := Comment{
 			ID:        commentID.String(),
 			PostID:    parentComment.PostID,
@@ -347,42 +563,216 @@ func (r *CommentRepository) GetRepliesForComment(ctx context.Context, parentIDSt
 			Depth:     depth,
 			CreatedAt: createdAt,
 			IsDeleted: isDeleted,
+			IsHidden:  isHidden,
 		}
 		if !updatedAt.IsZero() {
 			c.UpdatedAt = &updatedAt
+			c.IsEdited = true
 		}
-		replies = append(replies, c)
-
-		if len(replies) >= limit+1 {
-			break
+		if !opts.shows(&c) {
+			continue
 		}
+		replies = append(replies, c)
 	}
 
 	if err := iter.Close(); err != nil {
 		return nil, "", false, fmt.Errorf("failed to get replies: %w", err)
 	}
 
-	// Sort replies ASCending order (oldest first)
-	// Actually we need to check if we should reverse. The query returns DESC.
-	for i, j := 0, len(replies)-1; i < j; i, j = i+1, j-1 {
-		replies[i], replies[j] = replies[j], replies[i]
-	}
+	// Pages are walked newest-first in keyset order, then shown oldest first
+	sortCommentsByKeyset(replies)
 
 	hasMore := len(replies) > limit
 	if hasMore {
-		// since we reversed it, the element to chop is at index 0
-		replies = replies[1:]
+		replies = replies[:limit]
 	}
 
 	var nextCursor string
 	if hasMore && len(replies) > 0 {
-		// We reversed it, the oldest is at index 0.
-		nextCursor = EncodeCursor(replies[0].CreatedAt)
+		last := replies[len(replies)-1]
+		nextCursor = EncodeCursor(Cursor{
+			Scope:  CursorScopeReplies,
+			Keyset: Keyset{CreatedAt: last.CreatedAt, ID: last.ID},
+		})
+	}
+
+	for i, j := 0, len(replies)-1; i < j; i, j = i+1, j-1 {
+		replies[i], replies[j] = replies[j], replies[i]
 	}
 
 	return replies, nextCursor, hasMore, nil
 }
 
+// sortCommentsByKeyset orders comments by (created_at DESC, comment_id ASC)
+func sortCommentsByKeyset(comments []Comment) {
+	sort.Slice(comments, func(i, j int) bool {
+		return KeysetBefore(comments[i].CreatedAt, comments[i].ID, comments[j].CreatedAt, comments[j].ID)
+	})
+}
+
+// sortCommentsOldestFirst orders comments by (created_at ASC, comment_id ASC)
+func sortCommentsOldestFirst(comments []Comment) {
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+}
+
+// admitsOldestFirst reports whether a row at (createdAt, id) comes after the
+// keyset in oldest-first order
+func admitsOldestFirst(k Keyset, createdAt time.Time, id string) bool {
+	if k.IsZero() {
+		return true
+	}
+	if createdAt.After(k.CreatedAt) {
+		return true
+	}
+	return createdAt.Equal(k.CreatedAt) && id > k.ID
+}
+
+// ============== COMMENT CONTEXT ==============
+
+// HiddenCommentContent replaces the content of an ancestor in a comment's
+// context that the viewer may not see
+const HiddenCommentContent = "[hidden]"
+
+// GetCommentContext returns a comment with its ancestors, top-level comment
+// first, and up to siblings comments on each side of it under the same
+// parent. Ancestors the viewer may not see keep their place in the chain with
+// their content masked; the comment itself is "not found" for them.
+func (r *CommentRepository) GetCommentContext(ctx context.Context, commentIDStr string, opts CommentListOptions, siblings int) (*CommentContext, error) {
+	comment, err := r.GetCommentByID(ctx, commentIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pinned comment is a normal sibling here
+	opts.PinnedID = ""
+	if !opts.shows(comment) {
+		return nil, fmt.Errorf("comment not found")
+	}
+
+	result := &CommentContext{Comment: *comment}
+
+	// A comment has at most MaxCommentDepth-1 ancestors
+	parentID := comment.ParentID
+	for i := 1; parentID != "" && i < MaxCommentDepth; i++ {
+		parent, err := r.GetCommentByID(ctx, parentID)
+		if err != nil {
+			if err.Error() == "comment not found" {
+				break
+			}
+			return nil, err
+		}
+		if !opts.shows(parent) {
+			parent.Content = HiddenCommentContent
+		}
+		result.Ancestors = append([]Comment{*parent}, result.Ancestors...)
+		parentID = parent.ParentID
+	}
+
+	if siblings > 0 {
+		if result.Before, result.HasMoreBefore, err = r.getSiblings(ctx, comment, opts, siblings, false); err != nil {
+			return nil, err
+		}
+		if result.After, result.HasMoreAfter, err = r.getSiblings(ctx, comment, opts, siblings, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// getSiblings returns up to limit comments with the same parent as comment
+// that are older (or newer, when newer is set) than it, oldest first
+func (r *CommentRepository) getSiblings(ctx context.Context, comment *Comment, opts CommentListOptions, limit int, newer bool) ([]Comment, bool, error) {
+	pid, _ := gocql.ParseUUID(comment.PostID)
+	key := Keyset{CreatedAt: comment.CreatedAt, ID: comment.ID}
+
+	// Top-level comments are matched by depth since older rows may have no
+	// parent_id
+	filter, args := "depth = 1", []interface{}{pid, comment.CreatedAt}
+	if comment.ParentID != "" {
+		parentID, _ := gocql.ParseUUID(comment.ParentID)
+		filter, args = "parent_id = ?", append(args, parentID)
+	}
+
+	// Nearest siblings first in both directions
+	stmt := `
+		SELECT comment_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
+		FROM comments
+		WHERE post_id = ? AND created_at <= ? AND ` + filter + ` ALLOW FILTERING`
+	if newer {
+		stmt = `
+		SELECT comment_id, user_id, content, depth, created_at, updated_at, is_deleted, is_hidden
+		FROM comments
+		WHERE post_id = ? AND created_at >= ? AND ` + filter + ` ORDER BY created_at ASC ALLOW FILTERING`
+	}
+	iter := r.session.Query(stmt, args...).WithContext(ctx).PageSize(limit + 2).Iter()
+
+	var found []Comment
+	var commentID, userID gocql.UUID
+	var content string
+	var depth int
+	var createdAt, updatedAt time.Time
+	var isDeleted, isHidden bool
+
+	for iter.Scan(&commentID, &userID, &content, &depth, &createdAt, &updatedAt, &isDeleted, &isHidden) {
+		if len(found) > 0 && keysetScanDone(len(found), limit+1, found[len(found)-1].CreatedAt, createdAt) {
+			break
+		}
+		id := commentID.String()
+		if newer && !admitsOldestFirst(key, createdAt, id) {
+			continue
+		}
+		if !newer && !key.Admits(createdAt, id) {
+			continue
+		}
+
+		c := Comment{
+			ID:        id,
+			PostID:    comment.PostID,
+			ParentID:  comment.ParentID,
+			UserID:    userID.String(),
+			Content:   content,
+			Depth:     depth,
+			CreatedAt: createdAt,
+			IsDeleted: isDeleted,
+			IsHidden:  isHidden,
+		}
+		if !updatedAt.IsZero() {
+			c.UpdatedAt = &updatedAt
+			c.IsEdited = true
+		}
+		if !opts.shows(&c) {
+			continue
+		}
+		found = append(found, c)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to get sibling comments: %w", err)
+	}
+
+	// Keep the nearest ones; rows tied on created_at may arrive out of order
+	if newer {
+		sortCommentsOldestFirst(found)
+	} else {
+		sortCommentsByKeyset(found)
+	}
+	hasMore := len(found) > limit
+	if hasMore {
+		found = found[:limit]
+	}
+	if !newer {
+		sortCommentsOldestFirst(found)
+	}
+
+	return found, hasMore, nil
+}
+
 // DeleteComment soft-deletes a comment by its ID
 func (r *CommentRepository) DeleteComment(ctx context.Context, commentID, userID string) error {
 	comment, err := r.GetCommentByID(ctx, commentID)
@@ -401,6 +791,12 @@ func (r *CommentRepository) DeleteComment(ctx context.Context, commentID, userID
 	batch := r.session.NewBatch(gocql.LoggedBatch)
 	batch.WithContext(ctx)
 
+	// The tombstone replaces the content; keep it for the post author and
+	// moderators
+	if !comment.IsDeleted {
+		addCommentRevision(batch, cid, userID, comment.Content, CommentRevisionDelete, time.Now())
+	}
+
 	// Soft Delete from comments_by_id
 	batch.Query(`
 		UPDATE comments_by_id SET content = '[deleted]', is_deleted = true WHERE comment_id = ?
@@ -419,6 +815,140 @@ func (r *CommentRepository) DeleteComment(ctx context.Context, commentID, userID
 	return nil
 }
 
+// ============== PINNED AND HIDDEN COMMENTS ==============
+
+// PinComment pins a top-level comment above the other comments of its post,
+// replacing any earlier pin
+func (r *CommentRepository) PinComment(ctx context.Context, postIDStr, commentIDStr string) (*Comment, error) {
+	comment, err := r.GetCommentByID(ctx, commentIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if comment.PostID != postIDStr {
+		return nil, fmt.Errorf("comment not found")
+	}
+	if comment.Depth != 1 {
+		return nil, fmt.Errorf("invalid comment: only top-level comments can be pinned")
+	}
+	if comment.IsDeleted || comment.IsHidden {
+		return nil, fmt.Errorf("invalid comment: deleted and hidden comments cannot be pinned")
+	}
+
+	pid, _ := gocql.ParseUUID(postIDStr)
+	cid, _ := gocql.ParseUUID(commentIDStr)
+
+	err = r.session.Query(`
+		INSERT INTO comment_pins (post_id, comment_id, pinned_at) VALUES (?, ?, ?)
+	`, pid, cid, time.Now()).WithContext(ctx).Exec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pin comment: %w", err)
+	}
+
+	comment.IsPinned = true
+	return comment, nil
+}
+
+// UnpinComment removes the pinned comment of a post, if any
+func (r *CommentRepository) UnpinComment(ctx context.Context, postIDStr string) error {
+	pid, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	err = r.session.Query(`
+		DELETE FROM comment_pins WHERE post_id = ?
+	`, pid).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to unpin comment: %w", err)
+	}
+	return nil
+}
+
+// GetPinnedCommentID returns the ID of the pinned comment of a post, empty
+// if none is pinned
+func (r *CommentRepository) GetPinnedCommentID(ctx context.Context, postIDStr string) (string, error) {
+	pid, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	var commentID gocql.UUID
+	err = r.session.Query(`
+		SELECT comment_id FROM comment_pins WHERE post_id = ?
+	`, pid).WithContext(ctx).Scan(&commentID)
+	if err == gocql.ErrNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get pinned comment: %w", err)
+	}
+	return commentID.String(), nil
+}
+
+// GetPinnedComment returns the pinned comment of a post, or nil if none is
+// pinned or it has since been deleted
+func (r *CommentRepository) GetPinnedComment(ctx context.Context, postIDStr string) (*Comment, error) {
+	commentID, err := r.GetPinnedCommentID(ctx, postIDStr)
+	if err != nil || commentID == "" {
+		return nil, err
+	}
+
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil {
+		if err.Error() == "comment not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if comment.IsDeleted {
+		return nil, nil
+	}
+
+	comment.IsPinned = true
+	return comment, nil
+}
+
+// SetCommentHidden hides or unhides a comment. Hiding the pinned comment
+// unpins it.
+func (r *CommentRepository) SetCommentHidden(ctx context.Context, commentIDStr string, hidden bool) (*Comment, error) {
+	comment, err := r.GetCommentByID(ctx, commentIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted {
+		return nil, fmt.Errorf("invalid comment: cannot hide a deleted comment")
+	}
+
+	cid, _ := gocql.ParseUUID(commentIDStr)
+	pid, _ := gocql.ParseUUID(comment.PostID)
+
+	batch := r.session.NewBatch(gocql.LoggedBatch)
+	batch.WithContext(ctx)
+
+	batch.Query(`
+		UPDATE comments_by_id SET is_hidden = ? WHERE comment_id = ?
+	`, hidden, cid)
+
+	batch.Query(`
+		UPDATE comments SET is_hidden = ? WHERE post_id = ? AND created_at = ? AND comment_id = ?
+	`, hidden, pid, comment.CreatedAt, cid)
+
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to update comment visibility: %w", err)
+	}
+
+	if hidden {
+		if pinnedID, err := r.GetPinnedCommentID(ctx, comment.PostID); err == nil && pinnedID == commentIDStr {
+			if err := r.UnpinComment(ctx, comment.PostID); err != nil {
+				fmt.Printf("Warning: failed to unpin hidden comment: %v\n", err)
+			}
+		}
+	}
+
+	comment.IsHidden = hidden
+	return comment, nil
+}
+
 // GetCommentCount returns the comment count for a post
 func (r *CommentRepository) GetCommentCount(ctx context.Context, postID string) (int64, error) {
 	if r.commentCounter != nil {
@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,7 +11,7 @@ import (
 
 func TestCommentRepository_Integration(t *testing.T) {
 	repo := NewCommentRepository(testSession, nil)
-	postRepo := NewPostRepository(testSession)
+	postRepo := NewPostRepository(testSession, nil)
 	userRepo := NewUserRepository(testSession)
 	ctx := context.Background()
 
@@ -59,4 +60,114 @@ func TestCommentRepository_Integration(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, int64(2), count) // Root + Reply
 	})
+
+	t.Run("Edits And Deletion Keep Revisions", func(t *testing.T) {
+		c, err := repo.CreateComment(ctx, &CreateCommentRequest{PostID: post.ID, UserID: user.ID, Content: "v1"})
+		require.NoError(t, err)
+		assert.False(t, c.IsEdited)
+
+		edited, err := repo.EditComment(ctx, c.ID, user.ID, "v2")
+		require.NoError(t, err)
+		assert.True(t, edited.IsEdited)
+
+		fetched, err := repo.GetCommentByID(ctx, c.ID)
+		require.NoError(t, err)
+		assert.True(t, fetched.IsEdited)
+
+		require.NoError(t, repo.DeleteComment(ctx, c.ID, user.ID))
+		require.NoError(t, repo.DeleteComment(ctx, c.ID, user.ID))
+
+		revisions, err := repo.GetCommentRevisions(ctx, c.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2, "deleting twice records one revision")
+		assert.Equal(t, CommentRevisionDelete, revisions[0].Action)
+		assert.Equal(t, "v2", revisions[0].Content)
+		assert.Equal(t, CommentRevisionEdit, revisions[1].Action)
+		assert.Equal(t, "v1", revisions[1].Content)
+	})
+
+	t.Run("Comment Context", func(t *testing.T) {
+		create := func(parentID, content string) *Comment {
+			time.Sleep(5 * time.Millisecond) // distinct created_at
+			c, err := repo.CreateComment(ctx, &CreateCommentRequest{PostID: post.ID, UserID: user.ID, ParentID: parentID, Content: content})
+			require.NoError(t, err)
+			return c
+		}
+		root := create("", "thread root")
+		a := create(root.ID, "a")
+		b := create(root.ID, "b")
+		c := create(root.ID, "c")
+		d := create(b.ID, "d")
+
+		thread, err := repo.GetCommentContext(ctx, d.ID, CommentListOptions{}, 3)
+		require.NoError(t, err)
+		require.Len(t, thread.Ancestors, 2)
+		assert.Equal(t, root.ID, thread.Ancestors[0].ID)
+		assert.Equal(t, b.ID, thread.Ancestors[1].ID)
+		assert.Empty(t, thread.Before)
+		assert.Empty(t, thread.After)
+
+		thread, err = repo.GetCommentContext(ctx, b.ID, CommentListOptions{}, 1)
+		require.NoError(t, err)
+		require.Len(t, thread.Before, 1)
+		require.Len(t, thread.After, 1)
+		assert.Equal(t, a.ID, thread.Before[0].ID)
+		assert.Equal(t, c.ID, thread.After[0].ID)
+		assert.False(t, thread.HasMoreBefore)
+		assert.False(t, thread.HasMoreAfter)
+
+		// A hidden ancestor keeps its place without its content
+		_, err = repo.SetCommentHidden(ctx, b.ID, true)
+		require.NoError(t, err)
+		thread, err = repo.GetCommentContext(ctx, d.ID, CommentListOptions{ViewerID: "someone-else"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, HiddenCommentContent, thread.Ancestors[1].Content)
+
+		_, err = repo.GetCommentContext(ctx, b.ID, CommentListOptions{ViewerID: "someone-else"}, 0)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("Comment Settings Default To Everyone", func(t *testing.T) {
+		settings, err := repo.GetCommentSettings(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, CommentAudienceEveryone, settings.Audience)
+
+		_, err = repo.SetCommentSettings(ctx, post.ID, CommentAudienceFollowers, 7)
+		require.NoError(t, err)
+
+		settings, err = repo.GetCommentSettings(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, CommentAudienceFollowers, settings.Audience)
+		assert.Equal(t, 7, settings.LockAfterDays)
+
+		_, err = repo.SetCommentSettings(ctx, post.ID, "friends", 0)
+		assert.ErrorContains(t, err, "invalid")
+	})
+}
+
+func TestCommentSettingsLock(t *testing.T) {
+	created := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	open := &CommentSettings{Audience: CommentAudienceEveryone}
+	open.SetLocksAt(created)
+	assert.Nil(t, open.LocksAt)
+	assert.False(t, open.IsLocked(created.AddDate(10, 0, 0)))
+
+	weekly := &CommentSettings{Audience: CommentAudienceEveryone, LockAfterDays: 7}
+	weekly.SetLocksAt(created)
+	require.NotNil(t, weekly.LocksAt)
+	assert.False(t, weekly.IsLocked(created.AddDate(0, 0, 6)))
+	assert.True(t, weekly.IsLocked(created.AddDate(0, 0, 7)))
+}
+
+func TestCommentListOptionsShows(t *testing.T) {
+	opts := CommentListOptions{ViewerID: "viewer", PostAuthorID: "author", PinnedID: "pinned"}
+
+	assert.True(t, opts.shows(&Comment{ID: "c1", UserID: "someone"}))
+	assert.False(t, opts.shows(&Comment{ID: "pinned", UserID: "someone"}), "pinned comment is listed first instead")
+	assert.False(t, opts.shows(&Comment{ID: "c2", UserID: "someone", IsHidden: true}))
+	assert.True(t, opts.shows(&Comment{ID: "c3", UserID: "viewer", IsHidden: true}), "commenter still sees a hidden comment")
+
+	opts.ViewerID = "author"
+	assert.True(t, opts.shows(&Comment{ID: "c2", UserID: "someone", IsHidden: true}), "post author still sees a hidden comment")
 }
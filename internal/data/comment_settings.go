@@ -0,0 +1,76 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// GetCommentSettings returns the comment controls of a post. Posts without
+// settings are open to everyone.
+func (r *CommentRepository) GetCommentSettings(ctx context.Context, postIDStr string) (*CommentSettings, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	settings := &CommentSettings{PostID: postIDStr, Audience: CommentAudienceEveryone}
+
+	var audience string
+	var lockAfterDays int
+	err = r.session.Query(`
+		SELECT audience, lock_after_days FROM post_comment_settings WHERE post_id = ?
+	`, postID).WithContext(ctx).Scan(&audience, &lockAfterDays)
+	if err != nil {
+		if err == gocql.ErrNotFound {
+			return settings, nil
+		}
+		return nil, fmt.Errorf("failed to get comment settings: %w", err)
+	}
+
+	if ValidCommentAudience(audience) {
+		settings.Audience = audience
+	}
+	settings.LockAfterDays = lockAfterDays
+	return settings, nil
+}
+
+// SetCommentSettings replaces the comment controls of a post
+func (r *CommentRepository) SetCommentSettings(ctx context.Context, postIDStr, audience string, lockAfterDays int) (*CommentSettings, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+	if !ValidCommentAudience(audience) {
+		return nil, fmt.Errorf("invalid audience: %s", audience)
+	}
+	if lockAfterDays < 0 {
+		return nil, fmt.Errorf("invalid lock_after_days: %d", lockAfterDays)
+	}
+
+	err = r.session.Query(`
+		INSERT INTO post_comment_settings (post_id, audience, lock_after_days, updated_at)
+		VALUES (?, ?, ?, ?)
+	`, postID, audience, lockAfterDays, time.Now()).WithContext(ctx).Exec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set comment settings: %w", err)
+	}
+
+	return &CommentSettings{PostID: postIDStr, Audience: audience, LockAfterDays: lockAfterDays}, nil
+}
+
+// SetLocksAt fills LocksAt from the creation time of the post
+func (s *CommentSettings) SetLocksAt(postCreatedAt time.Time) {
+	s.LocksAt = nil
+	if s.LockAfterDays > 0 {
+		locksAt := postCreatedAt.AddDate(0, 0, s.LockAfterDays)
+		s.LocksAt = &locksAt
+	}
+}
+
+// IsLocked reports whether comments closed at now. SetLocksAt must have run.
+func (s *CommentSettings) IsLocked(now time.Time) bool {
+	return s.LocksAt != nil && !now.Before(*s.LocksAt)
+}
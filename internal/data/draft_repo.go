@@ -0,0 +1,445 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// MaxDraftsPerUser caps how many drafts (scheduled or not) a user may keep
+	MaxDraftsPerUser = 100
+	// MaxScheduleAhead is how far in the future a draft may be scheduled
+	MaxScheduleAhead = 30 * 24 * time.Hour
+
+	// scheduledPostsKey is a sorted set of "user_id:draft_id" scored by publish_at (unix seconds)
+	scheduledPostsKey = "posts:scheduled"
+	// scheduledPostLockTTL bounds how long one worker may hold a due draft
+	scheduledPostLockTTL = 2 * time.Minute
+)
+
+// ErrSchedulingUnavailable is returned when a draft cannot be scheduled
+// because Redis is not configured
+var ErrSchedulingUnavailable = fmt.Errorf("scheduling unavailable")
+
+// PostDraft is an unpublished post. Drafts with PublishAt set are scheduled
+// and published by the scheduled post worker at that time.
+type PostDraft struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	Content           string     `json:"content"`
+	MediaURLs         []string   `json:"media_urls,omitempty"`
+	MediaKeys         []string   `json:"media_keys,omitempty"`
+	Latitude          *float64   `json:"latitude,omitempty"`
+	Longitude         *float64   `json:"longitude,omitempty"`
+	Visibility        string     `json:"visibility,omitempty"`
+	LocationPrecision string     `json:"location_precision,omitempty"`
+	ExpiresIn         int        `json:"expires_in,omitempty"`
+	QuotedPostID      string     `json:"quoted_post_id,omitempty"`
+	PublishAt         *time.Time `json:"publish_at,omitempty"`
+	PublishError      string     `json:"publish_error,omitempty"` // Why the last scheduled publish failed
+	IPAddress         string     `json:"-"`
+	UserAgent         string     `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// SaveDraftRequest represents the request body for creating or replacing a
+// draft. Every field is optional until the draft is scheduled or published.
+type SaveDraftRequest struct {
+	Content           string     `json:"content"`
+	MediaURLs         []string   `json:"media_urls"`
+	MediaKeys         []string   `json:"media_keys"`
+	Latitude          *float64   `json:"latitude"`
+	Longitude         *float64   `json:"longitude"`
+	Visibility        string     `json:"visibility"`
+	LocationPrecision string     `json:"location_precision"`
+	ExpiresIn         int        `json:"expires_in"`
+	QuotedPostID      string     `json:"quoted_post_id"`
+	PublishAt         *time.Time `json:"publish_at"` // Schedules the draft; omit to keep it unscheduled
+	IPAddress         string     `json:"-"`
+	UserAgent         string     `json:"-"`
+}
+
+// IsComplete reports whether the draft has everything a post needs
+func (r *SaveDraftRequest) IsComplete() bool {
+	return strings.TrimSpace(r.Content) != "" && r.Latitude != nil && r.Longitude != nil
+}
+
+// PostRequest returns the CreatePostRequest the draft publishes as. It is
+// only meaningful for complete drafts.
+func (r *SaveDraftRequest) PostRequest(userID string) *CreatePostRequest {
+	req := &CreatePostRequest{
+		UserID:            userID,
+		Content:           r.Content,
+		MediaURLs:         r.MediaURLs,
+		MediaKeys:         r.MediaKeys,
+		Visibility:        r.Visibility,
+		LocationPrecision: r.LocationPrecision,
+		ExpiresIn:         r.ExpiresIn,
+		QuotedPostID:      r.QuotedPostID,
+		IPAddress:         r.IPAddress,
+		UserAgent:         r.UserAgent,
+	}
+	if r.Latitude != nil {
+		req.Latitude = *r.Latitude
+	}
+	if r.Longitude != nil {
+		req.Longitude = *r.Longitude
+	}
+	return req
+}
+
+// SaveRequest returns the draft's fields as a SaveDraftRequest
+func (d *PostDraft) SaveRequest() *SaveDraftRequest {
+	return &SaveDraftRequest{
+		Content:           d.Content,
+		MediaURLs:         d.MediaURLs,
+		MediaKeys:         d.MediaKeys,
+		Latitude:          d.Latitude,
+		Longitude:         d.Longitude,
+		Visibility:        d.Visibility,
+		LocationPrecision: d.LocationPrecision,
+		ExpiresIn:         d.ExpiresIn,
+		QuotedPostID:      d.QuotedPostID,
+		PublishAt:         d.PublishAt,
+		IPAddress:         d.IPAddress,
+		UserAgent:         d.UserAgent,
+	}
+}
+
+// ScheduledDraft identifies a draft that is due for publishing
+type ScheduledDraft struct {
+	UserID  string
+	DraftID string
+}
+
+// DraftRepository stores post drafts in Cassandra and the publishing schedule
+// in a Redis sorted set
+type DraftRepository struct {
+	session *gocql.Session
+	redis   *redis.Client
+}
+
+// NewDraftRepository creates a new DraftRepository. Without Redis, drafts
+// can be saved and published by hand but not scheduled.
+func NewDraftRepository(session *gocql.Session, redisClient *redis.Client) *DraftRepository {
+	return &DraftRepository{session: session, redis: redisClient}
+}
+
+// CreateDraft saves a new draft for userID and schedules it if PublishAt is set
+func (r *DraftRepository) CreateDraft(ctx context.Context, userID string, req *SaveDraftRequest) (*PostDraft, error) {
+	if _, err := gocql.ParseUUID(userID); err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	if req.PublishAt != nil && r.redis == nil {
+		return nil, ErrSchedulingUnavailable
+	}
+
+	drafts, err := r.GetDrafts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(drafts) >= MaxDraftsPerUser {
+		return nil, fmt.Errorf("too many drafts: the limit is %d", MaxDraftsPerUser)
+	}
+
+	now := time.Now()
+	draft := &PostDraft{ID: gocql.TimeUUID().String(), UserID: userID, CreatedAt: now}
+	if err := r.save(ctx, draft, req, now); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// UpdateDraft replaces the contents and schedule of one of userID's drafts
+func (r *DraftRepository) UpdateDraft(ctx context.Context, userID, draftID string, req *SaveDraftRequest) (*PostDraft, error) {
+	if req.PublishAt != nil && r.redis == nil {
+		return nil, ErrSchedulingUnavailable
+	}
+
+	draft, err := r.GetDraft(ctx, userID, draftID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.save(ctx, draft, req, time.Now()); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// save writes req into draft and brings the schedule in line with it
+func (r *DraftRepository) save(ctx context.Context, draft *PostDraft, req *SaveDraftRequest, now time.Time) error {
+	uid, _ := gocql.ParseUUID(draft.UserID)
+	did, err := gocql.ParseUUID(draft.ID)
+	if err != nil {
+		return fmt.Errorf("invalid draft_id: %w", err)
+	}
+
+	var publishAt interface{}
+	if req.PublishAt != nil {
+		publishAt = *req.PublishAt
+	}
+	var quotedPostID interface{}
+	if req.QuotedPostID != "" {
+		qid, err := gocql.ParseUUID(req.QuotedPostID)
+		if err != nil {
+			return fmt.Errorf("invalid quoted_post_id: %w", err)
+		}
+		quotedPostID = qid
+	}
+
+	if err := r.session.Query(`
+		INSERT INTO post_drafts (user_id, draft_id, content, media_urls, media_keys, latitude, longitude, visibility, location_precision, expires_in, quoted_post_id, publish_at, publish_error, ip_address, user_agent, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, uid, did, req.Content, req.MediaURLs, req.MediaKeys, req.Latitude, req.Longitude, req.Visibility, req.LocationPrecision,
+		req.ExpiresIn, quotedPostID, publishAt, "", req.IPAddress, req.UserAgent, draft.CreatedAt, now).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	draft.Content = req.Content
+	draft.MediaURLs = req.MediaURLs
+	draft.MediaKeys = req.MediaKeys
+	draft.Latitude = req.Latitude
+	draft.Longitude = req.Longitude
+	draft.Visibility = req.Visibility
+	draft.LocationPrecision = req.LocationPrecision
+	draft.ExpiresIn = req.ExpiresIn
+	draft.QuotedPostID = req.QuotedPostID
+	draft.PublishAt = req.PublishAt
+	draft.PublishError = ""
+	draft.IPAddress = req.IPAddress
+	draft.UserAgent = req.UserAgent
+	draft.UpdatedAt = now
+
+	if req.PublishAt != nil {
+		return r.Schedule(ctx, draft.UserID, draft.ID, *req.PublishAt)
+	}
+	return r.Unschedule(ctx, draft.UserID, draft.ID)
+}
+
+// GetDraft returns one of userID's drafts
+func (r *DraftRepository) GetDraft(ctx context.Context, userID, draftID string) (*PostDraft, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	did, err := gocql.ParseUUID(draftID)
+	if err != nil {
+		return nil, fmt.Errorf("draft not found")
+	}
+
+	iter := r.session.Query(`
+		SELECT draft_id, content, media_urls, media_keys, latitude, longitude, visibility, location_precision, expires_in, quoted_post_id, publish_at, publish_error, ip_address, user_agent, created_at, updated_at
+		FROM post_drafts WHERE user_id = ? AND draft_id = ?
+	`, uid, did).WithContext(ctx).Iter()
+
+	drafts := scanDrafts(iter, userID)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	if len(drafts) == 0 {
+		return nil, fmt.Errorf("draft not found")
+	}
+	return &drafts[0], nil
+}
+
+// GetDrafts returns userID's drafts, newest first
+func (r *DraftRepository) GetDrafts(ctx context.Context, userID string) ([]PostDraft, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT draft_id, content, media_urls, media_keys, latitude, longitude, visibility, location_precision, expires_in, quoted_post_id, publish_at, publish_error, ip_address, user_agent, created_at, updated_at
+		FROM post_drafts WHERE user_id = ?
+	`, uid).WithContext(ctx).Iter()
+
+	drafts := scanDrafts(iter, userID)
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get drafts: %w", err)
+	}
+	return drafts, nil
+}
+
+func scanDrafts(iter *gocql.Iter, userID string) []PostDraft {
+	var drafts []PostDraft
+	var d PostDraft
+	var draftID gocql.UUID
+	var quotedPostID gocql.UUID
+	var publishAt time.Time
+
+	for iter.Scan(&draftID, &d.Content, &d.MediaURLs, &d.MediaKeys, &d.Latitude, &d.Longitude, &d.Visibility, &d.LocationPrecision,
+		&d.ExpiresIn, &quotedPostID, &publishAt, &d.PublishError, &d.IPAddress, &d.UserAgent, &d.CreatedAt, &d.UpdatedAt) {
+		d.ID = draftID.String()
+		d.UserID = userID
+		d.QuotedPostID = optionalUUID(quotedPostID)
+		d.PublishAt = optionalTime(publishAt)
+		drafts = append(drafts, d)
+
+		d = PostDraft{}
+		quotedPostID = gocql.UUID{}
+		publishAt = time.Time{}
+	}
+	return drafts
+}
+
+// DeleteDraft removes one of userID's drafts and its schedule
+func (r *DraftRepository) DeleteDraft(ctx context.Context, userID, draftID string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	did, err := gocql.ParseUUID(draftID)
+	if err != nil {
+		return fmt.Errorf("draft not found")
+	}
+
+	if err := r.session.Query(`
+		DELETE FROM post_drafts WHERE user_id = ? AND draft_id = ?
+	`, uid, did).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+	return r.Unschedule(ctx, userID, draftID)
+}
+
+// ClaimPublish records postID as the post userID's draft publishes as,
+// unless a post ID is already recorded. It returns the recorded ID: postID
+// when the claim was made, or the earlier one, whose post may or may not
+// have been written yet.
+func (r *DraftRepository) ClaimPublish(ctx context.Context, userID, draftID string, createdAt time.Time, postID string) (string, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user_id: %w", err)
+	}
+	did, err := gocql.ParseUUID(draftID)
+	if err != nil {
+		return "", fmt.Errorf("draft not found")
+	}
+	pid, err := gocql.ParseUUID(postID)
+	if err != nil {
+		return "", fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	// created_at keeps the update from recreating a draft deleted meanwhile
+	existing := map[string]interface{}{}
+	applied, err := r.session.Query(`
+		UPDATE post_drafts SET published_post_id = ? WHERE user_id = ? AND draft_id = ?
+		IF created_at = ? AND published_post_id = null
+	`, pid, uid, did, createdAt).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return "", fmt.Errorf("failed to claim draft: %w", err)
+	}
+	if applied {
+		return postID, nil
+	}
+	if recorded, ok := existing["published_post_id"].(gocql.UUID); ok && recorded != (gocql.UUID{}) {
+		return recorded.String(), nil
+	}
+	return "", fmt.Errorf("draft not found")
+}
+
+// SetPublishError records why a scheduled draft could not be published and
+// unschedules it, leaving the draft for its author to fix
+func (r *DraftRepository) SetPublishError(ctx context.Context, userID, draftID, reason string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	did, err := gocql.ParseUUID(draftID)
+	if err != nil {
+		return fmt.Errorf("invalid draft_id: %w", err)
+	}
+
+	if _, err := r.session.Query(`
+		UPDATE post_drafts SET publish_error = ?, publish_at = null WHERE user_id = ? AND draft_id = ? IF EXISTS
+	`, reason, uid, did).WithContext(ctx).MapScanCAS(map[string]interface{}{}); err != nil {
+		return fmt.Errorf("failed to record publish error: %w", err)
+	}
+	return r.Unschedule(ctx, userID, draftID)
+}
+
+// scheduleMember is a draft's member in the scheduled posts sorted set
+func scheduleMember(userID, draftID string) string {
+	return userID + ":" + draftID
+}
+
+// Schedule (re)sets when a draft is published
+func (r *DraftRepository) Schedule(ctx context.Context, userID, draftID string, at time.Time) error {
+	if r.redis == nil {
+		return ErrSchedulingUnavailable
+	}
+	if err := r.redis.ZAdd(ctx, scheduledPostsKey, redis.Z{
+		Score:  float64(at.Unix()),
+		Member: scheduleMember(userID, draftID),
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to schedule draft: %w", err)
+	}
+	return nil
+}
+
+// Unschedule removes a draft from the publishing schedule
+func (r *DraftRepository) Unschedule(ctx context.Context, userID, draftID string) error {
+	if r.redis == nil {
+		return nil
+	}
+	if err := r.redis.ZRem(ctx, scheduledPostsKey, scheduleMember(userID, draftID)).Err(); err != nil {
+		return fmt.Errorf("failed to unschedule draft: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDrafts returns up to limit drafts whose publish time is at or before
+// now. Each one is locked for scheduledPostLockTTL so only one worker handles
+// it; a worker that dies mid-publish releases it when the lock expires.
+// Drafts stay in the schedule until they are published, deleted or failed.
+// Drafts another worker holds are skipped, so they do not hold up the ones
+// due after them.
+func (r *DraftRepository) ClaimDueDrafts(ctx context.Context, now time.Time, limit int) ([]ScheduledDraft, error) {
+	if r.redis == nil {
+		return nil, nil
+	}
+
+	var claimed []ScheduledDraft
+	for offset := int64(0); len(claimed) < limit; offset += int64(limit) {
+		members, err := r.redis.ZRangeByScore(ctx, scheduledPostsKey, &redis.ZRangeBy{
+			Min:    "-inf",
+			Max:    strconv.FormatInt(now.Unix(), 10),
+			Offset: offset,
+			Count:  int64(limit),
+		}).Result()
+		if err != nil {
+			return claimed, fmt.Errorf("failed to read scheduled drafts: %w", err)
+		}
+
+		for _, member := range members {
+			userID, draftID, ok := strings.Cut(member, ":")
+			if !ok {
+				r.redis.ZRem(ctx, scheduledPostsKey, member)
+				continue
+			}
+
+			locked, err := r.redis.SetNX(ctx, scheduledPostsKey+":lock:"+member, 1, scheduledPostLockTTL).Result()
+			if err != nil {
+				return claimed, fmt.Errorf("failed to lock scheduled draft: %w", err)
+			}
+			if locked {
+				claimed = append(claimed, ScheduledDraft{UserID: userID, DraftID: draftID})
+				if len(claimed) == limit {
+					break
+				}
+			}
+		}
+
+		if len(members) < limit {
+			break
+		}
+	}
+	return claimed, nil
+}
@@ -0,0 +1,49 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftRepository_Integration(t *testing.T) {
+	repo := NewDraftRepository(testSession, nil)
+	ctx := context.Background()
+	userID := uuid.New().String()
+
+	t.Run("Claim Publish Once", func(t *testing.T) {
+		draft, err := repo.CreateDraft(ctx, userID, &SaveDraftRequest{Content: "Later"})
+		require.NoError(t, err)
+		stored, err := repo.GetDraft(ctx, userID, draft.ID)
+		require.NoError(t, err)
+
+		first := gocql.TimeUUID().String()
+		recorded, err := repo.ClaimPublish(ctx, userID, draft.ID, stored.CreatedAt, first)
+		require.NoError(t, err)
+		assert.Equal(t, first, recorded)
+
+		// A second claim gets the ID recorded by the first
+		recorded, err = repo.ClaimPublish(ctx, userID, draft.ID, stored.CreatedAt, gocql.TimeUUID().String())
+		require.NoError(t, err)
+		assert.Equal(t, first, recorded)
+	})
+
+	t.Run("Claim Deleted Draft", func(t *testing.T) {
+		draft, err := repo.CreateDraft(ctx, userID, &SaveDraftRequest{Content: "Gone"})
+		require.NoError(t, err)
+		stored, err := repo.GetDraft(ctx, userID, draft.ID)
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteDraft(ctx, userID, draft.ID))
+
+		_, err = repo.ClaimPublish(ctx, userID, draft.ID, stored.CreatedAt, gocql.TimeUUID().String())
+		assert.ErrorContains(t, err, "not found")
+
+		// The claim does not bring the draft back
+		_, err = repo.GetDraft(ctx, userID, draft.ID)
+		assert.ErrorContains(t, err, "not found")
+	})
+}
@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -206,6 +207,191 @@ func (r *FollowRepository) GetFollowing(ctx context.Context, userID string, limi
 	return following, nil
 }
 
+// GetFollowerKeys returns a page of userID's followers, most recent first, as
+// keysets of (followed at, follower ID), starting after the keyset
+func (r *FollowRepository) GetFollowerKeys(ctx context.Context, userID string, limit int, after Keyset) ([]Keyset, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var iter *gocql.Iter
+	if after.IsZero() {
+		iter = r.session.Query(`
+			SELECT follower_id, created_at FROM followers WHERE user_id = ?
+		`, uid).WithContext(ctx).PageSize(limit).Iter()
+	} else {
+		iter = r.session.Query(`
+			SELECT follower_id, created_at FROM followers WHERE user_id = ? AND created_at <= ?
+		`, uid, after.CreatedAt).WithContext(ctx).PageSize(limit).Iter()
+	}
+
+	var keys []Keyset
+	var followerID gocql.UUID
+	var createdAt time.Time
+	for iter.Scan(&followerID, &createdAt) {
+		if len(keys) > 0 && keysetScanDone(len(keys), limit, keys[len(keys)-1].CreatedAt, createdAt) {
+			break
+		}
+		if !after.Admits(createdAt, followerID.String()) {
+			continue
+		}
+		keys = append(keys, Keyset{CreatedAt: createdAt, ID: followerID.String()})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating followers: %w", err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return KeysetBefore(keys[i].CreatedAt, keys[i].ID, keys[j].CreatedAt, keys[j].ID)
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	return keys, nil
+}
+
+// GetFollowingKeys returns a page of the accounts userID follows, in the
+// follows table's following_id order, starting after afterID. Each keyset
+// holds (followed at, following ID).
+func (r *FollowRepository) GetFollowingKeys(ctx context.Context, userID string, limit int, afterID string) ([]Keyset, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var iter *gocql.Iter
+	if afterID == "" {
+		iter = r.session.Query(`
+			SELECT following_id, created_at FROM follows WHERE follower_id = ? LIMIT ?
+		`, uid, limit).WithContext(ctx).Iter()
+	} else {
+		aid, err := gocql.ParseUUID(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor id: %w", err)
+		}
+		iter = r.session.Query(`
+			SELECT following_id, created_at FROM follows WHERE follower_id = ? AND following_id > ? LIMIT ?
+		`, uid, aid, limit).WithContext(ctx).Iter()
+	}
+
+	var keys []Keyset
+	var followingID gocql.UUID
+	var createdAt time.Time
+	for iter.Scan(&followingID, &createdAt) {
+		keys = append(keys, Keyset{CreatedAt: createdAt, ID: followingID.String()})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating following: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetAllFollowing returns every account a user follows
+func (r *FollowRepository) GetAllFollowing(ctx context.Context, userID string) ([]string, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT following_id FROM follows WHERE follower_id = ?
+	`, uid).WithContext(ctx).PageSize(1000).Iter()
+
+	var following []string
+	var followingID gocql.UUID
+	for iter.Scan(&followingID) {
+		following = append(following, followingID.String())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating following: %w", err)
+	}
+
+	return following, nil
+}
+
+// GetFollowingAmong returns which of userIDs followerID follows
+func (r *FollowRepository) GetFollowingAmong(ctx context.Context, followerID string, userIDs []string) (map[string]bool, error) {
+	fid, err := gocql.ParseUUID(followerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	const chunkSize = 100
+
+	following := make(map[string]bool)
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := min(start+chunkSize, len(userIDs))
+
+		uuids := make([]gocql.UUID, 0, end-start)
+		for _, id := range userIDs[start:end] {
+			if uid, err := gocql.ParseUUID(id); err == nil {
+				uuids = append(uuids, uid)
+			}
+		}
+
+		iter := r.session.Query(`
+			SELECT following_id FROM follows WHERE follower_id = ? AND following_id IN ?
+		`, fid, uuids).WithContext(ctx).Iter()
+
+		var followingID gocql.UUID
+		for iter.Scan(&followingID) {
+			following[followingID.String()] = true
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("error iterating following: %w", err)
+		}
+	}
+
+	return following, nil
+}
+
+// GetFollowersAmong returns which of userIDs follow followingID
+func (r *FollowRepository) GetFollowersAmong(ctx context.Context, followingID string, userIDs []string) (map[string]bool, error) {
+	fgid, err := gocql.ParseUUID(followingID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	const chunkSize = 100
+
+	followers := make(map[string]bool)
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := min(start+chunkSize, len(userIDs))
+
+		uuids := make([]gocql.UUID, 0, end-start)
+		for _, id := range userIDs[start:end] {
+			if uid, err := gocql.ParseUUID(id); err == nil {
+				uuids = append(uuids, uid)
+			}
+		}
+
+		iter := r.session.Query(`
+			SELECT follower_id FROM follows WHERE follower_id IN ? AND following_id = ?
+		`, uuids, fgid).WithContext(ctx).Iter()
+
+		var followerID gocql.UUID
+		for iter.Scan(&followerID) {
+			followers[followerID.String()] = true
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("error iterating followers: %w", err)
+		}
+	}
+
+	return followers, nil
+}
+
 // GetFollowCounts returns follower and following counts
 func (r *FollowRepository) GetFollowCounts(ctx context.Context, userID string) (*FollowCounts, error) {
 	uid, err := gocql.ParseUUID(userID)
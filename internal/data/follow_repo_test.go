@@ -47,6 +47,40 @@ func TestFollowRepository_Integration(t *testing.T) {
 		assert.Equal(t, int64(1), countsB.FollowersCount)
 	})
 
+	t.Run("Paged Lists", func(t *testing.T) {
+		target := uuid.New().String()
+		fans := []string{uuid.New().String(), uuid.New().String(), uuid.New().String()}
+		for _, fan := range fans {
+			require.NoError(t, repo.Follow(ctx, fan, target))
+			require.NoError(t, repo.Follow(ctx, target, fan))
+		}
+
+		// Followers come most recent first, across pages
+		first, err := repo.GetFollowerKeys(ctx, target, 2, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, first, 2)
+		assert.Equal(t, fans[2], first[0].ID)
+		rest, err := repo.GetFollowerKeys(ctx, target, 2, first[1])
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.Equal(t, fans[0], rest[0].ID)
+
+		// Following pages by following_id without repeats
+		page1, err := repo.GetFollowingKeys(ctx, target, 2, "")
+		require.NoError(t, err)
+		require.Len(t, page1, 2)
+		page2, err := repo.GetFollowingKeys(ctx, target, 2, page1[1].ID)
+		require.NoError(t, err)
+		require.Len(t, page2, 1)
+		seen := map[string]bool{page1[0].ID: true, page1[1].ID: true, page2[0].ID: true}
+		assert.Len(t, seen, 3)
+
+		followedBy, err := repo.GetFollowersAmong(ctx, fans[0], []string{target, userB})
+		require.NoError(t, err)
+		assert.True(t, followedBy[target])
+		assert.False(t, followedBy[userB])
+	})
+
 	t.Run("Unfollow", func(t *testing.T) {
 		err := repo.Unfollow(ctx, userA, userB)
 		require.NoError(t, err)
@@ -54,4 +88,49 @@ func TestFollowRepository_Integration(t *testing.T) {
 		isFollowing, _ := repo.IsFollowing(ctx, userA, userB)
 		assert.False(t, isFollowing)
 	})
+
+	t.Run("Follow Requests", func(t *testing.T) {
+		userC := uuid.New().String()
+
+		require.NoError(t, repo.RequestFollow(ctx, userA, userB))
+		require.NoError(t, repo.RequestFollow(ctx, userC, userB))
+
+		err := repo.RequestFollow(ctx, userA, userB)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already requested")
+
+		requested, err := repo.HasRequestedFollow(ctx, userA, userB)
+		require.NoError(t, err)
+		assert.True(t, requested)
+
+		// Newest first
+		requests, err := repo.GetFollowRequests(ctx, userB, 10, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		assert.Equal(t, userC, requests[0].UserID)
+		assert.Equal(t, userA, requests[1].UserID)
+
+		page, err := repo.GetFollowRequests(ctx, userB, 10, Keyset{CreatedAt: requests[0].RequestedAt, ID: requests[0].UserID})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, userA, page[0].UserID)
+
+		require.NoError(t, repo.AcceptFollowRequest(ctx, userA, userB))
+		isFollowing, err := repo.IsFollowing(ctx, userA, userB)
+		require.NoError(t, err)
+		assert.True(t, isFollowing)
+
+		require.NoError(t, repo.DeleteFollowRequest(ctx, userC, userB))
+		requested, err = repo.HasRequestedFollow(ctx, userC, userB)
+		require.NoError(t, err)
+		assert.False(t, requested)
+
+		requests, err = repo.GetFollowRequests(ctx, userB, 10, Keyset{})
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+
+		err = repo.DeleteFollowRequest(ctx, userC, userB)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
 }
@@ -0,0 +1,215 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// RequestFollow records a pending request from followerID to follow the
+// private account followingID. It fails with "already requested" when a
+// request is pending.
+func (r *FollowRepository) RequestFollow(ctx context.Context, followerID, followingID string) error {
+	fid, err := gocql.ParseUUID(followerID)
+	if err != nil {
+		return fmt.Errorf("invalid follower_id: %w", err)
+	}
+
+	fgid, err := gocql.ParseUUID(followingID)
+	if err != nil {
+		return fmt.Errorf("invalid following_id: %w", err)
+	}
+
+	if followerID == followingID {
+		return fmt.Errorf("cannot follow yourself")
+	}
+
+	// The requester's side is written first with LWT so retries don't add a
+	// second entry to the target's list
+	now := time.Now()
+	applied, err := r.session.Query(`
+		INSERT INTO follow_requests_sent (requester_id, target_id, created_at)
+		VALUES (?, ?, ?)
+		IF NOT EXISTS
+	`, fid, fgid, now).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to request follow: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("follow already requested")
+	}
+
+	err = r.session.Query(`
+		INSERT INTO follow_requests (target_id, created_at, requester_id)
+		VALUES (?, ?, ?)
+	`, fgid, now, fid).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to request follow: %w", err)
+	}
+
+	return nil
+}
+
+// HasRequestedFollow reports whether followerID has a pending request to
+// follow followingID
+func (r *FollowRepository) HasRequestedFollow(ctx context.Context, followerID, followingID string) (bool, error) {
+	_, err := r.getFollowRequestTime(ctx, followerID, followingID)
+	if err != nil {
+		if err.Error() == "follow request not found" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetRequestedFollows returns every account followerID has a pending request
+// to follow
+func (r *FollowRepository) GetRequestedFollows(ctx context.Context, followerID string) ([]string, error) {
+	fid, err := gocql.ParseUUID(followerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid follower_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT target_id FROM follow_requests_sent WHERE requester_id = ?
+	`, fid).WithContext(ctx).PageSize(1000).Iter()
+
+	var requested []string
+	var targetID gocql.UUID
+	for iter.Scan(&targetID) {
+		requested = append(requested, targetID.String())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating follow requests: %w", err)
+	}
+
+	return requested, nil
+}
+
+// getFollowRequestTime returns when followerID asked to follow followingID
+func (r *FollowRepository) getFollowRequestTime(ctx context.Context, followerID, followingID string) (time.Time, error) {
+	fid, err := gocql.ParseUUID(followerID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid follower_id: %w", err)
+	}
+
+	fgid, err := gocql.ParseUUID(followingID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid following_id: %w", err)
+	}
+
+	var createdAt time.Time
+	err = r.session.Query(`
+		SELECT created_at FROM follow_requests_sent WHERE requester_id = ? AND target_id = ?
+	`, fid, fgid).WithContext(ctx).Scan(&createdAt)
+	if err != nil {
+		if err == gocql.ErrNotFound {
+			return time.Time{}, fmt.Errorf("follow request not found")
+		}
+		return time.Time{}, fmt.Errorf("failed to get follow request: %w", err)
+	}
+
+	return createdAt, nil
+}
+
+// DeleteFollowRequest removes a pending request, when it is denied by the
+// target or withdrawn by the requester
+func (r *FollowRepository) DeleteFollowRequest(ctx context.Context, followerID, followingID string) error {
+	createdAt, err := r.getFollowRequestTime(ctx, followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	fid, _ := gocql.ParseUUID(followerID)
+	fgid, _ := gocql.ParseUUID(followingID)
+
+	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`
+		DELETE FROM follow_requests_sent WHERE requester_id = ? AND target_id = ?
+	`, fid, fgid)
+	batch.Query(`
+		DELETE FROM follow_requests WHERE target_id = ? AND created_at = ? AND requester_id = ?
+	`, fgid, createdAt, fid)
+
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to delete follow request: %w", err)
+	}
+
+	return nil
+}
+
+// AcceptFollowRequest turns a pending request into a follow. The request is
+// deleted only once the follow is written, so a failed accept leaves it
+// pending to be retried rather than lost.
+func (r *FollowRepository) AcceptFollowRequest(ctx context.Context, followerID, followingID string) error {
+	if _, err := r.getFollowRequestTime(ctx, followerID, followingID); err != nil {
+		return err
+	}
+	if err := r.Follow(ctx, followerID, followingID); err != nil {
+		return err
+	}
+	return r.DeleteFollowRequest(ctx, followerID, followingID)
+}
+
+// GetFollowRequests returns the pending requests to follow userID, newest
+// first, starting after the keyset
+func (r *FollowRepository) GetFollowRequests(ctx context.Context, userID string, limit int, after Keyset) ([]FollowRequest, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var iter *gocql.Iter
+	if after.IsZero() {
+		iter = r.session.Query(`
+			SELECT requester_id, created_at FROM follow_requests WHERE target_id = ?
+		`, uid).WithContext(ctx).PageSize(limit).Iter()
+	} else {
+		iter = r.session.Query(`
+			SELECT requester_id, created_at FROM follow_requests WHERE target_id = ? AND created_at <= ?
+		`, uid, after.CreatedAt).WithContext(ctx).PageSize(limit).Iter()
+	}
+
+	var requests []FollowRequest
+	var requesterID gocql.UUID
+	var createdAt time.Time
+
+	for iter.Scan(&requesterID, &createdAt) {
+		if len(requests) > 0 && keysetScanDone(len(requests), limit, requests[len(requests)-1].RequestedAt, createdAt) {
+			break
+		}
+		if !after.Admits(createdAt, requesterID.String()) {
+			continue
+		}
+		requests = append(requests, FollowRequest{
+			UserID:      requesterID.String(),
+			RequestedAt: createdAt,
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get follow requests: %w", err)
+	}
+
+	sortFollowRequests(requests)
+	if len(requests) > limit {
+		requests = requests[:limit]
+	}
+
+	return requests, nil
+}
+
+// sortFollowRequests orders requests by (requested_at DESC, user_id ASC)
+func sortFollowRequests(requests []FollowRequest) {
+	sort.Slice(requests, func(i, j int) bool {
+		return KeysetBefore(requests[i].RequestedAt, requests[i].UserID, requests[j].RequestedAt, requests[j].UserID)
+	})
+}
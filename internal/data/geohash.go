@@ -11,8 +11,19 @@ const (
 	DefaultGeohashPrecision = 5
 	// EarthRadiusKM is the radius of Earth in kilometers
 	EarthRadiusKM = 6371.0
+	// MaxCoverCells caps how many partitions a single radius query may touch
+	MaxCoverCells = 32
+
+	kmPerDegreeLat = 111.32
 )
 
+// GeocellPrecisions are the extra precisions posts are indexed at in posts_by_geocell.
+// Precision 5 (DefaultGeohashPrecision) lives in posts_by_geohash.
+var GeocellPrecisions = []uint{3, 4, 6}
+
+// coverPrecisions lists every indexed precision from finest to coarsest
+var coverPrecisions = []uint{6, 5, 4, 3}
+
 // EncodeGeohash converts latitude and longitude to a geohash string
 func EncodeGeohash(lat, lng float64, precision uint) string {
 	return geohash.EncodeWithPrecision(lat, lng, precision)
@@ -38,6 +49,115 @@ func GetNeighbors(lat, lng float64) []string {
 	return result
 }
 
+// GeohashCover picks the finest indexed precision whose cells cover a circle of
+// radiusKM around (lat, lng) with at most MaxCoverCells partitions, and returns
+// that precision with the cells. Cells that lie entirely outside the circle are
+// dropped. Very large radii fall back to the coarsest precision.
+func GeohashCover(lat, lng, radiusKM float64) (uint, []string) {
+	return geohashCoverAt(lat, lng, radiusKM, coverPrecisions)
+}
+
+// geohashCoverAt is GeohashCover restricted to the given precisions, listed
+// from finest to coarsest
+func geohashCoverAt(lat, lng, radiusKM float64, precisions []uint) (uint, []string) {
+	for _, precision := range precisions {
+		if cells, ok := coverCells(lat, lng, radiusKM, precision, MaxCoverCells); ok {
+			return precision, cells
+		}
+	}
+
+	coarsest := precisions[len(precisions)-1]
+	cells, _ := coverCells(lat, lng, radiusKM, coarsest, 0)
+	return coarsest, cells
+}
+
+// coverCells returns every geohash cell of the given precision that intersects
+// the circle of radiusKM around (lat, lng). When maxCells > 0 and the cover
+// needs more cells than that, it returns false.
+func coverCells(lat, lng, radiusKM float64, precision uint, maxCells int) ([]string, bool) {
+	center := geohash.EncodeWithPrecision(lat, lng, precision)
+	box := geohash.BoundingBox(center)
+	cellHeight := box.MaxLat - box.MinLat
+	cellWidth := box.MaxLng - box.MinLng
+
+	dLat := radiusKM / kmPerDegreeLat
+	dLng := 180.0
+	if cosLat := math.Cos(lat * math.Pi / 180); cosLat > 1e-6 {
+		dLng = math.Min(180, radiusKM/(kmPerDegreeLat*cosLat))
+	}
+
+	minLat, maxLat := math.Max(-90, lat-dLat), math.Min(90, lat+dLat)
+	minLng, maxLng := lng-dLng, lng+dLng
+
+	// Bail out before enumerating a grid far larger than we are willing to query
+	if maxCells > 0 {
+		rows := math.Ceil((maxLat-minLat)/cellHeight) + 1
+		cols := math.Ceil((maxLng-minLng)/cellWidth) + 1
+		if rows*cols > float64(4*maxCells) {
+			return nil, false
+		}
+	}
+
+	seen := map[string]bool{center: true}
+	cells := []string{center}
+
+	// Sample the bounding box at cell-sized steps; every cell the box touches
+	// receives at least one sample point.
+	for sLat := minLat; ; sLat += cellHeight {
+		sLat = math.Min(sLat, maxLat)
+		for sLng := minLng; ; sLng += cellWidth {
+			sLng = math.Min(sLng, maxLng)
+
+			cell := geohash.EncodeWithPrecision(sLat, normalizeLng(sLng), precision)
+			if !seen[cell] {
+				seen[cell] = true
+				if cellIntersectsCircle(cell, lat, lng, radiusKM) {
+					cells = append(cells, cell)
+				}
+			}
+
+			if sLng >= maxLng {
+				break
+			}
+		}
+		if sLat >= maxLat {
+			break
+		}
+	}
+
+	if maxCells > 0 && len(cells) > maxCells {
+		return nil, false
+	}
+
+	return cells, true
+}
+
+// cellIntersectsCircle reports whether any point of the cell is within radiusKM of (lat, lng)
+func cellIntersectsCircle(cell string, lat, lng, radiusKM float64) bool {
+	box := geohash.BoundingBox(cell)
+	nearestLat := math.Max(box.MinLat, math.Min(lat, box.MaxLat))
+
+	// Try the center on both sides of the antimeridian
+	for _, centerLng := range []float64{lng, lng - 360, lng + 360} {
+		nearestLng := math.Max(box.MinLng, math.Min(centerLng, box.MaxLng))
+		if HaversineDistance(lat, centerLng, nearestLat, nearestLng) <= radiusKM {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeLng wraps a longitude into [-180, 180)
+func normalizeLng(lng float64) float64 {
+	for lng < -180 {
+		lng += 360
+	}
+	for lng >= 180 {
+		lng -= 360
+	}
+	return lng
+}
+
 // HaversineDistance calculates the distance between two points on Earth
 // using the Haversine formula. Returns distance in kilometers.
 func HaversineDistance(lat1, lng1, lat2, lng2 float64) float64 {
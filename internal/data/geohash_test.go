@@ -0,0 +1,58 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/mmcloughlin/geohash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeohashCover(t *testing.T) {
+	lat, lng := -6.1754, 106.8272 // Monas, Jakarta
+
+	t.Run("Small Radius Uses Fine Cells", func(t *testing.T) {
+		precision, cells := GeohashCover(lat, lng, 0.5)
+		assert.Equal(t, uint(6), precision)
+		assert.LessOrEqual(t, len(cells), MaxCoverCells)
+		assert.Equal(t, EncodeGeohash(lat, lng, 6), cells[0])
+	})
+
+	t.Run("Large Radius Uses Coarse Cells", func(t *testing.T) {
+		precision, cells := GeohashCover(lat, lng, 50)
+		assert.LessOrEqual(t, precision, uint(4))
+		assert.LessOrEqual(t, len(cells), MaxCoverCells)
+	})
+
+	t.Run("Cover Contains Points Inside Radius", func(t *testing.T) {
+		for _, radius := range []float64{0.5, 5, 10, 50, 200} {
+			precision, cells := GeohashCover(lat, lng, radius)
+			covered := make(map[string]bool, len(cells))
+			for _, c := range cells {
+				covered[c] = true
+			}
+
+			// Points on the circle at 95% of the radius in 8 directions
+			d := radius * 0.95 / kmPerDegreeLat
+			for _, off := range [][2]float64{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {0.7, 0.7}, {-0.7, 0.7}, {0.7, -0.7}, {-0.7, -0.7}} {
+				pLat := lat + off[0]*d
+				pLng := lng + off[1]*d/0.9945 // cos(-6.17°)
+				assert.True(t, covered[EncodeGeohash(pLat, pLng, precision)],
+					"radius %.1fkm: point (%.4f, %.4f) not covered", radius, pLat, pLng)
+			}
+		}
+	})
+
+	t.Run("Antimeridian", func(t *testing.T) {
+		_, cells := GeohashCover(0, 179.99, 20)
+		var east, west bool
+		for _, c := range cells {
+			if _, lng := geohash.DecodeCenter(c); lng > 0 {
+				east = true
+			} else {
+				west = true
+			}
+		}
+		assert.True(t, east)
+		assert.True(t, west)
+	})
+}
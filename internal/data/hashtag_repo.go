@@ -0,0 +1,282 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// maxHashtagScan bounds how many posts_by_hashtag rows one read may scan, so
+// a geo filter that matches little cannot walk a whole hashtag
+const maxHashtagScan = 2000
+
+// GeoFilter limits a listing to posts within RadiusKM of a point
+type GeoFilter struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKM  float64
+}
+
+// contains reports whether a post stored at (lat, lng) is inside the filter.
+// Posts with a hidden location never are.
+func (g *GeoFilter) contains(lat, lng float64, locationPrecision string) bool {
+	if postLocationPrecision(locationPrecision) == LocationPrecisionHidden {
+		return false
+	}
+	return HaversineDistance(g.Latitude, g.Longitude, lat, lng) <= g.RadiusKM
+}
+
+// addHashtagsToBatch adds the hashtag page entries of a public post to the
+// batch creating or editing it. The entries share the post's TTL.
+func addHashtagsToBatch(batch *gocql.Batch, tags []string, postID, userID gocql.UUID, createdAt time.Time, lat, lng float64, locationPrecision string, ttl int) {
+	bucket := HashtagBucket(createdAt)
+	for _, tag := range tags {
+		batch.Query(`
+			INSERT INTO posts_by_hashtag (hashtag, bucket, created_at, post_id, user_id, latitude, longitude, location_precision)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			USING TTL ?
+		`, tag, bucket, createdAt, postID, userID, lat, lng, locationPrecision, ttl)
+
+		// Bucket rows never expire: an ephemeral post shares its bucket with
+		// posts that stay
+		batch.Query(`
+			INSERT INTO hashtag_buckets (hashtag, bucket) VALUES (?, ?)
+		`, tag, bucket)
+	}
+}
+
+// removeHashtagsFromBatch adds the deletion of a post's hashtag page entries
+// to the batch
+func removeHashtagsFromBatch(batch *gocql.Batch, tags []string, postID gocql.UUID, createdAt time.Time) {
+	bucket := HashtagBucket(createdAt)
+	for _, tag := range tags {
+		batch.Query(`DELETE FROM posts_by_hashtag WHERE hashtag = ? AND bucket = ? AND created_at = ? AND post_id = ?`,
+			tag, bucket, createdAt, postID)
+	}
+}
+
+// HashtagRepository reads hashtag pages and stores hashtag follows. Posts are
+// added to hashtag pages by PostRepository as they are written.
+type HashtagRepository struct {
+	session *gocql.Session
+}
+
+// NewHashtagRepository creates a new HashtagRepository
+func NewHashtagRepository(session *gocql.Session) *HashtagRepository {
+	return &HashtagRepository{session: session}
+}
+
+// GetHashtagPostKeys returns the newest public posts tagged with tag that
+// come after the given keyset, at most limit. With near set, only posts
+// inside it are returned. A read stops after maxHashtagScan rows, so fewer
+// than limit keys may come back while older posts remain: resume is where
+// the next read continues, and is zero once the tag has no older posts.
+func (r *HashtagRepository) GetHashtagPostKeys(ctx context.Context, tag string, limit int, after Keyset, near *GeoFilter) (keys []Keyset, resume Keyset, err error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	start := time.Now()
+	if !after.IsZero() {
+		start = after.CreatedAt
+	}
+
+	// Walk the tag's daily partitions newest first
+	buckets := r.session.Query(`
+		SELECT bucket FROM hashtag_buckets WHERE hashtag = ? AND bucket <= ?
+	`, tag, HashtagBucket(start)).WithContext(ctx).PageSize(30).Iter()
+
+	var bucket time.Time
+	var last Keyset // The last row scanned
+	scanned := 0
+	done, capped := false, false
+	for !done && !capped && buckets.Scan(&bucket) {
+		var iter *gocql.Iter
+		if after.IsZero() {
+			iter = r.session.Query(`
+				SELECT created_at, post_id, latitude, longitude, location_precision FROM posts_by_hashtag
+				WHERE hashtag = ? AND bucket = ?
+			`, tag, bucket).WithContext(ctx).PageSize(limit * 2).Iter()
+		} else {
+			iter = r.session.Query(`
+				SELECT created_at, post_id, latitude, longitude, location_precision FROM posts_by_hashtag
+				WHERE hashtag = ? AND bucket = ? AND created_at <= ?
+			`, tag, bucket, after.CreatedAt).WithContext(ctx).PageSize(limit * 2).Iter()
+		}
+
+		var createdAt time.Time
+		var postID gocql.UUID
+		var lat, lng float64
+		var precision string
+		for iter.Scan(&createdAt, &postID, &lat, &lng, &precision) {
+			if len(keys) > 0 && keysetScanDone(len(keys), limit, keys[len(keys)-1].CreatedAt, createdAt) {
+				done = true
+				break
+			}
+			// Stop between timestamps, so rows sharing one are read together
+			if scanned >= maxHashtagScan && !createdAt.Equal(last.CreatedAt) {
+				capped = true
+				break
+			}
+			scanned++
+
+			id := postID.String()
+			if !after.Admits(createdAt, id) {
+				continue
+			}
+			last = Keyset{CreatedAt: createdAt, ID: id}
+			if near != nil && !near.contains(lat, lng, precision) {
+				continue
+			}
+			keys = append(keys, last)
+		}
+		if err := iter.Close(); err != nil {
+			buckets.Close()
+			return nil, Keyset{}, fmt.Errorf("error iterating hashtag posts: %w", err)
+		}
+	}
+	if err := buckets.Close(); err != nil {
+		return nil, Keyset{}, fmt.Errorf("error iterating hashtag buckets: %w", err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return KeysetBefore(keys[i].CreatedAt, keys[i].ID, keys[j].CreatedAt, keys[j].ID)
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	switch {
+	case done || len(keys) == limit:
+		resume = keys[len(keys)-1]
+	case capped:
+		resume = last
+	}
+	return keys, resume, nil
+}
+
+// FollowHashtag makes userID follow tag. Following a tag again keeps the
+// time it was first followed.
+func (r *HashtagRepository) FollowHashtag(ctx context.Context, userIDStr, tag string) (*HashtagFollow, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	var count int
+	if err := r.session.Query(`
+		SELECT COUNT(*) FROM hashtag_follows WHERE user_id = ?
+	`, userID).WithContext(ctx).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count followed hashtags: %w", err)
+	}
+	if count >= MaxFollowedHashtags {
+		following, err := r.IsFollowingHashtag(ctx, userIDStr, tag)
+		if err != nil {
+			return nil, err
+		}
+		if !following {
+			return nil, fmt.Errorf("too many followed hashtags: at most %d", MaxFollowedHashtags)
+		}
+	}
+
+	now := time.Now()
+	existing := make(map[string]interface{})
+	applied, err := r.session.Query(`
+		INSERT INTO hashtag_follows (user_id, hashtag, created_at) VALUES (?, ?, ?) IF NOT EXISTS
+	`, userID, tag, now).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow hashtag: %w", err)
+	}
+
+	follow := &HashtagFollow{Hashtag: tag, CreatedAt: now}
+	if t, ok := existing["created_at"].(time.Time); !applied && ok {
+		follow.CreatedAt = t
+	}
+
+	// Written on every follow, so a follow whose second write failed is repaired
+	if err := r.session.Query(`
+		INSERT INTO hashtag_followers (hashtag, user_id, created_at) VALUES (?, ?, ?)
+	`, tag, userID, follow.CreatedAt).WithContext(ctx).Exec(); err != nil {
+		return nil, fmt.Errorf("failed to follow hashtag: %w", err)
+	}
+
+	return follow, nil
+}
+
+// UnfollowHashtag stops userID following tag. It is a no-op if they don't.
+func (r *HashtagRepository) UnfollowHashtag(ctx context.Context, userIDStr, tag string) error {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`DELETE FROM hashtag_follows WHERE user_id = ? AND hashtag = ?`, userID, tag)
+	batch.Query(`DELETE FROM hashtag_followers WHERE hashtag = ? AND user_id = ?`, tag, userID)
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to unfollow hashtag: %w", err)
+	}
+	return nil
+}
+
+// IsFollowingHashtag reports whether userID follows tag
+func (r *HashtagRepository) IsFollowingHashtag(ctx context.Context, userIDStr, tag string) (bool, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	var createdAt time.Time
+	err = r.session.Query(`
+		SELECT created_at FROM hashtag_follows WHERE user_id = ? AND hashtag = ?
+	`, userID, tag).WithContext(ctx).Scan(&createdAt)
+	if err == gocql.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check hashtag follow: %w", err)
+	}
+	return true, nil
+}
+
+// GetFollowedHashtags returns the hashtags userID follows, in alphabetical order
+func (r *HashtagRepository) GetFollowedHashtags(ctx context.Context, userIDStr string) ([]HashtagFollow, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT hashtag, created_at FROM hashtag_follows WHERE user_id = ?
+	`, userID).WithContext(ctx).Iter()
+
+	follows := []HashtagFollow{}
+	var follow HashtagFollow
+	for iter.Scan(&follow.Hashtag, &follow.CreatedAt) {
+		follows = append(follows, follow)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get followed hashtags: %w", err)
+	}
+	return follows, nil
+}
+
+// GetHashtagFollowers returns the IDs of the users following tag
+func (r *HashtagRepository) GetHashtagFollowers(ctx context.Context, tag string) ([]string, error) {
+	iter := r.session.Query(`
+		SELECT user_id FROM hashtag_followers WHERE hashtag = ?
+	`, tag).WithContext(ctx).Iter()
+
+	var userIDs []string
+	var userID gocql.UUID
+	for iter.Scan(&userID) {
+		userIDs = append(userIDs, userID.String())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get hashtag followers: %w", err)
+	}
+	return userIDs, nil
+}
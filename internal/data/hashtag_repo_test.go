@@ -0,0 +1,108 @@
+package data
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashtagRepository_Integration(t *testing.T) {
+	repo := NewHashtagRepository(testSession)
+	postRepo := NewPostRepository(testSession, nil)
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	// Unique per run, so earlier runs don't show up on the page
+	tag := "t" + strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+
+	createPost := func(content, visibility string, lat, lng float64) *Post {
+		post, err := postRepo.CreatePost(ctx, &CreatePostRequest{
+			UserID: userID, Content: content, Visibility: visibility,
+			Latitude: lat, Longitude: lng,
+		})
+		require.NoError(t, err)
+		return post
+	}
+
+	jakarta := createPost("Kopi in Jakarta #"+tag, VisibilityPublic, -6.2088, 106.8456)
+	bandung := createPost("Kopi in Bandung #"+strings.ToUpper(tag), VisibilityPublic, -6.9175, 107.6191)
+	createPost("Followers only #"+tag, VisibilityFollowers, -6.2088, 106.8456)
+
+	t.Run("Public Posts Newest First", func(t *testing.T) {
+		keys, resume, err := repo.GetHashtagPostKeys(ctx, tag, 10, Keyset{}, nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, bandung.ID, keys[0].ID)
+		assert.Equal(t, jakarta.ID, keys[1].ID)
+		assert.True(t, resume.IsZero())
+
+		// Paging continues after the cursor
+		keys, resume, err = repo.GetHashtagPostKeys(ctx, tag, 1, Keyset{}, nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, bandung.ID, resume.ID)
+
+		keys, _, err = repo.GetHashtagPostKeys(ctx, tag, 1, resume, nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, jakarta.ID, keys[0].ID)
+	})
+
+	t.Run("Geo Filter", func(t *testing.T) {
+		near := &GeoFilter{Latitude: -6.2088, Longitude: 106.8456, RadiusKM: 20}
+		keys, _, err := repo.GetHashtagPostKeys(ctx, tag, 10, Keyset{}, near)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, jakarta.ID, keys[0].ID)
+	})
+
+	t.Run("Edit And Delete", func(t *testing.T) {
+		_, err := postRepo.UpdatePost(ctx, bandung.ID, userID, &UpdatePostRequest{Content: "No tags now"}, 0)
+		require.NoError(t, err)
+
+		keys, _, err := repo.GetHashtagPostKeys(ctx, tag, 10, Keyset{}, nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, jakarta.ID, keys[0].ID)
+
+		require.NoError(t, postRepo.DeletePost(ctx, jakarta.ID, userID))
+
+		keys, resume, err := repo.GetHashtagPostKeys(ctx, tag, 10, Keyset{}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+		assert.True(t, resume.IsZero())
+	})
+
+	t.Run("Follows", func(t *testing.T) {
+		follow, err := repo.FollowHashtag(ctx, userID, tag)
+		require.NoError(t, err)
+
+		// Following again keeps the first follow
+		again, err := repo.FollowHashtag(ctx, userID, tag)
+		require.NoError(t, err)
+		assert.WithinDuration(t, follow.CreatedAt, again.CreatedAt, time.Millisecond)
+
+		following, err := repo.IsFollowingHashtag(ctx, userID, tag)
+		require.NoError(t, err)
+		assert.True(t, following)
+
+		followers, err := repo.GetHashtagFollowers(ctx, tag)
+		require.NoError(t, err)
+		assert.Equal(t, []string{userID}, followers)
+
+		follows, err := repo.GetFollowedHashtags(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, follows, 1)
+		assert.Equal(t, tag, follows[0].Hashtag)
+
+		require.NoError(t, repo.UnfollowHashtag(ctx, userID, tag))
+		followers, err = repo.GetHashtagFollowers(ctx, tag)
+		require.NoError(t, err)
+		assert.Empty(t, followers)
+	})
+}
@@ -0,0 +1,71 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Hashtag limits
+const (
+	MaxPostHashtags     = 20 // Hashtags of a post listed on hashtag pages
+	MaxHashtagLength    = 100
+	MaxFollowedHashtags = 200
+)
+
+var hashtagPattern = regexp.MustCompile(`#([A-Za-z0-9_]+)`)
+var hashtagNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// ExtractHashtags returns lowercase hashtag tokens without the leading '#'.
+func ExtractHashtags(content string) []string {
+	matches := hashtagPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(matches))
+	hashtags := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if len(match) < 2 {
+			continue
+		}
+		tag := strings.ToLower(match[1])
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		hashtags = append(hashtags, tag)
+	}
+	return hashtags
+}
+
+// PostHashtags returns the hashtags of content that a post is listed under:
+// the first MaxPostHashtags of at most MaxHashtagLength characters
+func PostHashtags(content string) []string {
+	var tags []string
+	for _, tag := range ExtractHashtags(content) {
+		if len(tag) > MaxHashtagLength {
+			continue
+		}
+		tags = append(tags, tag)
+		if len(tags) == MaxPostHashtags {
+			break
+		}
+	}
+	return tags
+}
+
+// NormalizeHashtag returns tag as it is stored: lowercase, without a leading '#'
+func NormalizeHashtag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" || len(tag) > MaxHashtagLength || !hashtagNamePattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid hashtag: %q", tag)
+	}
+	return tag, nil
+}
+
+// HashtagBucket returns the posts_by_hashtag partition for a post created at t
+func HashtagBucket(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
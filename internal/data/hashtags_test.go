@@ -0,0 +1,57 @@
+package data
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostHashtags(t *testing.T) {
+	t.Run("Lowercase And Distinct", func(t *testing.T) {
+		assert.Equal(t, []string{"kopi", "jakarta"}, PostHashtags("#Kopi in #jakarta, more #KOPI"))
+	})
+
+	t.Run("Overlong Tags Are Skipped", func(t *testing.T) {
+		long := strings.Repeat("a", MaxHashtagLength+1)
+		assert.Equal(t, []string{"ok"}, PostHashtags("#"+long+" #ok"))
+	})
+
+	t.Run("At Most MaxPostHashtags", func(t *testing.T) {
+		var b strings.Builder
+		for i := 0; i < MaxPostHashtags+5; i++ {
+			b.WriteString(" #tag")
+			b.WriteByte(byte('a' + i))
+		}
+		tags := PostHashtags(b.String())
+		require.Len(t, tags, MaxPostHashtags)
+		assert.Equal(t, "taga", tags[0])
+	})
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tag, err := NormalizeHashtag("#Kopi_Susu")
+	require.NoError(t, err)
+	assert.Equal(t, "kopi_susu", tag)
+
+	for _, bad := range []string{"", "#", "kopi susu", "kopi-susu", "café", strings.Repeat("a", MaxHashtagLength+1)} {
+		_, err := NormalizeHashtag(bad)
+		assert.ErrorContains(t, err, "invalid hashtag", bad)
+	}
+}
+
+func TestHashtagBucket(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	bucket := HashtagBucket(time.Date(2026, 3, 2, 5, 30, 0, 0, jakarta))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), bucket)
+}
+
+func TestGeoFilterContains(t *testing.T) {
+	near := &GeoFilter{Latitude: -6.2088, Longitude: 106.8456, RadiusKM: 10}
+
+	assert.True(t, near.contains(-6.2, 106.85, LocationPrecisionExact))
+	assert.False(t, near.contains(-6.9175, 107.6191, LocationPrecisionExact)) // Bandung
+	assert.False(t, near.contains(0, 0, LocationPrecisionHidden))
+}
@@ -3,6 +3,8 @@ package data
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -32,149 +34,223 @@ type ToggleLikeResult struct {
 	Changed   bool  `json:"changed"` // Whether the state actually changed
 }
 
+// NormalizeReaction validates a reaction. A bare heart without the emoji
+// variation selector is accepted as ❤️.
+func NormalizeReaction(reaction string) (string, error) {
+	reaction = strings.TrimSpace(reaction)
+	if reaction == "❤" {
+		return ReactionLove, nil
+	}
+	for _, valid := range ValidReactions {
+		if reaction == valid {
+			return reaction, nil
+		}
+	}
+	return "", fmt.Errorf("invalid reaction")
+}
+
+// ReactionResult represents a user's reaction to a target after a change
+type ReactionResult struct {
+	MyReaction     string           `json:"my_reaction"`     // Empty when the user has no reaction
+	ReactionCounts map[string]int64 `json:"reaction_counts"` // Non-zero counts, ❤️ included
+	Changed        bool             `json:"changed"`         // Whether the state actually changed
+	Previous       string           `json:"-"`               // The reaction replaced or removed, if any
+}
+
+// LikeCount returns the number of likes (❤️ reactions) on the target
+func (r *ReactionResult) LikeCount() int64 {
+	return r.ReactionCounts[ReactionLove]
+}
+
+// maxReactionAttempts bounds how often a reaction change that raced another
+// change to the same reaction is retried
+const maxReactionAttempts = 3
+
 // ToggleLike toggles the like state for a user on a target (post or comment)
 // This is idempotent - calling it twice with the same desired state will not double-count
-// Uses Cassandra LWT for atomic state changes, Redis for counter updates
+// A like is the ❤️ reaction: liking replaces another reaction, and unliking
+// leaves another reaction alone
 func (r *LikeRepository) ToggleLike(ctx context.Context, targetType, targetID, userID string, wantLiked bool) (*ToggleLikeResult, error) {
-	tid, err := gocql.ParseUUID(targetID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid target_id: %w", err)
+	reaction, onlyRemove := ReactionLove, ""
+	if !wantLiked {
+		reaction, onlyRemove = "", ReactionLove
 	}
 
-	uid, err := gocql.ParseUUID(userID)
+	result, err := r.setReaction(ctx, targetType, targetID, userID, reaction, onlyRemove)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user_id: %w", err)
+		return nil, err
 	}
 
-	now := time.Now()
-
-	if wantLiked {
-		// Try to add like using LWT (IF NOT EXISTS)
-		return r.tryAddLike(ctx, targetType, tid, uid, now)
-	} else {
-		// Try to remove like using LWT (IF EXISTS)
-		return r.tryRemoveLike(ctx, targetType, tid, uid)
-	}
+	return &ToggleLikeResult{
+		IsLiked:   result.MyReaction == ReactionLove,
+		LikeCount: result.LikeCount(),
+		Changed:   result.Changed,
+	}, nil
 }
 
-// tryAddLike attempts to add a like using LWT for idempotency
-func (r *LikeRepository) tryAddLike(ctx context.Context, targetType string, targetID, userID gocql.UUID, now time.Time) (*ToggleLikeResult, error) {
-	// Use LWT: INSERT ... IF NOT EXISTS
-	// This returns applied=true only if the row didn't exist
-	// LWT queries return a map with [applied] column
-	resultMap := make(map[string]interface{})
-	applied, err := r.session.Query(`
-		INSERT INTO like_state (target_type, target_id, user_id, created_at)
-		VALUES (?, ?, ?, ?)
-		IF NOT EXISTS
-	`, targetType, targetID, userID, now).
-		WithContext(ctx).
-		MapScanCAS(resultMap)
+// SetReaction sets a user's reaction to a target (post or comment), replacing
+// any other reaction. An empty reaction removes it. Like ToggleLike, this is
+// idempotent.
+func (r *LikeRepository) SetReaction(ctx context.Context, targetType, targetID, userID, reaction string) (*ReactionResult, error) {
+	return r.setReaction(ctx, targetType, targetID, userID, reaction, "")
+}
 
+// setReaction changes a user's reaction using Cassandra LWT conditioned on the
+// current reaction, so concurrent changes never double-count, and updates the
+// counters only when the change applied. When removing with onlyRemove set,
+// only that reaction is removed.
+func (r *LikeRepository) setReaction(ctx context.Context, targetType, targetID, userID, reaction, onlyRemove string) (*ReactionResult, error) {
+	tid, err := gocql.ParseUUID(targetID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add like: %w", err)
+		return nil, fmt.Errorf("invalid target_id: %w", err)
 	}
 
-	result := &ToggleLikeResult{
-		IsLiked: true,
-		Changed: applied, // applied=true means the insert succeeded (row didn't exist before)
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
 	}
 
-	// Only increment counter if state actually changed
-	if applied && r.likeCounter != nil {
-		count, err := r.likeCounter.IncrementLikeCount(ctx, targetType, targetID.String())
+	for attempt := 0; attempt < maxReactionAttempts; attempt++ {
+		stored, reactedAt, found, err := r.readReaction(ctx, targetType, tid, uid)
 		if err != nil {
-			// Log but don't fail - state change succeeded
-			fmt.Printf("WARNING: failed to increment Redis counter: %v\n", err)
-			// Schedule async counter sync
-			go r.SyncCounterFromCassandra(context.Background(), targetType, targetID.String())
-			// Fall back to getting estimate
-			count, _ = r.getLikeCountFallback(ctx, targetType, targetID.String())
+			return nil, err
 		}
-		result.LikeCount = count
-	} else if r.likeCounter != nil {
-		// State didn't change, just get current count
-		count, _ := r.likeCounter.GetLikeCount(ctx, targetType, targetID.String())
-		result.LikeCount = count
-	}
+		current := reactionFromState(stored, found)
+
+		if current == reaction || (reaction == "" && onlyRemove != "" && current != onlyRemove) {
+			return &ReactionResult{
+				MyReaction:     current,
+				ReactionCounts: r.getReactionCounts(ctx, targetType, targetID),
+			}, nil
+		}
+
+		// Rows written before reactions existed have no reaction: they are likes
+		var condition interface{}
+		if stored != "" {
+			condition = stored
+		}
+
+		now := time.Now()
+		resultMap := make(map[string]interface{})
+		var applied bool
+		switch {
+		case !found:
+			applied, err = r.session.Query(`
+				INSERT INTO like_state (target_type, target_id, user_id, created_at, reaction)
+				VALUES (?, ?, ?, ?, ?)
+				IF NOT EXISTS
+			`, targetType, tid, uid, now, reaction).WithContext(ctx).MapScanCAS(resultMap)
+		case reaction == "":
+			applied, err = r.session.Query(`
+				DELETE FROM like_state
+				WHERE target_type = ? AND target_id = ? AND user_id = ?
+				IF reaction = ?
+			`, targetType, tid, uid, condition).WithContext(ctx).MapScanCAS(resultMap)
+		default:
+			applied, err = r.session.Query(`
+				UPDATE like_state SET reaction = ?, created_at = ?
+				WHERE target_type = ? AND target_id = ? AND user_id = ?
+				IF reaction = ?
+			`, reaction, now, targetType, tid, uid, condition).WithContext(ctx).MapScanCAS(resultMap)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to set reaction: %w", err)
+		}
+		if !applied {
+			// Another change won the race; re-read the current reaction
+			continue
+		}
+
+		r.applyReactionChange(ctx, targetType, tid, uid, current, reaction, reactedAt, now)
 
-	// Also maintain the legacy likes table for backward compatibility
-	if applied {
-		go r.insertLegacyLike(context.Background(), targetType, targetID, userID, now)
-		go r.insertLikeByUser(context.Background(), targetType, targetID, userID, now)
+		return &ReactionResult{
+			MyReaction:     reaction,
+			ReactionCounts: r.getReactionCounts(ctx, targetType, targetID),
+			Changed:        true,
+			Previous:       current,
+		}, nil
 	}
 
-	return result, nil
+	return nil, fmt.Errorf("failed to set reaction: too many concurrent changes")
 }
 
-// tryRemoveLike attempts to remove a like using LWT for idempotency
-func (r *LikeRepository) tryRemoveLike(ctx context.Context, targetType string, targetID, userID gocql.UUID) (*ToggleLikeResult, error) {
-	// First check if the like exists (required for conditional delete)
-	var existingTime time.Time
-	err := r.session.Query(`
-		SELECT created_at FROM like_state
+// readReaction reads a user's like_state row for a target. stored is the raw
+// reaction column, empty on rows written before reactions existed.
+func (r *LikeRepository) readReaction(ctx context.Context, targetType string, targetID, userID gocql.UUID) (stored string, reactedAt time.Time, found bool, err error) {
+	err = r.session.Query(`
+		SELECT reaction, created_at FROM like_state
 		WHERE target_type = ? AND target_id = ? AND user_id = ?
 	`, targetType, targetID, userID).
 		WithContext(ctx).
 		Consistency(gocql.One).
-		Scan(&existingTime)
+		Scan(&stored, &reactedAt)
 
 	if err == gocql.ErrNotFound {
-		// Already not liked - return current state without change
-		result := &ToggleLikeResult{
-			IsLiked: false,
-			Changed: false,
-		}
-		if r.likeCounter != nil {
-			result.LikeCount, _ = r.likeCounter.GetLikeCount(ctx, targetType, targetID.String())
-		}
-		return result, nil
+		return "", time.Time{}, false, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to check like state: %w", err)
-	}
-
-	// Like exists, delete it using LWT
-	// Use MapScanCAS for LWT queries
-	resultMap := make(map[string]interface{})
-	applied, err := r.session.Query(`
-		DELETE FROM like_state
-		WHERE target_type = ? AND target_id = ? AND user_id = ?
-		IF EXISTS
-	`, targetType, targetID, userID).
-		WithContext(ctx).
-		MapScanCAS(resultMap)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to remove like: %w", err)
+		return "", time.Time{}, false, fmt.Errorf("failed to check like state: %w", err)
 	}
+	return stored, reactedAt, true, nil
+}
 
-	result := &ToggleLikeResult{
-		IsLiked: false,
-		Changed: applied,
+// reactionFromState returns the reaction of a like_state row
+func reactionFromState(stored string, found bool) string {
+	if found && stored == "" {
+		return ReactionLove
 	}
+	return stored
+}
 
-	// Only decrement counter if state actually changed
-	if applied && r.likeCounter != nil {
-		count, err := r.likeCounter.DecrementLikeCount(ctx, targetType, targetID.String())
+// applyReactionChange updates the counters and the like tables after a
+// user's reaction changed from old to new. Either may be empty.
+func (r *LikeRepository) applyReactionChange(ctx context.Context, targetType string, targetID, userID gocql.UUID, old, new string, oldAt, newAt time.Time) {
+	if r.likeCounter != nil {
+		var err error
+		if old != "" {
+			err = r.decrementReactionCount(ctx, targetType, targetID.String(), old)
+		}
+		if new != "" && err == nil {
+			err = r.incrementReactionCount(ctx, targetType, targetID.String(), new)
+		}
 		if err != nil {
-			fmt.Printf("WARNING: failed to decrement Redis counter: %v\n", err)
+			// Log but don't fail - state change succeeded
+			fmt.Printf("WARNING: failed to update Redis counter: %v\n", err)
+			// Schedule async counter sync
 			go r.SyncCounterFromCassandra(context.Background(), targetType, targetID.String())
-			count, _ = r.getLikeCountFallback(ctx, targetType, targetID.String())
 		}
-		result.LikeCount = count
-	} else if r.likeCounter != nil {
-		count, _ := r.likeCounter.GetLikeCount(ctx, targetType, targetID.String())
-		result.LikeCount = count
 	}
 
-	// Also delete from legacy likes table
-	if applied {
+	// The legacy likes table, likes_by_user and likes_by_target only hold likes
+	if old == ReactionLove {
 		go r.deleteLegacyLike(context.Background(), targetType, targetID, userID)
-		go r.deleteLikeByUser(context.Background(), targetType, targetID, userID)
+		go r.deleteLikeByUser(context.Background(), targetType, targetID, userID, oldAt)
+		go r.deleteLikeByTarget(context.Background(), targetType, targetID, userID, oldAt)
+	}
+	if new == ReactionLove {
+		go r.insertLegacyLike(context.Background(), targetType, targetID, userID, newAt)
+		go r.insertLikeByUser(context.Background(), targetType, targetID, userID, newAt)
+		go r.insertLikeByTarget(context.Background(), targetType, targetID, userID, newAt)
 	}
+}
 
-	return result, nil
+// incrementReactionCount increments the Redis counter of a reaction. Likes
+// keep their own counter.
+func (r *LikeRepository) incrementReactionCount(ctx context.Context, targetType, targetID, reaction string) error {
+	if reaction == ReactionLove {
+		_, err := r.likeCounter.IncrementLikeCount(ctx, targetType, targetID)
+		return err
+	}
+	return r.likeCounter.IncrementReactionCount(ctx, targetType, targetID, reaction)
+}
+
+// decrementReactionCount decrements the Redis counter of a reaction
+func (r *LikeRepository) decrementReactionCount(ctx context.Context, targetType, targetID, reaction string) error {
+	if reaction == ReactionLove {
+		_, err := r.likeCounter.DecrementLikeCount(ctx, targetType, targetID)
+		return err
+	}
+	return r.likeCounter.DecrementReactionCount(ctx, targetType, targetID, reaction)
 }
 
 // insertLegacyLike maintains backward compatibility with the likes table
@@ -209,32 +285,123 @@ func (r *LikeRepository) insertLikeByUser(ctx context.Context, targetType string
 	`, userID, targetType, targetID, createdAt).WithContext(ctx).Exec()
 }
 
-// deleteLikeByUser removes from the likes_by_user table
-func (r *LikeRepository) deleteLikeByUser(ctx context.Context, targetType string, targetID, userID gocql.UUID) {
+// deleteLikeByUser removes from the likes_by_user table. The row is keyed by
+// the like's created_at, which the caller reads from like_state.
+func (r *LikeRepository) deleteLikeByUser(ctx context.Context, targetType string, targetID, userID gocql.UUID, createdAt time.Time) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
 	_ = r.session.Query(`
-		DELETE FROM likes_by_user WHERE user_id = ? AND target_type = ? AND target_id = ?
-	`, userID, targetType, targetID).WithContext(ctx).Exec()
+		DELETE FROM likes_by_user WHERE user_id = ? AND created_at = ? AND target_id = ?
+	`, userID, createdAt, targetID).WithContext(ctx).Exec()
+}
+
+// insertLikeByTarget adds to the likes_by_target table
+func (r *LikeRepository) insertLikeByTarget(ctx context.Context, targetType string, targetID, userID gocql.UUID, createdAt time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_ = r.session.Query(`
+		INSERT INTO likes_by_target (target_type, target_id, created_at, user_id)
+		VALUES (?, ?, ?, ?)
+	`, targetType, targetID, createdAt, userID).WithContext(ctx).Exec()
+}
+
+// deleteLikeByTarget removes from the likes_by_target table. Like
+// likes_by_user, the row is keyed by the like's created_at.
+func (r *LikeRepository) deleteLikeByTarget(ctx context.Context, targetType string, targetID, userID gocql.UUID, createdAt time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_ = r.session.Query(`
+		DELETE FROM likes_by_target WHERE target_type = ? AND target_id = ? AND created_at = ? AND user_id = ?
+	`, targetType, targetID, createdAt, userID).WithContext(ctx).Exec()
+}
+
+// GetLikedPostKeys returns the posts a user has liked, most recently liked
+// first, as keysets of (liked at, post ID) so they can back a cursor. Results
+// start after the given keyset.
+func (r *LikeRepository) GetLikedPostKeys(ctx context.Context, userIDStr string, limit int, after Keyset) ([]Keyset, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50 // Default 50 likes, max 200
+	}
+
+	var iter *gocql.Iter
+	if after.IsZero() {
+		iter = r.session.Query(`
+			SELECT target_type, target_id, created_at FROM likes_by_user
+			WHERE user_id = ?
+		`, userID).WithContext(ctx).PageSize(limit * 2).Iter()
+	} else {
+		iter = r.session.Query(`
+			SELECT target_type, target_id, created_at FROM likes_by_user
+			WHERE user_id = ? AND created_at <= ?
+		`, userID, after.CreatedAt).WithContext(ctx).PageSize(limit * 2).Iter()
+	}
+
+	var keys []Keyset
+	var targetType string
+	var targetID gocql.UUID
+	var likedAt time.Time
+	for iter.Scan(&targetType, &targetID, &likedAt) {
+		if len(keys) > 0 && keysetScanDone(len(keys), limit, keys[len(keys)-1].CreatedAt, likedAt) {
+			break
+		}
+		if targetType != TargetTypePost || !after.Admits(likedAt, targetID.String()) {
+			continue
+		}
+		keys = append(keys, Keyset{CreatedAt: likedAt, ID: targetID.String()})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating liked posts: %w", err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return KeysetBefore(keys[i].CreatedAt, keys[i].ID, keys[j].CreatedAt, keys[j].ID)
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	return keys, nil
 }
 
 // getLikeCountFallback counts likes from Cassandra when Redis is unavailable
 func (r *LikeRepository) getLikeCountFallback(ctx context.Context, targetType, targetID string) (int64, error) {
-	tid, err := gocql.ParseUUID(targetID)
+	counts, err := r.getReactionCountsFallback(ctx, targetType, targetID)
 	if err != nil {
 		return 0, err
 	}
+	return counts[ReactionLove], nil
+}
 
-	var count int
-	err = r.session.Query(`
-		SELECT COUNT(*) FROM like_state
-		WHERE target_type = ? AND target_id = ?
-	`, targetType, tid).WithContext(ctx).Scan(&count)
+// getReactionCountsFallback counts reactions from Cassandra when Redis is
+// unavailable
+func (r *LikeRepository) getReactionCountsFallback(ctx context.Context, targetType, targetID string) (map[string]int64, error) {
+	tid, err := gocql.ParseUUID(targetID)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return int64(count), nil
+
+	iter := r.session.Query(`
+		SELECT reaction FROM like_state
+		WHERE target_type = ? AND target_id = ?
+	`, targetType, tid).WithContext(ctx).Iter()
+
+	counts := make(map[string]int64)
+	var reaction string
+	for iter.Scan(&reaction) {
+		counts[reactionFromState(reaction, true)]++
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return counts, nil
 }
 
 // ============== READ OPERATIONS ==============
@@ -254,168 +421,273 @@ func (r *LikeRepository) GetLikeCount(ctx context.Context, targetType, targetID
 	return r.getLikeCountFallback(ctx, targetType, targetID)
 }
 
+// getReactionCounts returns the non-zero reaction counts of a target, ❤️
+// included. Uses Redis if available, falls back to Cassandra
+func (r *LikeRepository) getReactionCounts(ctx context.Context, targetType, targetID string) map[string]int64 {
+	if r.likeCounter != nil {
+		counts, err := r.getReactionCountsBatch(ctx, targetType, []string{targetID})
+		if err == nil {
+			return counts[targetID]
+		}
+		fmt.Printf("WARNING: Redis reaction counts failed, falling back to Cassandra: %v\n", err)
+	}
+
+	counts, _ := r.getReactionCountsFallback(ctx, targetType, targetID)
+	if counts == nil {
+		counts = make(map[string]int64)
+	}
+	return counts
+}
+
+// getReactionCountsBatch reads the reaction counts of several targets from
+// Redis, merging the like counters in as ❤️
+func (r *LikeRepository) getReactionCountsBatch(ctx context.Context, targetType string, targetIDs []string) (map[string]map[string]int64, error) {
+	likeCounts, err := r.likeCounter.GetLikeCountsBatch(ctx, targetType, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := r.likeCounter.GetReactionCountsBatch(ctx, targetType, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range targetIDs {
+		if counts[id] == nil {
+			counts[id] = make(map[string]int64)
+		}
+		if likeCounts[id] > 0 {
+			counts[id][ReactionLove] = likeCounts[id]
+		}
+	}
+	return counts, nil
+}
+
 // HasUserLiked checks if a user has liked a target
 func (r *LikeRepository) HasUserLiked(ctx context.Context, targetType, targetID, userID string) (bool, error) {
+	reaction, err := r.GetUserReaction(ctx, targetType, targetID, userID)
+	if err != nil {
+		return false, err
+	}
+	return reaction == ReactionLove, nil
+}
+
+// GetUserReaction returns a user's reaction to a target, empty if they have none
+func (r *LikeRepository) GetUserReaction(ctx context.Context, targetType, targetID, userID string) (string, error) {
 	tid, err := gocql.ParseUUID(targetID)
 	if err != nil {
-		return false, nil
+		return "", nil
 	}
 
 	uid, err := gocql.ParseUUID(userID)
 	if err != nil {
-		return false, nil
+		return "", nil
 	}
 
-	var createdAt time.Time
-	err = r.session.Query(`
-		SELECT created_at FROM like_state
-		WHERE target_type = ? AND target_id = ? AND user_id = ?
-	`, targetType, tid, uid).WithContext(ctx).Consistency(gocql.One).Scan(&createdAt)
-
-	if err == gocql.ErrNotFound {
-		return false, nil
-	}
+	stored, _, found, err := r.readReaction(ctx, targetType, tid, uid)
 	if err != nil {
-		return false, err
+		return "", err
 	}
-	return true, nil
+	return reactionFromState(stored, found), nil
 }
 
-// PostLikeInfo contains like information for a post
+// PostLikeInfo contains like and reaction information for a post
 type PostLikeInfo struct {
-	LikeCount int64
-	IsLiked   bool
+	LikeCount      int64
+	IsLiked        bool
+	ReactionCounts map[string]int64
+	MyReaction     string
 }
 
 // GetLikesForPosts returns like counts and user like status for multiple posts
 func (r *LikeRepository) GetLikesForPosts(ctx context.Context, postIDs []string, userID string) (map[string]PostLikeInfo, error) {
 	result := make(map[string]PostLikeInfo)
 
-	if len(postIDs) == 0 {
-		return result, nil
-	}
-
-	// Batch get counts from Redis
-	var counts map[string]int64
-	if r.likeCounter != nil {
-		var err error
-		counts, err = r.likeCounter.GetLikeCountsBatch(ctx, TargetTypePost, postIDs)
-		if err != nil {
-			fmt.Printf("WARNING: Redis batch get failed: %v\n", err)
-			counts = make(map[string]int64)
-		}
-	} else {
-		counts = make(map[string]int64)
-	}
-
-	// Concurrent checking of user likes to avoid N+1 latency
-	type likeResult struct {
-		targetID string
-		liked    bool
-	}
-	likeChan := make(chan likeResult, len(postIDs))
-
+	counts, mine := r.getReactionsForTargets(ctx, TargetTypePost, postIDs, userID)
 	for _, postID := range postIDs {
-		go func(pid string) {
-			liked := false
-			if userID != "" {
-				liked, _ = r.HasUserLiked(ctx, TargetTypePost, pid, userID)
-			}
-			likeChan <- likeResult{pid, liked}
-		}(postID)
-	}
-
-	userLikes := make(map[string]bool)
-	for i := 0; i < len(postIDs); i++ {
-		res := <-likeChan
-		userLikes[res.targetID] = res.liked
-	}
-
-	// Build result
-	for _, postID := range postIDs {
-		info := PostLikeInfo{
-			LikeCount: counts[postID],
-			IsLiked:   userLikes[postID],
+		result[postID] = PostLikeInfo{
+			LikeCount:      counts[postID][ReactionLove],
+			IsLiked:        mine[postID] == ReactionLove,
+			ReactionCounts: counts[postID],
+			MyReaction:     mine[postID],
 		}
-
-		// If Redis count is 0, try Cassandra fallback
-		if info.LikeCount == 0 && r.likeCounter == nil {
-			count, _ := r.getLikeCountFallback(ctx, TargetTypePost, postID)
-			info.LikeCount = count
-		}
-
-		result[postID] = info
 	}
 
 	return result, nil
 }
 
-// CommentLikeInfo contains like information for a comment
+// CommentLikeInfo contains like and reaction information for a comment
 type CommentLikeInfo struct {
-	LikeCount int64
-	IsLiked   bool
+	LikeCount      int64
+	IsLiked        bool
+	ReactionCounts map[string]int64
+	MyReaction     string
 }
 
 // GetLikesForComments returns like counts and user like status for multiple comments
 func (r *LikeRepository) GetLikesForComments(ctx context.Context, commentIDs []string, userID string) (map[string]CommentLikeInfo, error) {
 	result := make(map[string]CommentLikeInfo)
 
-	if len(commentIDs) == 0 {
-		return result, nil
+	counts, mine := r.getReactionsForTargets(ctx, TargetTypeComment, commentIDs, userID)
+	for _, commentID := range commentIDs {
+		result[commentID] = CommentLikeInfo{
+			LikeCount:      counts[commentID][ReactionLove],
+			IsLiked:        mine[commentID] == ReactionLove,
+			ReactionCounts: counts[commentID],
+			MyReaction:     mine[commentID],
+		}
+	}
+
+	return result, nil
+}
+
+// getReactionsForTargets returns the reaction counts of several targets and
+// userID's reaction to each
+func (r *LikeRepository) getReactionsForTargets(ctx context.Context, targetType string, targetIDs []string, userID string) (map[string]map[string]int64, map[string]string) {
+	mine := make(map[string]string)
+	if len(targetIDs) == 0 {
+		return make(map[string]map[string]int64), mine
 	}
 
 	// Batch get counts from Redis
-	var counts map[string]int64
+	var counts map[string]map[string]int64
 	if r.likeCounter != nil {
 		var err error
-		counts, err = r.likeCounter.GetLikeCountsBatch(ctx, TargetTypeComment, commentIDs)
+		counts, err = r.getReactionCountsBatch(ctx, targetType, targetIDs)
 		if err != nil {
 			fmt.Printf("WARNING: Redis batch get failed: %v\n", err)
-			counts = make(map[string]int64)
+			counts = make(map[string]map[string]int64)
 		}
 	} else {
-		counts = make(map[string]int64)
+		// Count from Cassandra when Redis is not available
+		counts = make(map[string]map[string]int64)
+		for _, id := range targetIDs {
+			counts[id], _ = r.getReactionCountsFallback(ctx, targetType, id)
+		}
 	}
 
-	// Concurrent checking of user likes
-	type likeResult struct {
+	// Concurrent checking of user reactions to avoid N+1 latency
+	type reactionResult struct {
 		targetID string
-		liked    bool
+		reaction string
 	}
-	likeChan := make(chan likeResult, len(commentIDs))
+	reactionChan := make(chan reactionResult, len(targetIDs))
 
-	for _, commentID := range commentIDs {
-		go func(cid string) {
-			liked := false
+	for _, targetID := range targetIDs {
+		go func(tid string) {
+			reaction := ""
 			if userID != "" {
-				liked, _ = r.HasUserLiked(ctx, TargetTypeComment, cid, userID)
+				reaction, _ = r.GetUserReaction(ctx, targetType, tid, userID)
 			}
-			likeChan <- likeResult{cid, liked}
-		}(commentID)
+			reactionChan <- reactionResult{tid, reaction}
+		}(targetID)
 	}
 
-	userLikes := make(map[string]bool)
-	for i := 0; i < len(commentIDs); i++ {
-		res := <-likeChan
-		userLikes[res.targetID] = res.liked
+	for i := 0; i < len(targetIDs); i++ {
+		res := <-reactionChan
+		if res.reaction != "" {
+			mine[res.targetID] = res.reaction
+		}
 	}
 
-	// Build result
-	for _, commentID := range commentIDs {
-		info := CommentLikeInfo{
-			LikeCount: counts[commentID],
-			IsLiked:   userLikes[commentID],
+	return counts, mine
+}
+
+// GetLikerKeys returns the users who liked a target, most recent like first,
+// as keysets of (liked at, user ID) so they can back a cursor. Results start
+// after the given keyset.
+func (r *LikeRepository) GetLikerKeys(ctx context.Context, targetType, targetIDStr string, limit int, after Keyset) ([]Keyset, error) {
+	targetID, err := gocql.ParseUUID(targetIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50 // Default 50 likes, max 200
+	}
+
+	var iter *gocql.Iter
+	if after.IsZero() {
+		iter = r.session.Query(`
+			SELECT user_id, created_at FROM likes_by_target
+			WHERE target_type = ? AND target_id = ?
+		`, targetType, targetID).WithContext(ctx).PageSize(limit * 2).Iter()
+	} else {
+		iter = r.session.Query(`
+			SELECT user_id, created_at FROM likes_by_target
+			WHERE target_type = ? AND target_id = ? AND created_at <= ?
+		`, targetType, targetID, after.CreatedAt).WithContext(ctx).PageSize(limit * 2).Iter()
+	}
+
+	var keys []Keyset
+	var userID gocql.UUID
+	var likedAt time.Time
+	for iter.Scan(&userID, &likedAt) {
+		if len(keys) > 0 && keysetScanDone(len(keys), limit, keys[len(keys)-1].CreatedAt, likedAt) {
+			break
+		}
+		if !after.Admits(likedAt, userID.String()) {
+			continue
 		}
+		keys = append(keys, Keyset{CreatedAt: likedAt, ID: userID.String()})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating likers: %w", err)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return KeysetBefore(keys[i].CreatedAt, keys[i].ID, keys[j].CreatedAt, keys[j].ID)
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	return keys, nil
+}
+
+// GetLikerKeysAmong returns which of userIDs liked a target, most recent like
+// first, as keysets of (liked at, user ID). Other reactions are not likes.
+func (r *LikeRepository) GetLikerKeysAmong(ctx context.Context, targetType, targetIDStr string, userIDs []string) ([]Keyset, error) {
+	targetID, err := gocql.ParseUUID(targetIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_id: %w", err)
+	}
+
+	const chunkSize = 100
 
-		if info.LikeCount == 0 && r.likeCounter == nil {
-			count, _ := r.getLikeCountFallback(ctx, TargetTypeComment, commentID)
-			info.LikeCount = count
+	var keys []Keyset
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := min(start+chunkSize, len(userIDs))
+
+		uuids := make([]gocql.UUID, 0, end-start)
+		for _, id := range userIDs[start:end] {
+			if uid, err := gocql.ParseUUID(id); err == nil {
+				uuids = append(uuids, uid)
+			}
 		}
 
-		result[commentID] = info
+		iter := r.session.Query(`
+			SELECT user_id, reaction, created_at FROM like_state
+			WHERE target_type = ? AND target_id = ? AND user_id IN ?
+		`, targetType, targetID, uuids).WithContext(ctx).Iter()
+
+		var userID gocql.UUID
+		var reaction string
+		var likedAt time.Time
+		for iter.Scan(&userID, &reaction, &likedAt) {
+			if reactionFromState(reaction, true) == ReactionLove {
+				keys = append(keys, Keyset{CreatedAt: likedAt, ID: userID.String()})
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return nil, fmt.Errorf("error iterating likers: %w", err)
+		}
 	}
 
-	return result, nil
+	sort.Slice(keys, func(i, j int) bool {
+		return KeysetBefore(keys[i].CreatedAt, keys[i].ID, keys[j].CreatedAt, keys[j].ID)
+	})
+
+	return keys, nil
 }
 
 // ============== LEGACY API (for backward compatibility) ==============
@@ -442,17 +714,22 @@ func (r *LikeRepository) RemoveLike(ctx context.Context, req *LikeRequest) error
 	return err
 }
 
-// SyncCounterFromCassandra rebuilds a Redis counter from Cassandra data
+// SyncCounterFromCassandra rebuilds the Redis like and reaction counters of a
+// target from Cassandra data
 // Useful for recovery or initial sync
 func (r *LikeRepository) SyncCounterFromCassandra(ctx context.Context, targetType, targetID string) error {
 	if r.likeCounter == nil {
 		return fmt.Errorf("Redis counter not available")
 	}
 
-	count, err := r.getLikeCountFallback(ctx, targetType, targetID)
+	counts, err := r.getReactionCountsFallback(ctx, targetType, targetID)
 	if err != nil {
 		return err
 	}
 
-	return r.likeCounter.SetLikeCount(ctx, targetType, targetID, count)
+	if err := r.likeCounter.SetLikeCount(ctx, targetType, targetID, counts[ReactionLove]); err != nil {
+		return err
+	}
+	delete(counts, ReactionLove)
+	return r.likeCounter.SetReactionCounts(ctx, targetType, targetID, counts)
 }
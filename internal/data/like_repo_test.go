@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -57,4 +58,128 @@ func TestLikeRepository_Integration(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), count)
 	})
+	t.Run("Liked Posts Follow Like State", func(t *testing.T) {
+		likerID := uuid.New().String()
+		firstPost := uuid.New().String()
+		secondPost := uuid.New().String()
+
+		_, err := repo.ToggleLike(ctx, TargetTypePost, firstPost, likerID, true)
+		require.NoError(t, err)
+		_, err = repo.ToggleLike(ctx, TargetTypePost, secondPost, likerID, true)
+		require.NoError(t, err)
+
+		// likes_by_user is written asynchronously
+		likedIDs := func() []string {
+			keys, err := repo.GetLikedPostKeys(ctx, likerID, 10, Keyset{})
+			require.NoError(t, err)
+			var ids []string
+			for _, k := range keys {
+				ids = append(ids, k.ID)
+			}
+			return ids
+		}
+		assert.Eventually(t, func() bool {
+			ids := likedIDs()
+			return len(ids) == 2 && ids[0] == secondPost
+		}, 5*time.Second, 50*time.Millisecond)
+
+		_, err = repo.ToggleLike(ctx, TargetTypePost, secondPost, likerID, false)
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			ids := likedIDs()
+			return len(ids) == 1 && ids[0] == firstPost
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("Likers Follow Like State", func(t *testing.T) {
+		target := uuid.New().String()
+		first := uuid.New().String()
+		second := uuid.New().String()
+		reactor := uuid.New().String()
+
+		_, err := repo.ToggleLike(ctx, TargetTypePost, target, first, true)
+		require.NoError(t, err)
+		_, err = repo.ToggleLike(ctx, TargetTypePost, target, second, true)
+		require.NoError(t, err)
+		_, err = repo.SetReaction(ctx, TargetTypePost, target, reactor, ReactionWow)
+		require.NoError(t, err)
+
+		// likes_by_target is written asynchronously
+		assert.Eventually(t, func() bool {
+			keys, err := repo.GetLikerKeys(ctx, TargetTypePost, target, 10, Keyset{})
+			require.NoError(t, err)
+			return len(keys) == 2 && keys[0].ID == second && keys[1].ID == first
+		}, 5*time.Second, 50*time.Millisecond)
+
+		keys, err := repo.GetLikerKeys(ctx, TargetTypePost, target, 1, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		keys, err = repo.GetLikerKeys(ctx, TargetTypePost, target, 10, keys[0])
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, first, keys[0].ID)
+
+		// Only likes count, not other reactions
+		keys, err = repo.GetLikerKeysAmong(ctx, TargetTypePost, target, []string{first, reactor})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, first, keys[0].ID)
+	})
+
+	t.Run("Reactions Replace Each Other", func(t *testing.T) {
+		reactorID := uuid.New().String()
+		otherID := uuid.New().String()
+		target := uuid.New().String()
+
+		_, err := repo.ToggleLike(ctx, TargetTypeComment, target, otherID, true)
+		require.NoError(t, err)
+
+		result, err := repo.SetReaction(ctx, TargetTypeComment, target, reactorID, ReactionFire)
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Empty(t, result.Previous)
+		assert.Equal(t, map[string]int64{ReactionLove: 1, ReactionFire: 1}, result.ReactionCounts)
+
+		// Liking replaces the reaction
+		toggled, err := repo.ToggleLike(ctx, TargetTypeComment, target, reactorID, true)
+		require.NoError(t, err)
+		assert.True(t, toggled.IsLiked)
+		assert.Equal(t, int64(2), toggled.LikeCount)
+
+		// Unliking leaves another reaction alone
+		_, err = repo.SetReaction(ctx, TargetTypeComment, target, reactorID, ReactionSad)
+		require.NoError(t, err)
+		toggled, err = repo.ToggleLike(ctx, TargetTypeComment, target, reactorID, false)
+		require.NoError(t, err)
+		assert.False(t, toggled.Changed)
+
+		info, err := repo.GetLikesForComments(ctx, []string{target}, reactorID)
+		require.NoError(t, err)
+		assert.Equal(t, ReactionSad, info[target].MyReaction)
+		assert.False(t, info[target].IsLiked)
+		assert.Equal(t, map[string]int64{ReactionLove: 1, ReactionSad: 1}, info[target].ReactionCounts)
+
+		result, err = repo.SetReaction(ctx, TargetTypeComment, target, reactorID, "")
+		require.NoError(t, err)
+		assert.Equal(t, ReactionSad, result.Previous)
+		assert.Equal(t, map[string]int64{ReactionLove: 1}, result.ReactionCounts)
+	})
+}
+
+func TestNormalizeReaction(t *testing.T) {
+	for _, reaction := range ValidReactions {
+		got, err := NormalizeReaction(reaction)
+		require.NoError(t, err)
+		assert.Equal(t, reaction, got)
+	}
+
+	// A heart without the variation selector is a like
+	got, err := NormalizeReaction("❤")
+	require.NoError(t, err)
+	assert.Equal(t, ReactionLove, got)
+
+	for _, bad := range []string{"", "👍", "like", "❤️❤️"} {
+		_, err := NormalizeReaction(bad)
+		assert.Error(t, err, "expected error for %q", bad)
+	}
 }
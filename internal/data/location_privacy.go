@@ -0,0 +1,105 @@
+package data
+
+import (
+	"github.com/mmcloughlin/geohash"
+)
+
+// How precisely a post's location is published
+const (
+	LocationPrecisionExact         = "exact"
+	LocationPrecisionNeighbourhood = "neighbourhood"
+	LocationPrecisionCity          = "city"
+	LocationPrecisionHidden        = "hidden"
+)
+
+// locationPrecisionRank orders precisions from finest to coarsest
+var locationPrecisionRank = map[string]int{
+	LocationPrecisionExact:         0,
+	LocationPrecisionNeighbourhood: 1,
+	LocationPrecisionCity:          2,
+	LocationPrecisionHidden:        3,
+}
+
+// locationGeohashLength is the geohash cell a precision is snapped to:
+// ~150 m for exact, ~1 km for neighbourhood and ~20-40 km for city
+var locationGeohashLength = map[string]uint{
+	LocationPrecisionExact:         7,
+	LocationPrecisionNeighbourhood: 6,
+	LocationPrecisionCity:          4,
+}
+
+// ValidLocationPrecision reports whether p is an accepted location precision
+func ValidLocationPrecision(p string) bool {
+	_, ok := locationPrecisionRank[p]
+	return ok
+}
+
+// postLocationPrecision returns the stored precision of a post. Posts written
+// before location precision existed have no value and are exact.
+func postLocationPrecision(p string) string {
+	if p == "" {
+		return LocationPrecisionExact
+	}
+	return p
+}
+
+// CoarserLocationPrecision returns whichever of a and b reveals less
+func CoarserLocationPrecision(a, b string) string {
+	a, b = postLocationPrecision(a), postLocationPrecision(b)
+	if locationPrecisionRank[b] > locationPrecisionRank[a] {
+		return b
+	}
+	return a
+}
+
+// CoarsenLocation returns the coordinates and geohash to publish for a
+// location at the given precision. Neighbourhood and city snap the point to
+// the centre of its geohash cell, so the stored values cannot be traced back
+// to the original point; hidden drops the location entirely.
+func CoarsenLocation(lat, lng float64, precision string) (float64, float64, string) {
+	precision = postLocationPrecision(precision)
+	if precision == LocationPrecisionHidden {
+		return 0, 0, ""
+	}
+
+	length := locationGeohashLength[precision]
+	cell := EncodeGeohash(lat, lng, length)
+	if precision == LocationPrecisionExact {
+		return lat, lng, cell
+	}
+
+	centerLat, centerLng := geohash.DecodeCenter(cell)
+	return centerLat, centerLng, cell
+}
+
+// HasLocation reports whether the post publishes a location at all
+func (p *Post) HasLocation() bool {
+	return postLocationPrecision(p.LocationPrecision) != LocationPrecisionHidden
+}
+
+// SetLocation fills the post's place name and address from a geocoded
+// location, trimmed to what the post's precision allows: city posts only name
+// the city, and hidden posts get nothing.
+func (p *Post) SetLocation(loc *LocationName) {
+	switch postLocationPrecision(p.LocationPrecision) {
+	case LocationPrecisionHidden:
+		return
+	case LocationPrecisionCity:
+		addr := LocationAddress{
+			City:        loc.Address.City,
+			State:       loc.Address.State,
+			Region:      loc.Address.Region,
+			Country:     loc.Address.Country,
+			CountryCode: loc.Address.CountryCode,
+		}
+		p.LocationName = addr.City
+		if p.LocationName == "" {
+			p.LocationName = addr.State
+		}
+		p.Address = &addr
+	default:
+		addr := loc.Address
+		p.LocationName = loc.Name
+		p.Address = &addr
+	}
+}
@@ -0,0 +1,82 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoarsenLocation(t *testing.T) {
+	lat, lng := -6.36531, 106.82694
+
+	t.Run("Exact Keeps The Point", func(t *testing.T) {
+		gotLat, gotLng, gh := CoarsenLocation(lat, lng, LocationPrecisionExact)
+		assert.Equal(t, lat, gotLat)
+		assert.Equal(t, lng, gotLng)
+		assert.Len(t, gh, 7)
+	})
+
+	t.Run("Unset Is Exact", func(t *testing.T) {
+		_, _, gh := CoarsenLocation(lat, lng, "")
+		assert.Len(t, gh, 7)
+	})
+
+	t.Run("Coarse Precisions Snap To Cell Centre", func(t *testing.T) {
+		for precision, length := range map[string]int{LocationPrecisionNeighbourhood: 6, LocationPrecisionCity: 4} {
+			gotLat, gotLng, gh := CoarsenLocation(lat, lng, precision)
+			assert.Len(t, gh, length, precision)
+			assert.Equal(t, EncodeGeohash(lat, lng, uint(length)), gh, precision)
+			assert.Equal(t, gh, EncodeGeohash(gotLat, gotLng, uint(length)), precision)
+
+			// Any point in the same cell is published identically
+			otherLat, otherLng, _ := CoarsenLocation(gotLat+0.0001, gotLng-0.0001, precision)
+			assert.Equal(t, gotLat, otherLat, precision)
+			assert.Equal(t, gotLng, otherLng, precision)
+		}
+	})
+
+	t.Run("Hidden Drops The Location", func(t *testing.T) {
+		gotLat, gotLng, gh := CoarsenLocation(lat, lng, LocationPrecisionHidden)
+		assert.Zero(t, gotLat)
+		assert.Zero(t, gotLng)
+		assert.Empty(t, gh)
+	})
+}
+
+func TestCoarserLocationPrecision(t *testing.T) {
+	assert.Equal(t, LocationPrecisionCity, CoarserLocationPrecision(LocationPrecisionNeighbourhood, LocationPrecisionCity))
+	assert.Equal(t, LocationPrecisionCity, CoarserLocationPrecision(LocationPrecisionCity, LocationPrecisionNeighbourhood))
+	assert.Equal(t, LocationPrecisionHidden, CoarserLocationPrecision(LocationPrecisionHidden, LocationPrecisionCity))
+	assert.Equal(t, LocationPrecisionNeighbourhood, CoarserLocationPrecision("", LocationPrecisionNeighbourhood))
+}
+
+func TestPrivacyZoneContains(t *testing.T) {
+	zone := PrivacyZone{Latitude: -6.3653, Longitude: 106.8269, RadiusM: 500}
+	assert.True(t, zone.Contains(-6.3660, 106.8275))
+	assert.False(t, zone.Contains(-6.3753, 106.8269)) // ~1.1 km south
+}
+
+func TestPostSetLocation(t *testing.T) {
+	loc := &LocationName{
+		Name: "Kukusan",
+		Address: LocationAddress{
+			Village: "Kukusan", CityDistrict: "Beji", City: "Depok",
+			State: "West Java", Postcode: "16425", Country: "Indonesia", CountryCode: "id",
+		},
+	}
+
+	exact := Post{LocationPrecision: LocationPrecisionExact}
+	exact.SetLocation(loc)
+	assert.Equal(t, "Kukusan", exact.LocationName)
+	assert.Equal(t, "Beji", exact.Address.CityDistrict)
+
+	city := Post{LocationPrecision: LocationPrecisionCity}
+	city.SetLocation(loc)
+	assert.Equal(t, "Depok", city.LocationName)
+	assert.Equal(t, LocationAddress{City: "Depok", State: "West Java", Country: "Indonesia", CountryCode: "id"}, *city.Address)
+
+	hidden := Post{LocationPrecision: LocationPrecisionHidden}
+	hidden.SetLocation(loc)
+	assert.Empty(t, hidden.LocationName)
+	assert.Nil(t, hidden.Address)
+}
@@ -0,0 +1,205 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// MentionTarget is a post or comment whose mentions are stored
+type MentionTarget struct {
+	Type     string // TargetTypePost or TargetTypeComment
+	ID       string
+	PostID   string // The post itself, or the post commented on
+	AuthorID string
+	TTL      int // Seconds left of an ephemeral post; 0 keeps the rows
+}
+
+// MentionRef is an entry of a user's mentions tab
+type MentionRef struct {
+	TargetType string
+	TargetID   string
+	PostID     string
+	AuthorID   string
+	CreatedAt  time.Time
+}
+
+// Keyset returns the position of the entry in the mentions tab
+func (m MentionRef) Keyset() Keyset {
+	return Keyset{CreatedAt: m.CreatedAt, ID: m.TargetID}
+}
+
+// MentionRepository stores the mentions in posts and comments, and the index
+// of where each user has been mentioned
+type MentionRepository struct {
+	session *gocql.Session
+}
+
+// NewMentionRepository creates a new MentionRepository
+func NewMentionRepository(session *gocql.Session) *MentionRepository {
+	return &MentionRepository{session: session}
+}
+
+// SaveMentions stores the mentions of target and adds the target to the
+// mentions tab of each of recipients. With replace, mentions left over from
+// an earlier version of the text are dropped. Every call adds new tab
+// entries, so recipients should only hold users newly mentioned.
+func (r *MentionRepository) SaveMentions(ctx context.Context, target MentionTarget, mentions []Mention, recipients []string, replace bool) error {
+	targetID, err := gocql.ParseUUID(target.ID)
+	if err != nil {
+		return fmt.Errorf("invalid target_id: %w", err)
+	}
+	postID, err := gocql.ParseUUID(target.PostID)
+	if err != nil {
+		return fmt.Errorf("invalid post_id: %w", err)
+	}
+	authorID, err := gocql.ParseUUID(target.AuthorID)
+	if err != nil {
+		return fmt.Errorf("invalid author_id: %w", err)
+	}
+
+	now := time.Now()
+	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+
+	if replace {
+		batch.Query(`
+			DELETE FROM mentions WHERE target_type = ? AND target_id = ? AND position >= ?
+		`, target.Type, targetID, len(mentions))
+	}
+	for i, m := range mentions {
+		userID, err := gocql.ParseUUID(m.UserID)
+		if err != nil {
+			return fmt.Errorf("invalid user_id: %w", err)
+		}
+		batch.Query(`
+			INSERT INTO mentions (target_type, target_id, position, user_id, username, char_offset, char_length)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			USING TTL ?
+		`, target.Type, targetID, i, userID, m.Username, m.Offset, m.Length, target.TTL)
+	}
+	for _, id := range recipients {
+		userID, err := gocql.ParseUUID(id)
+		if err != nil {
+			return fmt.Errorf("invalid user_id: %w", err)
+		}
+		batch.Query(`
+			INSERT INTO mentions_by_user (user_id, created_at, target_id, target_type, post_id, author_id)
+			VALUES (?, ?, ?, ?, ?, ?)
+			USING TTL ?
+		`, userID, now, targetID, target.Type, postID, authorID, target.TTL)
+	}
+
+	if len(batch.Entries) == 0 {
+		return nil
+	}
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to save mentions: %w", err)
+	}
+	return nil
+}
+
+// GetMentions returns the mentions of posts or comments (targetType), in the
+// order they appear in each text
+func (r *MentionRepository) GetMentions(ctx context.Context, targetType string, targetIDs []string) (map[string][]Mention, error) {
+	return getMentions(ctx, r.session, targetType, targetIDs)
+}
+
+// GetMentionRefs returns where userID has been mentioned, most recent first,
+// starting after the given keyset
+func (r *MentionRepository) GetMentionRefs(ctx context.Context, userIDStr string, limit int, after Keyset) ([]MentionRef, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var iter *gocql.Iter
+	if after.IsZero() {
+		iter = r.session.Query(`
+			SELECT created_at, target_id, target_type, post_id, author_id FROM mentions_by_user
+			WHERE user_id = ?
+		`, userID).WithContext(ctx).PageSize(limit * 2).Iter()
+	} else {
+		iter = r.session.Query(`
+			SELECT created_at, target_id, target_type, post_id, author_id FROM mentions_by_user
+			WHERE user_id = ? AND created_at <= ?
+		`, userID, after.CreatedAt).WithContext(ctx).PageSize(limit * 2).Iter()
+	}
+
+	var refs []MentionRef
+	var ref MentionRef
+	var targetID, postID, authorID gocql.UUID
+	for iter.Scan(&ref.CreatedAt, &targetID, &ref.TargetType, &postID, &authorID) {
+		if len(refs) > 0 && keysetScanDone(len(refs), limit, refs[len(refs)-1].CreatedAt, ref.CreatedAt) {
+			break
+		}
+		if !after.Admits(ref.CreatedAt, targetID.String()) {
+			continue
+		}
+		ref.TargetID = targetID.String()
+		ref.PostID = postID.String()
+		ref.AuthorID = authorID.String()
+		refs = append(refs, ref)
+		ref = MentionRef{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating mentions: %w", err)
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return KeysetBefore(refs[i].CreatedAt, refs[i].TargetID, refs[j].CreatedAt, refs[j].TargetID)
+	})
+	if len(refs) > limit {
+		refs = refs[:limit]
+	}
+
+	return refs, nil
+}
+
+// GetMentionsForPosts returns the mentions in posts, keyed by post ID
+func (r *PostRepository) GetMentionsForPosts(ctx context.Context, postIDs []string) (map[string][]Mention, error) {
+	return getMentions(ctx, r.session, TargetTypePost, postIDs)
+}
+
+// GetMentionsForComments returns the mentions in comments, keyed by comment ID
+func (r *CommentRepository) GetMentionsForComments(ctx context.Context, commentIDs []string) (map[string][]Mention, error) {
+	return getMentions(ctx, r.session, TargetTypeComment, commentIDs)
+}
+
+func getMentions(ctx context.Context, session *gocql.Session, targetType string, targetIDs []string) (map[string][]Mention, error) {
+	result := make(map[string][]Mention)
+
+	ids := make([]gocql.UUID, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		if tid, err := gocql.ParseUUID(id); err == nil {
+			ids = append(ids, tid)
+		}
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	iter := session.Query(`
+		SELECT target_id, user_id, username, char_offset, char_length FROM mentions
+		WHERE target_type = ? AND target_id IN ?
+	`, targetType, ids).WithContext(ctx).Iter()
+
+	var targetID, userID gocql.UUID
+	var m Mention
+	for iter.Scan(&targetID, &userID, &m.Username, &m.Offset, &m.Length) {
+		m.UserID = userID.String()
+		result[targetID.String()] = append(result[targetID.String()], m)
+		m = Mention{}
+	}
+	if err := iter.Close(); err != nil {
+		return result, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	return result, nil
+}
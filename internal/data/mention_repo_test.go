@@ -0,0 +1,78 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMentionRepository_Integration(t *testing.T) {
+	repo := NewMentionRepository(testSession)
+	ctx := context.Background()
+
+	authorID := uuid.New().String()
+	aliceID := uuid.New().String()
+	bobID := uuid.New().String()
+	postID := uuid.New().String()
+	commentID := uuid.New().String()
+
+	alice := Mention{UserID: aliceID, Username: "alice", Offset: 0, Length: 6}
+	bob := Mention{UserID: bobID, Username: "bob", Offset: 7, Length: 4}
+
+	postTarget := MentionTarget{Type: TargetTypePost, ID: postID, PostID: postID, AuthorID: authorID}
+	commentTarget := MentionTarget{Type: TargetTypeComment, ID: commentID, PostID: postID, AuthorID: authorID}
+
+	t.Run("Save And Get", func(t *testing.T) {
+		require.NoError(t, repo.SaveMentions(ctx, postTarget, []Mention{alice, bob}, []string{aliceID, bobID}, false))
+
+		mentions, err := repo.GetMentions(ctx, TargetTypePost, []string{postID})
+		require.NoError(t, err)
+		assert.Equal(t, []Mention{alice, bob}, mentions[postID])
+
+		// Posts and comments are kept apart
+		mentions, err = repo.GetMentions(ctx, TargetTypeComment, []string{postID})
+		require.NoError(t, err)
+		assert.Empty(t, mentions)
+	})
+
+	t.Run("Replace Drops Left Over Mentions", func(t *testing.T) {
+		require.NoError(t, repo.SaveMentions(ctx, postTarget, []Mention{bob}, nil, true))
+
+		mentions, err := repo.GetMentions(ctx, TargetTypePost, []string{postID})
+		require.NoError(t, err)
+		require.Len(t, mentions[postID], 1)
+		assert.Equal(t, bobID, mentions[postID][0].UserID)
+	})
+
+	t.Run("Mentions Tab", func(t *testing.T) {
+		time.Sleep(2 * time.Millisecond)
+		require.NoError(t, repo.SaveMentions(ctx, commentTarget, []Mention{alice}, []string{aliceID}, false))
+
+		refs, err := repo.GetMentionRefs(ctx, aliceID, 10, Keyset{})
+		require.NoError(t, err)
+		require.Len(t, refs, 2)
+		assert.Equal(t, commentID, refs[0].TargetID)
+		assert.Equal(t, TargetTypeComment, refs[0].TargetType)
+		assert.Equal(t, postID, refs[0].PostID)
+		assert.Equal(t, authorID, refs[0].AuthorID)
+		assert.Equal(t, postID, refs[1].TargetID)
+
+		// Paging continues after the cursor
+		refs, err = repo.GetMentionRefs(ctx, aliceID, 10, refs[0].Keyset())
+		require.NoError(t, err)
+		require.Len(t, refs, 1)
+		assert.Equal(t, postID, refs[0].TargetID)
+	})
+
+	t.Run("Invalid IDs", func(t *testing.T) {
+		err := repo.SaveMentions(ctx, MentionTarget{Type: TargetTypePost, ID: "bad"}, []Mention{alice}, nil, false)
+		assert.ErrorContains(t, err, "invalid target_id")
+
+		_, err = repo.GetMentionRefs(ctx, "bad", 10, Keyset{})
+		assert.ErrorContains(t, err, "invalid user_id")
+	})
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mention limits
+const (
+	MaxMentions             = 10 // Distinct users resolved per post or comment
+	MinMentionUsernameChars = 3
+	MaxMentionUsernameChars = 50
+)
+
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_]+)`)
+
+// ParseMentions returns the @usernames in content, in order, with their
+// character offsets. Users are not resolved, so UserID is empty. An '@'
+// right after a letter, digit, '_' or another '@', as in an email address,
+// does not start a mention.
+func ParseMentions(content string) []Mention {
+	var mentions []Mention
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := loc[0], loc[1]
+		if start > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(content[:start])
+			if prev == '@' || prev == '_' || unicode.IsLetter(prev) || unicode.IsDigit(prev) {
+				continue
+			}
+		}
+
+		username := content[loc[2]:loc[3]]
+		if len(username) < MinMentionUsernameChars || len(username) > MaxMentionUsernameChars {
+			continue
+		}
+
+		mentions = append(mentions, Mention{
+			Username: username,
+			Offset:   utf8.RuneCountInString(content[:start]),
+			Length:   utf8.RuneCountInString(content[start:end]),
+		})
+	}
+	return mentions
+}
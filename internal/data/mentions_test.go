@@ -0,0 +1,35 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	t.Run("Offsets Count Characters", func(t *testing.T) {
+		mentions := ParseMentions("Kopi ☕ with @alice and @Bob_99!")
+		assert.Equal(t, []Mention{
+			{Username: "alice", Offset: 12, Length: 6},
+			{Username: "Bob_99", Offset: 23, Length: 7},
+		}, mentions)
+	})
+
+	t.Run("Repeated Usernames Are Kept", func(t *testing.T) {
+		mentions := ParseMentions("@alice @alice")
+		assert.Len(t, mentions, 2)
+		assert.Equal(t, 7, mentions[1].Offset)
+	})
+
+	t.Run("Not Mentions", func(t *testing.T) {
+		assert.Empty(t, ParseMentions("mail me at alice@example.com"))
+		assert.Empty(t, ParseMentions("@@alice"))
+		assert.Empty(t, ParseMentions("@al is too short"))
+		assert.Empty(t, ParseMentions("just an @ sign"))
+	})
+
+	t.Run("Punctuation Ends A Mention", func(t *testing.T) {
+		mentions := ParseMentions("(@carol's café)")
+		assert.Equal(t, []Mention{{Username: "carol", Offset: 1, Length: 6}}, mentions)
+	})
+}
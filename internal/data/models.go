@@ -16,6 +16,7 @@ type User struct {
 	PhoneNumber       string     `json:"phone_number,omitempty"`
 	ProfilePictureURL string     `json:"profile_picture_url,omitempty"`
 	CoverImageURL     string     `json:"cover_image_url,omitempty"`
+	IsPrivate         bool       `json:"is_private"` // Follows need approval; non-followers only see the basic profile
 	PasswordHash      string     `json:"-"`
 	LastOnline        *time.Time `json:"last_online,omitempty"`
 	LastIPAddress     string     `json:"-"` // Don't expose in JSON
@@ -50,29 +51,133 @@ type Post struct {
 	Latitude          float64  `json:"-"` // Hidden - use geohash instead
 	Longitude         float64  `json:"-"` // Hidden - use geohash instead
 	Geohash           string   `json:"geohash,omitempty"`
+	Visibility        string   `json:"visibility"`         // Audience: public, followers, close_friends or only_me
+	LocationPrecision string   `json:"location_precision"` // exact, neighbourhood, city or hidden
 	// Location info (from cached geocoding)
 	LocationName string           `json:"location_name,omitempty"`
 	Address      *LocationAddress `json:"address,omitempty"`
-	// Like info
-	LikeCount    int64     `json:"like_count"`
-	CommentCount int64     `json:"comment_count"`
-	IsLiked      bool      `json:"is_liked"` // Whether current user has liked this post
-	IPAddress    string    `json:"-"`        // Don't expose in JSON
-	UserAgent    string    `json:"-"`        // Don't expose in JSON
-	CreatedAt    time.Time `json:"created_at"`
-	Distance     float64   `json:"distance_km,omitempty"`
+	// Quote posts: the quoted post as the viewer may see it, with its own location
+	QuotedPostID          string `json:"quoted_post_id,omitempty"`
+	QuotedPost            *Post  `json:"quoted_post,omitempty"`
+	QuotedPostUnavailable bool   `json:"quoted_post_unavailable,omitempty"` // Quoted post deleted, expired or hidden from the viewer
+	Poll                  *Poll  `json:"poll,omitempty"`
+	// @mentions in Content, resolved when the post was written
+	Mentions []Mention `json:"mentions,omitempty"`
+	// Set when the post appears in a home timeline as a plain repost
+	RepostedBy         string     `json:"reposted_by,omitempty"`
+	RepostedByUsername string     `json:"reposted_by_username,omitempty"`
+	RepostedAt         *time.Time `json:"reposted_at,omitempty"`
+	// Like and repost info
+	LikeCount    int64 `json:"like_count"`
+	CommentCount int64 `json:"comment_count"`
+	RepostCount  int64 `json:"repost_count"`
+	IsLiked      bool  `json:"is_liked"` // Whether current user has liked this post
+	// Emoji reactions, ❤️ included; likes are the ❤️ reaction
+	ReactionCounts map[string]int64 `json:"reaction_counts,omitempty"`
+	MyReaction     string           `json:"my_reaction,omitempty"`
+	IsReposted     bool             `json:"is_reposted"`   // Whether current user has reposted this post
+	IsBookmarked   bool             `json:"is_bookmarked"` // Whether current user has bookmarked this post
+	IPAddress      string           `json:"-"`             // Don't expose in JSON
+	UserAgent      string           `json:"-"`             // Don't expose in JSON
+	CreatedAt      time.Time        `json:"created_at"`
+	EditedAt       *time.Time       `json:"edited_at,omitempty"`
+	ExpiresAt      *time.Time       `json:"expires_at,omitempty"` // Set on ephemeral posts
+	Distance       float64          `json:"distance_km,omitempty"`
+}
+
+// PostRevision is a previous version of an edited post
+type PostRevision struct {
+	ID        string    `json:"id"`
+	PostID    string    `json:"post_id"`
+	EditorID  string    `json:"editor_id"`
+	Content   string    `json:"content"`
+	MediaURLs []string  `json:"media_urls,omitempty"`
+	RevisedAt time.Time `json:"revised_at"` // When this version was replaced
 }
 
 // CreatePostRequest represents the request body for creating a post
 type CreatePostRequest struct {
-	UserID    string   `json:"user_id"` // Set from auth context, not trusted from request body
+	UserID            string             `json:"user_id"` // Set from auth context, not trusted from request body
+	Content           string             `json:"content" binding:"required"`
+	MediaURLs         []string           `json:"media_urls"` // Max 4 URLs (legacy or external)
+	MediaKeys         []string           `json:"media_keys"` // Max 4 R2 object keys (Pattern B)
+	Latitude          float64            `json:"latitude" binding:"required"`
+	Longitude         float64            `json:"longitude" binding:"required"`
+	Visibility        string             `json:"visibility"`         // Defaults to public
+	LocationPrecision string             `json:"location_precision"` // Defaults to exact; privacy zones may coarsen it
+	ExpiresIn         int                `json:"expires_in"`         // Hours until the post expires (1-48); 0 keeps it
+	QuotedPostID      string             `json:"quoted_post_id"`     // Makes this a quote post of a public post
+	Poll              *CreatePollRequest `json:"poll"`               // Optional poll attached to the post
+	IPAddress         string             `json:"-"`                  // Set from request context
+	UserAgent         string             `json:"-"`                  // Set from request context
+	PostID            string             `json:"-"`                  // Preset ID, so a retried draft publish writes the same post
+}
+
+// UpdatePostRequest represents the request body for editing a post.
+// Omitting both media fields keeps the current media; sending either replaces it.
+type UpdatePostRequest struct {
 	Content   string   `json:"content" binding:"required"`
-	MediaURLs []string `json:"media_urls"` // Max 4 URLs (legacy or external)
-	MediaKeys []string `json:"media_keys"` // Max 4 R2 object keys (Pattern B)
-	Latitude  float64  `json:"latitude" binding:"required"`
-	Longitude float64  `json:"longitude" binding:"required"`
-	IPAddress string   `json:"-"` // Set from request context
-	UserAgent string   `json:"-"` // Set from request context
+	MediaURLs []string `json:"media_urls"`
+	MediaKeys []string `json:"media_keys"`
+}
+
+// ReplacesMedia reports whether the edit sets new media
+func (r *UpdatePostRequest) ReplacesMedia() bool {
+	return r.MediaURLs != nil || r.MediaKeys != nil
+}
+
+// ValidateMedia checks if media arrays have at most 4 items total.
+func (r *UpdatePostRequest) ValidateMedia() bool {
+	return len(r.MediaURLs)+len(r.MediaKeys) <= 4
+}
+
+// ============== POLLS ==============
+
+// Poll is a poll attached to a post, with its tallies
+type Poll struct {
+	Options     []PollOption `json:"options"`
+	ClosesAt    time.Time    `json:"closes_at"`
+	IsClosed    bool         `json:"is_closed"`
+	TotalVotes  int64        `json:"total_votes"`
+	VotedOption *int         `json:"voted_option,omitempty"` // Index of the current user's vote
+}
+
+// PollOption is one answer of a poll
+type PollOption struct {
+	Text      string `json:"text"`
+	VoteCount int64  `json:"vote_count"`
+}
+
+// CreatePollRequest represents the poll part of a create post request
+type CreatePollRequest struct {
+	Options  []string  `json:"options"`   // 2-4 answers
+	ClosesAt time.Time `json:"closes_at"` // When voting ends
+}
+
+// PollVoteRequest represents the request body for voting in a poll
+type PollVoteRequest struct {
+	Option *int `json:"option" binding:"required"` // Index of the chosen answer
+}
+
+// ============== MENTIONS ==============
+
+// Mention is an @username in a post or comment that resolved to a user.
+// Offset and Length count characters (Unicode code points) of the content,
+// and cover the leading '@'.
+type Mention struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+}
+
+// MentionItem is an entry of a user's mentions tab: a post, or a comment on a
+// post, that mentions them
+type MentionItem struct {
+	TargetType string    `json:"target_type"` // post or comment
+	CreatedAt  time.Time `json:"created_at"`  // When the mention was made
+	Post       *Post     `json:"post"`
+	Comment    *Comment  `json:"comment,omitempty"`
 }
 
 // GetFeedRequest represents query parameters for fetching feed
@@ -82,6 +187,7 @@ type GetFeedRequest struct {
 	RadiusKM  float64 `form:"radius_km"`
 	Limit     int     `form:"limit"`
 	Cursor    string  `form:"cursor"`
+	Sort      string  `form:"sort"`
 }
 
 // MediaCount returns the total number of media attachments on the request.
@@ -115,6 +221,33 @@ type Like struct {
 	LikeCount  int64     `json:"like_count,omitempty"`
 }
 
+// Liker is a user in the list of who liked a post or comment
+type Liker struct {
+	UserID            string    `json:"user_id"`
+	Username          string    `json:"username"`
+	ProfilePictureURL string    `json:"profile_picture_url,omitempty"`
+	IsFollowing       bool      `json:"is_following"` // Whether the current user follows them
+	LikedAt           time.Time `json:"liked_at"`
+}
+
+// Reactions. A like is the ❤️ reaction; a user has at most one reaction per
+// target.
+const (
+	ReactionLove  = "❤️"
+	ReactionLaugh = "😂"
+	ReactionWow   = "😮"
+	ReactionSad   = "😢"
+	ReactionFire  = "🔥"
+)
+
+// ValidReactions lists the allowed reactions in display order
+var ValidReactions = []string{ReactionLove, ReactionLaugh, ReactionWow, ReactionSad, ReactionFire}
+
+// ReactionRequest represents the request body for reacting to a post or comment
+type ReactionRequest struct {
+	Reaction string `json:"reaction" binding:"required"`
+}
+
 // LikeRequest represents a like action request
 type LikeRequest struct {
 	TargetType string `json:"-"` // Set from route
@@ -126,22 +259,28 @@ type LikeRequest struct {
 
 // Comment represents a comment on a post or reply to another comment
 type Comment struct {
-	ID                string     `json:"id"`
-	PostID            string     `json:"post_id"`
-	ParentID          string     `json:"parent_id,omitempty"` // null for top-level
-	UserID            string     `json:"user_id"`
-	Username          string     `json:"username,omitempty"`
-	ProfilePictureURL string     `json:"profile_picture_url,omitempty"`
-	Content           string     `json:"content"`
-	Depth             int        `json:"depth"` // 1, 2, or 3
-	LikeCount         int64      `json:"like_count"`
-	IsLiked           bool       `json:"is_liked"`
-	IPAddress         string     `json:"-"`
-	UserAgent         string     `json:"-"`
-	CreatedAt         time.Time  `json:"created_at"`
-	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
-	IsDeleted         bool       `json:"-"`
-	Replies           []Comment  `json:"replies,omitempty"` // Nested replies
+	ID                string           `json:"id"`
+	PostID            string           `json:"post_id"`
+	ParentID          string           `json:"parent_id,omitempty"` // null for top-level
+	UserID            string           `json:"user_id"`
+	Username          string           `json:"username,omitempty"`
+	ProfilePictureURL string           `json:"profile_picture_url,omitempty"`
+	Content           string           `json:"content"`
+	Mentions          []Mention        `json:"mentions,omitempty"` // @mentions in Content
+	Depth             int              `json:"depth"`              // 1, 2, or 3
+	LikeCount         int64            `json:"like_count"`
+	IsLiked           bool             `json:"is_liked"`
+	ReactionCounts    map[string]int64 `json:"reaction_counts,omitempty"` // Emoji reactions, ❤️ included
+	MyReaction        string           `json:"my_reaction,omitempty"`
+	IPAddress         string           `json:"-"`
+	UserAgent         string           `json:"-"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         *time.Time       `json:"updated_at,omitempty"`
+	IsEdited          bool             `json:"edited"` // Edited since it was posted; see comment_revisions
+	IsDeleted         bool             `json:"-"`
+	IsPinned          bool             `json:"is_pinned,omitempty"` // Pinned by the post author above the other comments
+	IsHidden          bool             `json:"is_hidden,omitempty"` // Hidden by the post author; only shown to them and the commenter
+	Replies           []Comment        `json:"replies,omitempty"`   // Nested replies
 }
 
 // CreateCommentRequest represents the request body for creating a comment
@@ -161,6 +300,65 @@ const (
 	TargetTypeComment = "comment"
 )
 
+// Comment revision actions
+const (
+	CommentRevisionEdit   = "edit"
+	CommentRevisionDelete = "delete"
+)
+
+// CommentRevision is a previous version of an edited or deleted comment
+type CommentRevision struct {
+	ID        string    `json:"id"`
+	CommentID string    `json:"comment_id"`
+	EditorID  string    `json:"editor_id"`
+	Content   string    `json:"content"`
+	Action    string    `json:"action"`     // edit or delete
+	RevisedAt time.Time `json:"revised_at"` // When this version was replaced
+}
+
+// CommentContext is a comment with the thread around it: its ancestors, top
+// level first, and its nearest siblings on each side, oldest first
+type CommentContext struct {
+	Comment       Comment   `json:"comment"`
+	Ancestors     []Comment `json:"ancestors"`
+	Before        []Comment `json:"siblings_before"`
+	After         []Comment `json:"siblings_after"`
+	HasMoreBefore bool      `json:"has_more_before"`
+	HasMoreAfter  bool      `json:"has_more_after"`
+}
+
+// Who may comment on a post
+const (
+	CommentAudienceEveryone  = "everyone"
+	CommentAudienceFollowers = "followers" // People who follow the post author
+	CommentAudienceNobody    = "nobody"
+)
+
+// CommentSettings are the comment controls a post author sets on a post. The
+// post author may always comment on their own post.
+type CommentSettings struct {
+	PostID        string     `json:"post_id"`
+	Audience      string     `json:"audience"`
+	LockAfterDays int        `json:"lock_after_days,omitempty"` // 0 = never locks
+	LocksAt       *time.Time `json:"locks_at,omitempty"`
+}
+
+// UpdateCommentSettingsRequest represents the request body for changing a
+// post's comment controls
+type UpdateCommentSettingsRequest struct {
+	Audience      string `json:"audience" binding:"required"`
+	LockAfterDays int    `json:"lock_after_days" binding:"min=0,max=3650"`
+}
+
+// ValidCommentAudience reports whether audience is a comment audience
+func ValidCommentAudience(audience string) bool {
+	switch audience {
+	case CommentAudienceEveryone, CommentAudienceFollowers, CommentAudienceNobody:
+		return true
+	}
+	return false
+}
+
 // ============== PROFILE ==============
 
 // UpdateProfileRequest represents the request body for updating a profile
@@ -172,6 +370,7 @@ type UpdateProfileRequest struct {
 	AvatarKey         string `json:"avatar_key"`
 	CoverImageURL     string `json:"cover_image_url"`
 	CoverKey          string `json:"cover_key"`
+	IsPrivate         *bool  `json:"is_private"` // Unchanged when omitted
 }
 
 // ============== FOLLOWS ==============
@@ -183,6 +382,52 @@ type Follow struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// FollowListUser is an account in a followers or following list
+type FollowListUser struct {
+	UserID            string    `json:"user_id"`
+	Username          string    `json:"username"`
+	ProfilePictureURL string    `json:"profile_picture_url,omitempty"`
+	IsFollowing       bool      `json:"is_following"` // Whether the current user follows them
+	FollowsYou        bool      `json:"follows_you"`  // Whether they follow the current user
+	FollowedAt        time.Time `json:"followed_at"`
+}
+
+// Suggestion reasons, from the strongest signal to the weakest
+const (
+	SuggestionReasonMutual     = "mutual"      // Followed by people the user follows
+	SuggestionReasonFollowsYou = "follows_you" // Follows the user, not followed back
+	SuggestionReasonLocation   = "location"    // Follows a location the user follows
+	SuggestionReasonNearby     = "nearby"      // Posts near where the user posts
+)
+
+// Suggestion is an account suggested for the user to follow, with why
+type Suggestion struct {
+	UserID            string  `json:"user_id"`
+	Username          string  `json:"username"`
+	ProfilePictureURL string  `json:"profile_picture_url,omitempty"`
+	Reason            string  `json:"reason"`
+	Explanation       string  `json:"explanation"`
+	MutualCount       int     `json:"mutual_count,omitempty"`
+	MutualID          string  `json:"-"` // One followed account who follows them
+	Place             string  `json:"place,omitempty"`
+	Score             float64 `json:"score"`
+}
+
+// FollowRequest is a pending request to follow a private account
+type FollowRequest struct {
+	UserID            string    `json:"user_id"` // Who asked to follow
+	Username          string    `json:"username,omitempty"`
+	ProfilePictureURL string    `json:"profile_picture_url,omitempty"`
+	RequestedAt       time.Time `json:"requested_at"`
+}
+
+// Follow states of the current user towards another user
+const (
+	FollowStatusNone      = "none"
+	FollowStatusRequested = "requested"
+	FollowStatusFollowing = "following"
+)
+
 // FollowCounts represents follower/following counts
 type FollowCounts struct {
 	UserID         string `json:"user_id"`
@@ -209,13 +454,31 @@ type FollowLocationRequest struct {
 	Longitude float64 `json:"longitude" binding:"required"`
 }
 
+// ============== HASHTAG FOLLOWS ==============
+
+// HashtagFollow is a hashtag a user follows
+type HashtagFollow struct {
+	Hashtag   string    `json:"hashtag"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // ============== NOTIFICATIONS ==============
 
 const (
-	NotificationTypeLike         = "like"
-	NotificationTypeComment      = "comment"
-	NotificationTypeFollow       = "follow"
-	NotificationTypeLocationPost = "location_post"
+	NotificationTypeLike           = "like"
+	NotificationTypeComment        = "comment"
+	NotificationTypeFollow         = "follow"
+	NotificationTypeFollowRequest  = "follow_request"
+	NotificationTypeFollowAccepted = "follow_accepted"
+	NotificationTypeLocationPost   = "location_post"
+	NotificationTypeRepost         = "repost"
+	NotificationTypeQuote          = "quote"
+	NotificationTypePollClosed     = "poll_closed"
+	NotificationTypeMention        = "mention"
+	NotificationTypeHashtagPost    = "hashtag_post"
+	NotificationTypeReaction       = "reaction"
+	NotificationTypeCommentPinned  = "comment_pinned"
+	NotificationTypeCommentHidden  = "comment_hidden"
 )
 
 // Notification represents a user notification (V2)
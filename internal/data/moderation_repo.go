@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gocql/gocql"
 )
@@ -337,16 +339,17 @@ func (r *ModerationRepository) GetMutedUsers(ctx context.Context, userID string)
 	return mutedUsers, nil
 }
 
-// GetBlockedAndMutedUsers returns both blocked and muted user IDs for feed filtering
-func (r *ModerationRepository) GetBlockedAndMutedUsers(ctx context.Context, userID string) (map[string]bool, error) {
-	excluded := make(map[string]bool)
+// GetBlockedEitherWay returns the user IDs the given user blocked or was
+// blocked by
+func (r *ModerationRepository) GetBlockedEitherWay(ctx context.Context, userID string) map[string]bool {
+	blockedSet := make(map[string]bool)
 
 	blocked, err := r.GetBlockedUsers(ctx, userID)
 	if err != nil {
 		slog.Warn("Failed to fetch blocked users", "error", err, "user_id", userID)
 	}
 	for _, id := range blocked {
-		excluded[id] = true
+		blockedSet[id] = true
 	}
 
 	// Also add users who have blocked the current user (bidirectional)
@@ -358,11 +361,18 @@ func (r *ModerationRepository) GetBlockedAndMutedUsers(ctx context.Context, user
 
 		var blockerID gocql.UUID
 		for iter.Scan(&blockerID) {
-			excluded[blockerID.String()] = true
+			blockedSet[blockerID.String()] = true
 		}
 		iter.Close()
 	}
 
+	return blockedSet
+}
+
+// GetBlockedAndMutedUsers returns both blocked and muted user IDs for feed filtering
+func (r *ModerationRepository) GetBlockedAndMutedUsers(ctx context.Context, userID string) (map[string]bool, error) {
+	excluded := r.GetBlockedEitherWay(ctx, userID)
+
 	muted, err := r.GetMutedUsers(ctx, userID)
 	if err != nil {
 		slog.Warn("Failed to fetch muted users", "error", err, "user_id", userID)
@@ -373,3 +383,98 @@ func (r *ModerationRepository) GetBlockedAndMutedUsers(ctx context.Context, user
 
 	return excluded, nil
 }
+
+// ============== MUTED WORDS ==============
+
+// Muted word limits
+const (
+	MaxMutedWords      = 100
+	MaxMutedWordLength = 100
+)
+
+// NormalizeMutedWord returns word as it is stored: trimmed, lowercase and
+// without a leading '#', so muting "#Foo" and "foo" is the same
+func NormalizeMutedWord(word string) (string, error) {
+	word = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(word), "#"))
+	if word == "" || utf8.RuneCountInString(word) > MaxMutedWordLength {
+		return "", fmt.Errorf("invalid muted word: %q", word)
+	}
+	return word, nil
+}
+
+// MuteWord adds a word to the user's muted words. word must be normalized.
+func (r *ModerationRepository) MuteWord(ctx context.Context, userID, word string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	var count int
+	if err := r.session.Query(`
+		SELECT COUNT(*) FROM muted_words WHERE user_id = ?
+	`, uid).WithContext(ctx).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count muted words: %w", err)
+	}
+	if count >= MaxMutedWords {
+		var existing string
+		err := r.session.Query(`
+			SELECT word FROM muted_words WHERE user_id = ? AND word = ?
+		`, uid, word).WithContext(ctx).Scan(&existing)
+		if err == gocql.ErrNotFound {
+			return fmt.Errorf("too many muted words: at most %d", MaxMutedWords)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check muted word: %w", err)
+		}
+	}
+
+	err = r.session.Query(`
+		INSERT INTO muted_words (user_id, word, created_at) VALUES (?, ?, ?)
+	`, uid, word, time.Now()).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to mute word: %w", err)
+	}
+
+	return nil
+}
+
+// UnmuteWord removes a word from the user's muted words
+func (r *ModerationRepository) UnmuteWord(ctx context.Context, userID, word string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	err = r.session.Query(`
+		DELETE FROM muted_words WHERE user_id = ? AND word = ?
+	`, uid, word).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to unmute word: %w", err)
+	}
+
+	return nil
+}
+
+// GetMutedWords returns the user's muted words in alphabetical order
+func (r *ModerationRepository) GetMutedWords(ctx context.Context, userID string) ([]string, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT word FROM muted_words WHERE user_id = ?
+	`, uid).WithContext(ctx).Iter()
+
+	words := []string{}
+	var word string
+	for iter.Scan(&word) {
+		words = append(words, word)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
@@ -1,8 +1,14 @@
 package data
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,28 +26,222 @@ type PaginatedResponse struct {
 	Count      int    `json:"count"`
 }
 
-// EncodeCursor encodes a timestamp into a cursor string
-func EncodeCursor(t time.Time) string {
-	return base64.StdEncoding.EncodeToString([]byte(t.Format(time.RFC3339Nano)))
+// CursorVersion is the current wire format of pagination cursors
+const CursorVersion = 1
+
+// Cursor scopes: a cursor issued for one listing is rejected by every other
+const (
+	CursorScopeFeed           = "feed"
+	CursorScopeFeedHot        = "feed_hot"
+	CursorScopeFeedNearest    = "feed_nearest"
+	CursorScopeFollowing      = "following"
+	CursorScopeUserPosts      = "user_posts"
+	CursorScopeLikedPosts     = "liked_posts"
+	CursorScopeComments       = "comments"
+	CursorScopeCommentsOldest = "comments_oldest"
+	CursorScopeCommentsTop    = "comments_top"
+	CursorScopeReplies        = "replies"
+	CursorScopeBookmarks      = "bookmarks"
+	CursorScopeMentions       = "mentions"
+	CursorScopeHashtag        = "hashtag"
+	CursorScopeLikers         = "likers"
+	CursorScopeFollowRequests = "follow_requests"
+	CursorScopeFollowerList   = "follower_list"
+	CursorScopeFollowingList  = "following_list"
+	// People the current user follows come first in liker lists, on their
+	// own cursor scope
+	CursorScopeLikersFollowed = "likers_followed"
+)
+
+// Keyset is a position in a listing ordered by (created_at DESC, id ASC).
+// The ID breaks ties between rows created in the same millisecond.
+type Keyset struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// IsZero reports whether the keyset is the start of the listing
+func (k Keyset) IsZero() bool {
+	return k.CreatedAt.IsZero()
+}
+
+// Admits reports whether a row at (createdAt, id) comes after the keyset
+func (k Keyset) Admits(createdAt time.Time, id string) bool {
+	if k.IsZero() {
+		return true
+	}
+	if createdAt.Before(k.CreatedAt) {
+		return true
+	}
+	return createdAt.Equal(k.CreatedAt) && id > k.ID
+}
+
+// KeysetBefore reports whether row a sorts before row b in keyset order
+func KeysetBefore(aTime time.Time, aID string, bTime time.Time, bID string) bool {
+	if !aTime.Equal(bTime) {
+		return aTime.After(bTime)
+	}
+	return aID < bID
+}
+
+// keysetScanDone reports whether a newest-first scan holding n rows, the last
+// created at lastAt, can stop before a row created at next. Rows sharing the
+// boundary timestamp are always read so the page can be ordered by ID.
+func keysetScanDone(n, limit int, lastAt, next time.Time) bool {
+	return n >= limit && !next.Equal(lastAt)
+}
+
+// CursorOrigin pins the query a feed cursor was issued for
+type CursorOrigin struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+	RadiusKM  float64 `json:"r"`
+}
+
+// CursorRank is a position in a listing ranked by score (DESC, then ID ASC)
+// rather than by time. AsOf freezes the candidate set and the clock the scores
+// were computed with, so every page ranks the same posts the same way.
+type CursorRank struct {
+	AsOf  time.Time
+	Score float64
+}
+
+// RankBefore reports whether row a sorts before row b in ranked order
+func RankBefore(aScore float64, aID string, bScore float64, bID string) bool {
+	if aScore != bScore {
+		return aScore > bScore
+	}
+	return aID < bID
+}
+
+// Cursor is an opaque, versioned and HMAC-signed pagination position
+type Cursor struct {
+	Scope  string
+	Keyset Keyset
+	Origin *CursorOrigin
+	Rank   *CursorRank
+}
+
+type cursorPayload struct {
+	Version   int           `json:"v"`
+	Scope     string        `json:"s"`
+	CreatedAt int64         `json:"t"`
+	ID        string        `json:"id"`
+	Origin    *CursorOrigin `json:"o,omitempty"`
+	Rank      *cursorRank   `json:"r,omitempty"`
+}
+
+type cursorRank struct {
+	AsOf  int64   `json:"a"`
+	Score float64 `json:"s"`
 }
 
-// DecodeCursor decodes a cursor string into a timestamp
-func DecodeCursor(cursor string) (time.Time, error) {
+var (
+	cursorKeyMu sync.RWMutex
+	cursorKey   = randomCursorKey()
+)
+
+func randomCursorKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("cursor key: %v", err))
+	}
+	return key
+}
+
+// SetCursorSigningKey sets the HMAC key used to sign cursors. Without it a
+// random per-process key is used, so cursors do not survive restarts or work
+// across instances.
+func SetCursorSigningKey(key []byte) {
+	if len(key) == 0 {
+		return
+	}
+	cursorKeyMu.Lock()
+	defer cursorKeyMu.Unlock()
+	cursorKey = append([]byte(nil), key...)
+}
+
+// DeriveCursorSigningKey derives a cursor key from another secret, such as
+// the JWT secret, so the secret itself is never used to sign cursors
+func DeriveCursorSigningKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("cursor"))
+	return mac.Sum(nil)
+}
+
+func signCursor(payload []byte) []byte {
+	cursorKeyMu.RLock()
+	defer cursorKeyMu.RUnlock()
+	mac := hmac.New(sha256.New, cursorKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// EncodeCursor serializes and signs a cursor
+func EncodeCursor(c Cursor) string {
+	p := cursorPayload{
+		Version:   CursorVersion,
+		Scope:     c.Scope,
+		CreatedAt: c.Keyset.CreatedAt.UnixMilli(),
+		ID:        c.Keyset.ID,
+		Origin:    c.Origin,
+	}
+	if c.Rank != nil {
+		p.Rank = &cursorRank{AsOf: c.Rank.AsOf.UnixMilli(), Score: c.Rank.Score}
+	}
+	payload, _ := json.Marshal(p)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signCursor(payload))
+}
+
+// DecodeCursor verifies and decodes a cursor issued for scope.
+// An empty string decodes to the zero Cursor (first page).
+func DecodeCursor(scope, cursor string) (Cursor, error) {
 	if cursor == "" {
-		return time.Time{}, nil
+		return Cursor{Scope: scope}, nil
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(cursor)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid cursor")
+	encodedPayload, encodedSig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return Cursor{}, fmt.Errorf("invalid cursor")
 	}
 
-	t, err := time.Parse(time.RFC3339Nano, string(decoded))
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid cursor format")
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	if !hmac.Equal(sig, signCursor(payload)) {
+		return Cursor{}, fmt.Errorf("invalid cursor signature")
 	}
 
-	return t, nil
+	var p cursorPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor format")
+	}
+	if p.Version != CursorVersion {
+		return Cursor{}, fmt.Errorf("unsupported cursor version %d", p.Version)
+	}
+	if p.Scope != scope {
+		return Cursor{}, fmt.Errorf("cursor not valid for this listing")
+	}
+
+	c := Cursor{
+		Scope: p.Scope,
+		Keyset: Keyset{
+			CreatedAt: time.UnixMilli(p.CreatedAt).UTC(),
+			ID:        p.ID,
+		},
+		Origin: p.Origin,
+	}
+	if p.Rank != nil {
+		c.Rank = &CursorRank{AsOf: time.UnixMilli(p.Rank.AsOf).UTC(), Score: p.Rank.Score}
+	}
+	return c, nil
 }
 
 // GetDefaultLimit returns the limit with defaults applied
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	ts := time.Date(2026, 1, 5, 10, 30, 0, 123000000, time.UTC)
+
+	t.Run("Round Trip", func(t *testing.T) {
+		in := Cursor{
+			Scope:  CursorScopeFeed,
+			Keyset: Keyset{CreatedAt: ts, ID: "5b6f2a70-ea1c-11f0-8000-000000000001"},
+			Origin: &CursorOrigin{Latitude: -6.1754, Longitude: 106.8272, RadiusKM: 10},
+		}
+		out, err := DecodeCursor(CursorScopeFeed, EncodeCursor(in))
+		require.NoError(t, err)
+		assert.True(t, ts.Equal(out.Keyset.CreatedAt))
+		assert.Equal(t, in.Keyset.ID, out.Keyset.ID)
+		assert.Equal(t, in.Origin, out.Origin)
+	})
+
+	t.Run("Empty Is First Page", func(t *testing.T) {
+		out, err := DecodeCursor(CursorScopeFeed, "")
+		require.NoError(t, err)
+		assert.True(t, out.Keyset.IsZero())
+		assert.Nil(t, out.Origin)
+	})
+
+	t.Run("Rejects Tampering", func(t *testing.T) {
+		encoded := EncodeCursor(Cursor{Scope: CursorScopeUserPosts, Keyset: Keyset{CreatedAt: ts, ID: "a"}})
+		forged := EncodeCursor(Cursor{Scope: CursorScopeUserPosts, Keyset: Keyset{CreatedAt: ts, ID: "b"}})
+
+		payload, _, _ := strings.Cut(forged, ".")
+		_, sig, _ := strings.Cut(encoded, ".")
+		_, err := DecodeCursor(CursorScopeUserPosts, payload+"."+sig)
+		assert.Error(t, err)
+
+		_, err = DecodeCursor(CursorScopeUserPosts, "MjAyNi0wMS0wNVQxMDozMDowMFo=")
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects Other Scope", func(t *testing.T) {
+		encoded := EncodeCursor(Cursor{Scope: CursorScopeComments, Keyset: Keyset{CreatedAt: ts, ID: "a"}})
+		_, err := DecodeCursor(CursorScopeReplies, encoded)
+		assert.Error(t, err)
+	})
+
+	t.Run("Derived Key Differs From Secret", func(t *testing.T) {
+		secret := []byte("jwt-secret")
+		key := DeriveCursorSigningKey(secret)
+		assert.Len(t, key, 32)
+		assert.NotEqual(t, secret, key)
+		assert.Equal(t, key, DeriveCursorSigningKey(secret))
+	})
+}
+
+func TestKeysetPagination(t *testing.T) {
+	ts := time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC)
+
+	// Five posts, three of them created in the same millisecond
+	var posts []Post
+	for _, p := range []struct {
+		id string
+		at time.Time
+	}{
+		{"c", ts}, {"a", ts}, {"e", ts.Add(-time.Second)}, {"b", ts}, {"d", ts.Add(time.Second)},
+	} {
+		posts = append(posts, Post{ID: p.id, CreatedAt: p.at})
+	}
+	SortPostsByKeyset(posts)
+
+	var ids []string
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	require.Equal(t, []string{"d", "a", "b", "c", "e"}, ids)
+
+	// Walk two at a time: every post is seen exactly once
+	var seen []string
+	var after Keyset
+	for {
+		var page []Post
+		for _, p := range posts {
+			if after.Admits(p.CreatedAt, p.ID) && len(page) < 2 {
+				page = append(page, p)
+			}
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, p := range page {
+			seen = append(seen, p.ID)
+		}
+		last := page[len(page)-1]
+		after = Keyset{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	assert.Equal(t, ids, seen)
+}
+
+func TestRepostKeyset(t *testing.T) {
+	ts := time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC)
+	repostedAt := ts.Add(2 * time.Hour)
+
+	// An old post reposted later sorts at the time of the repost
+	posts := []Post{
+		{ID: "old", CreatedAt: ts.Add(-24 * time.Hour), RepostedAt: &repostedAt},
+		{ID: "new", CreatedAt: ts.Add(time.Hour)},
+		{ID: "older", CreatedAt: ts},
+	}
+	SortPostsByKeyset(posts)
+
+	var ids []string
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	assert.Equal(t, []string{"old", "new", "older"}, ids)
+	assert.Equal(t, Keyset{CreatedAt: repostedAt, ID: "old"}, posts[0].Keyset())
+	assert.Equal(t, Keyset{CreatedAt: ts, ID: "older"}, posts[2].Keyset())
+}
@@ -0,0 +1,477 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/cache"
+)
+
+// Poll bounds
+const (
+	MinPollOptions      = 2
+	MaxPollOptions      = 4
+	MaxPollOptionLength = 80
+	MinPollDuration     = 5 * time.Minute
+	MaxPollDuration     = 7 * 24 * time.Hour
+)
+
+// pollCloseLookback is how far back the poll closer looks for polls it has
+// not closed yet. Index rows and Redis tallies outlive the poll by the same
+// margin.
+const pollCloseLookback = 24 * time.Hour
+
+// PollCloseBucket returns the polls_by_close partition for a closing time
+func PollCloseBucket(closesAt time.Time) time.Time {
+	return closesAt.UTC().Truncate(time.Hour)
+}
+
+// Validate checks the poll of a post created at now
+func (r *CreatePollRequest) Validate(now time.Time) error {
+	if len(r.Options) < MinPollOptions || len(r.Options) > MaxPollOptions {
+		return fmt.Errorf("a poll needs %d to %d options", MinPollOptions, MaxPollOptions)
+	}
+
+	seen := make(map[string]bool, len(r.Options))
+	for _, option := range r.Options {
+		text := strings.TrimSpace(option)
+		if text == "" {
+			return fmt.Errorf("poll options cannot be empty")
+		}
+		if len([]rune(text)) > MaxPollOptionLength {
+			return fmt.Errorf("poll options must be at most %d characters", MaxPollOptionLength)
+		}
+		if seen[strings.ToLower(text)] {
+			return fmt.Errorf("poll options must be different")
+		}
+		seen[strings.ToLower(text)] = true
+	}
+
+	duration := r.ClosesAt.Sub(now)
+	if duration < MinPollDuration || duration > MaxPollDuration {
+		return fmt.Errorf("closes_at must be between 5 minutes and 7 days from now")
+	}
+	return nil
+}
+
+// newPoll returns a poll without votes
+func newPoll(req *CreatePollRequest) *Poll {
+	poll := &Poll{ClosesAt: req.ClosesAt}
+	for _, text := range req.Options {
+		poll.Options = append(poll.Options, PollOption{Text: text})
+	}
+	return poll
+}
+
+// addPollToBatch adds the rows of a new post's poll to the batch creating
+// the post. The poll shares the post's TTL.
+func addPollToBatch(batch *gocql.Batch, postID, userID gocql.UUID, req *CreatePollRequest, createdAt time.Time, ttl int) {
+	batch.Query(`
+		INSERT INTO polls (post_id, user_id, options, closes_at, closed, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		USING TTL ?
+	`, postID, userID, req.Options, req.ClosesAt, false, createdAt, ttl)
+
+	// Index the poll for the poll closer, which publishes its results
+	batch.Query(`
+		INSERT INTO polls_by_close (close_bucket, closes_at, post_id)
+		VALUES (?, ?, ?)
+		USING TTL ?
+	`, PollCloseBucket(req.ClosesAt), req.ClosesAt, postID, int(req.ClosesAt.Sub(createdAt).Seconds()+pollCloseLookback.Seconds()))
+}
+
+// PollRepository handles poll votes with Cassandra (state) and Redis (tallies)
+type PollRepository struct {
+	session     *gocql.Session
+	pollCounter *cache.PollCounter
+}
+
+// NewPollRepository creates a new PollRepository
+// pollCounter can be nil if Redis is not available (tallies are then counted from Cassandra)
+func NewPollRepository(session *gocql.Session, pollCounter *cache.PollCounter) *PollRepository {
+	return &PollRepository{
+		session:     session,
+		pollCounter: pollCounter,
+	}
+}
+
+// PollVoteResult represents the result of a vote
+type PollVoteResult struct {
+	Poll    *Poll `json:"poll"`
+	Changed bool  `json:"changed"` // Whether the vote was recorded now
+}
+
+// pollRow is a row of the polls table
+type pollRow struct {
+	postID      gocql.UUID
+	userID      gocql.UUID
+	options     []string
+	closesAt    time.Time
+	closed      bool
+	finalCounts []int64
+	ttl         int
+}
+
+// isOpen reports whether the poll still takes votes at now
+func (p *pollRow) isOpen(now time.Time) bool {
+	return !p.closed && now.Before(p.closesAt)
+}
+
+// getPollRows reads the polls of the given posts, keyed by post ID
+func (r *PollRepository) getPollRows(ctx context.Context, postIDs []gocql.UUID) (map[string]*pollRow, error) {
+	rows := make(map[string]*pollRow, len(postIDs))
+	if len(postIDs) == 0 {
+		return rows, nil
+	}
+
+	iter := r.session.Query(`
+		SELECT post_id, user_id, options, closes_at, closed, final_counts, TTL(options)
+		FROM polls WHERE post_id IN ?
+	`, postIDs).WithContext(ctx).Iter()
+
+	row := &pollRow{}
+	for iter.Scan(&row.postID, &row.userID, &row.options, &row.closesAt, &row.closed, &row.finalCounts, &row.ttl) {
+		rows[row.postID.String()] = row
+		row = &pollRow{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get polls: %w", err)
+	}
+	return rows, nil
+}
+
+// getPollRow reads the poll of a single post
+func (r *PollRepository) getPollRow(ctx context.Context, postID gocql.UUID) (*pollRow, error) {
+	rows, err := r.getPollRows(ctx, []gocql.UUID{postID})
+	if err != nil {
+		return nil, err
+	}
+	row, ok := rows[postID.String()]
+	if !ok {
+		return nil, fmt.Errorf("poll not found")
+	}
+	return row, nil
+}
+
+// tallyVotes counts a poll's votes from Cassandra and returns the voters
+func (r *PollRepository) tallyVotes(ctx context.Context, postID gocql.UUID, numOptions int) ([]int64, []string, error) {
+	iter := r.session.Query(`
+		SELECT user_id, option_index FROM poll_votes WHERE post_id = ?
+	`, postID).WithContext(ctx).PageSize(1000).Iter()
+
+	counts := make([]int64, numOptions)
+	var voters []string
+	var userID gocql.UUID
+	var option int
+	for iter.Scan(&userID, &option) {
+		if option >= 0 && option < numOptions {
+			counts[option]++
+		}
+		voters = append(voters, userID.String())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to count poll votes: %w", err)
+	}
+	return counts, voters, nil
+}
+
+// liveCounts returns the tallies of polls that have no final results yet.
+// Open polls are read from Redis; polls past their closing time, and every
+// poll when Redis is unavailable, are counted from Cassandra.
+func (r *PollRepository) liveCounts(ctx context.Context, rows []*pollRow, now time.Time) map[string][]int64 {
+	counts := make(map[string][]int64, len(rows))
+
+	var fromRedis []string
+	optionCounts := make(map[string]int)
+	if r.pollCounter != nil {
+		for _, row := range rows {
+			if row.isOpen(now) {
+				fromRedis = append(fromRedis, row.postID.String())
+				optionCounts[row.postID.String()] = len(row.options)
+			}
+		}
+	}
+	if len(fromRedis) > 0 {
+		redisCounts, err := r.pollCounter.GetVoteCountsBatch(ctx, fromRedis, optionCounts)
+		if err != nil {
+			slog.Warn("Redis poll tallies failed, falling back to Cassandra", "error", err)
+		}
+		for id, tally := range redisCounts {
+			counts[id] = tally
+		}
+	}
+
+	for _, row := range rows {
+		id := row.postID.String()
+		if _, ok := counts[id]; ok {
+			continue
+		}
+		tally, _, err := r.tallyVotes(ctx, row.postID, len(row.options))
+		if err != nil {
+			slog.Warn("Failed to count poll votes", "error", err, "post_id", id)
+			tally = make([]int64, len(row.options))
+		}
+		counts[id] = tally
+	}
+	return counts
+}
+
+// GetPollsForPosts returns the polls of the posts that have one, keyed by
+// post ID, with their tallies and userID's vote
+func (r *PollRepository) GetPollsForPosts(ctx context.Context, postIDs []string, userID string) (map[string]*Poll, error) {
+	result := make(map[string]*Poll)
+
+	ids := make([]gocql.UUID, 0, len(postIDs))
+	for _, id := range postIDs {
+		if pid, err := gocql.ParseUUID(id); err == nil {
+			ids = append(ids, pid)
+		}
+	}
+	rows, err := r.getPollRows(ctx, ids)
+	if err != nil || len(rows) == 0 {
+		return result, err
+	}
+
+	now := time.Now()
+	var pending []*pollRow
+	withPoll := make([]gocql.UUID, 0, len(rows))
+	for _, row := range rows {
+		if len(row.finalCounts) != len(row.options) {
+			pending = append(pending, row)
+		}
+		withPoll = append(withPoll, row.postID)
+	}
+	counts := r.liveCounts(ctx, pending, now)
+
+	for id, row := range rows {
+		tally, ok := counts[id]
+		if !ok {
+			tally = row.finalCounts
+		}
+		poll := &Poll{ClosesAt: row.closesAt, IsClosed: !row.isOpen(now)}
+		for i, text := range row.options {
+			poll.Options = append(poll.Options, PollOption{Text: text, VoteCount: tally[i]})
+			poll.TotalVotes += tally[i]
+		}
+		result[id] = poll
+	}
+
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return result, nil
+	}
+	iter := r.session.Query(`
+		SELECT post_id, option_index FROM poll_votes WHERE post_id IN ? AND user_id = ?
+	`, withPoll, uid).WithContext(ctx).Iter()
+	var postID gocql.UUID
+	var option int
+	for iter.Scan(&postID, &option) {
+		voted := option
+		result[postID.String()].VotedOption = &voted
+	}
+	if err := iter.Close(); err != nil {
+		return result, fmt.Errorf("failed to get poll votes: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetPoll returns a post's poll with its tallies and userID's vote
+func (r *PollRepository) GetPoll(ctx context.Context, postID, userID string) (*Poll, error) {
+	polls, err := r.GetPollsForPosts(ctx, []string{postID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	poll, ok := polls[postID]
+	if !ok {
+		return nil, fmt.Errorf("poll not found")
+	}
+	return poll, nil
+}
+
+// Vote records userID's vote for an option of a post's poll. Each user votes
+// once: voting again, for any option, is a no-op that keeps the first vote.
+// Uses Cassandra LWT for the vote, Redis for the tallies.
+func (r *PollRepository) Vote(ctx context.Context, postIDStr, userIDStr string, option int) (*PollVoteResult, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	row, err := r.getPollRow(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !row.isOpen(now) {
+		return nil, fmt.Errorf("poll closed")
+	}
+	if option < 0 || option >= len(row.options) {
+		return nil, fmt.Errorf("invalid option: %d", option)
+	}
+
+	// The vote shares the poll's TTL, so votes on ephemeral posts expire with them
+	applied, err := r.session.Query(`
+		INSERT INTO poll_votes (post_id, user_id, option_index, created_at)
+		VALUES (?, ?, ?, ?)
+		IF NOT EXISTS
+		USING TTL ?
+	`, postID, userID, option, now, row.ttl).WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to vote: %w", err)
+	}
+
+	// Only count the vote if it was recorded now
+	if applied && r.pollCounter != nil {
+		expireAt := row.closesAt.Add(pollCloseLookback)
+		if _, err := r.pollCounter.IncrementVoteCount(ctx, postIDStr, option, expireAt); err != nil {
+			slog.Warn("Failed to increment Redis poll tally", "error", err, "post_id", postIDStr)
+			go r.SyncVoteCounts(context.Background(), postIDStr)
+		}
+	}
+
+	poll, err := r.GetPoll(ctx, postIDStr, userIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return &PollVoteResult{Poll: poll, Changed: applied}, nil
+}
+
+// SyncVoteCounts rebuilds a poll's Redis tallies from Cassandra
+func (r *PollRepository) SyncVoteCounts(ctx context.Context, postIDStr string) error {
+	if r.pollCounter == nil {
+		return fmt.Errorf("Redis counter not available")
+	}
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	row, err := r.getPollRow(ctx, postID)
+	if err != nil {
+		return err
+	}
+	counts, _, err := r.tallyVotes(ctx, postID, len(row.options))
+	if err != nil {
+		return err
+	}
+	return r.pollCounter.SetVoteCounts(ctx, postIDStr, counts, row.closesAt.Add(pollCloseLookback))
+}
+
+// ClosedPoll is a poll closed by the poll closer, with its final results
+type ClosedPoll struct {
+	PostID     string
+	AuthorID   string
+	Options    []PollOption
+	TotalVotes int64
+	Voters     []string
+}
+
+// ClosePolls closes polls whose closing time is at or before now: it counts
+// their votes from Cassandra, stores the final results and returns the closed
+// polls so the caller can tell their voters. Each poll is closed exactly once,
+// so several API instances may run the poll closer. At most limit polls are
+// closed per call.
+func (r *PollRepository) ClosePolls(ctx context.Context, now time.Time, limit int) ([]ClosedPoll, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var closed []ClosedPoll
+	for bucket := PollCloseBucket(now.Add(-pollCloseLookback)); !bucket.After(PollCloseBucket(now)); bucket = bucket.Add(time.Hour) {
+		iter := r.session.Query(`
+			SELECT closes_at, post_id FROM polls_by_close
+			WHERE close_bucket = ? AND closes_at <= ?
+		`, bucket, now).WithContext(ctx).PageSize(limit).Iter()
+
+		type dueEntry struct {
+			closesAt time.Time
+			postID   gocql.UUID
+		}
+		var due []dueEntry
+		var entry dueEntry
+		for len(closed)+len(due) < limit && iter.Scan(&entry.closesAt, &entry.postID) {
+			due = append(due, entry)
+		}
+		if err := iter.Close(); err != nil {
+			return closed, fmt.Errorf("failed to read due polls: %w", err)
+		}
+
+		for _, entry := range due {
+			poll, err := r.closePoll(ctx, entry.postID)
+			if err != nil {
+				return closed, fmt.Errorf("failed to close poll %s: %w", entry.postID, err)
+			}
+			if poll != nil {
+				closed = append(closed, *poll)
+			}
+
+			if err := r.session.Query(`
+				DELETE FROM polls_by_close WHERE close_bucket = ? AND closes_at = ? AND post_id = ?
+			`, bucket, entry.closesAt, entry.postID).WithContext(ctx).Exec(); err != nil {
+				return closed, fmt.Errorf("failed to remove closed poll %s from the index: %w", entry.postID, err)
+			}
+		}
+
+		if len(closed) >= limit {
+			break
+		}
+	}
+
+	return closed, nil
+}
+
+// closePoll stores a poll's final results. It returns nil if the poll is gone
+// (its post was deleted or expired) or another instance closed it first.
+func (r *PollRepository) closePoll(ctx context.Context, postID gocql.UUID) (*ClosedPoll, error) {
+	row, err := r.getPollRow(ctx, postID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if row.closed {
+		return nil, nil
+	}
+
+	counts, voters, err := r.tallyVotes(ctx, postID, len(row.options))
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the poll's TTL so the final results expire with an ephemeral post
+	applied, err := r.session.Query(`
+		UPDATE polls USING TTL ? SET closed = true, final_counts = ?
+		WHERE post_id = ?
+		IF closed = false
+	`, row.ttl, counts, postID).WithContext(ctx).MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, nil
+	}
+
+	if r.pollCounter != nil {
+		if err := r.pollCounter.DeleteVoteCounts(ctx, postID.String()); err != nil {
+			slog.Warn("Failed to delete Redis poll tally", "error", err, "post_id", postID.String())
+		}
+	}
+
+	poll := &ClosedPoll{PostID: postID.String(), AuthorID: row.userID.String(), Voters: voters}
+	for i, text := range row.options {
+		poll.Options = append(poll.Options, PollOption{Text: text, VoteCount: counts[i]})
+		poll.TotalVotes += counts[i]
+	}
+	return poll, nil
+}
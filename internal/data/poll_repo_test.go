@@ -0,0 +1,96 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollRepository_Integration(t *testing.T) {
+	// Pass nil for pollCounter to test Cassandra-only mode
+	repo := NewPollRepository(testSession, nil)
+	postRepo := NewPostRepository(testSession, nil)
+	ctx := context.Background()
+
+	authorID := uuid.New().String()
+	voterID := uuid.New().String()
+	closesAt := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	post, err := postRepo.CreatePost(ctx, &CreatePostRequest{
+		UserID:    authorID,
+		Content:   "Which café on this street is best?",
+		Latitude:  -6.2088,
+		Longitude: 106.8456,
+		Poll: &CreatePollRequest{
+			Options:  []string{"Kopi Kenangan", "Tanamera", "Anomali"},
+			ClosesAt: closesAt,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, post.Poll)
+	assert.Len(t, post.Poll.Options, 3)
+
+	t.Run("Vote Once", func(t *testing.T) {
+		result, err := repo.Vote(ctx, post.ID, voterID, 1)
+		require.NoError(t, err)
+		assert.True(t, result.Changed)
+		assert.Equal(t, int64(1), result.Poll.Options[1].VoteCount)
+		assert.Equal(t, int64(1), result.Poll.TotalVotes)
+		require.NotNil(t, result.Poll.VotedOption)
+		assert.Equal(t, 1, *result.Poll.VotedOption)
+
+		// A second vote, for any option, keeps the first
+		result, err = repo.Vote(ctx, post.ID, voterID, 2)
+		require.NoError(t, err)
+		assert.False(t, result.Changed)
+		assert.Equal(t, int64(0), result.Poll.Options[2].VoteCount)
+		assert.Equal(t, 1, *result.Poll.VotedOption)
+	})
+
+	t.Run("Invalid Option", func(t *testing.T) {
+		_, err := repo.Vote(ctx, post.ID, uuid.New().String(), 3)
+		assert.ErrorContains(t, err, "invalid option")
+	})
+
+	t.Run("Posts Without Poll", func(t *testing.T) {
+		polls, err := repo.GetPollsForPosts(ctx, []string{post.ID, uuid.New().String()}, authorID)
+		require.NoError(t, err)
+		require.Len(t, polls, 1)
+		assert.Nil(t, polls[post.ID].VotedOption)
+		assert.False(t, polls[post.ID].IsClosed)
+	})
+
+	t.Run("Close Poll", func(t *testing.T) {
+		closed, err := repo.ClosePolls(ctx, closesAt.Add(time.Second), 100)
+		require.NoError(t, err)
+
+		var found *ClosedPoll
+		for i := range closed {
+			if closed[i].PostID == post.ID {
+				found = &closed[i]
+			}
+		}
+		require.NotNil(t, found)
+		assert.Equal(t, authorID, found.AuthorID)
+		assert.Equal(t, []string{voterID}, found.Voters)
+		assert.Equal(t, int64(1), found.TotalVotes)
+
+		// Closing is done once, and a closed poll takes no votes
+		closed, err = repo.ClosePolls(ctx, closesAt.Add(time.Second), 100)
+		require.NoError(t, err)
+		for _, p := range closed {
+			assert.NotEqual(t, post.ID, p.PostID)
+		}
+		_, err = repo.Vote(ctx, post.ID, uuid.New().String(), 0)
+		assert.ErrorContains(t, err, "poll closed")
+
+		poll, err := repo.GetPoll(ctx, post.ID, voterID)
+		require.NoError(t, err)
+		assert.True(t, poll.IsClosed)
+		assert.Equal(t, int64(1), poll.Options[1].VoteCount)
+	})
+}
@@ -0,0 +1,76 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Lifetime bounds for ephemeral posts
+const (
+	MinPostLifetime = time.Hour
+	MaxPostLifetime = 48 * time.Hour
+)
+
+// expirySweepLookback is how far back the sweeper looks for expired posts it
+// has not cleaned up yet. Index rows outlive their post by the same margin.
+const expirySweepLookback = 24 * time.Hour
+
+// ExpiryBucket returns the posts_by_expiry partition for an expiry time
+func ExpiryBucket(expiresAt time.Time) time.Time {
+	return expiresAt.UTC().Truncate(time.Hour)
+}
+
+// ExpiredPost is an ephemeral post removed by the expiry sweeper
+type ExpiredPost struct {
+	PostID    string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// SweepExpiredPosts cleans up ephemeral posts that expired at or before now.
+// Their rows are already gone through the TTL; this removes the likes,
+// comments, edit history and counters left behind and returns the swept posts
+// so the caller can drop them from search. At most limit posts are swept per
+// call.
+func (r *PostRepository) SweepExpiredPosts(ctx context.Context, now time.Time, limit int) ([]ExpiredPost, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+
+	var swept []ExpiredPost
+	for bucket := ExpiryBucket(now.Add(-expirySweepLookback)); !bucket.After(ExpiryBucket(now)); bucket = bucket.Add(time.Hour) {
+		iter := r.session.Query(`
+			SELECT expires_at, post_id, user_id, created_at, latitude, longitude, location_precision
+			FROM posts_by_expiry
+			WHERE expiry_bucket = ? AND expires_at <= ?
+		`, bucket, now).WithContext(ctx).PageSize(limit).Iter()
+
+		var due []postLocation
+		var loc postLocation
+		for len(swept)+len(due) < limit && iter.Scan(&loc.expiresAt, &loc.postID, &loc.userID, &loc.createdAt, &loc.latitude, &loc.longitude, &loc.locationPrecision) {
+			due = append(due, loc)
+			loc = postLocation{}
+		}
+		if err := iter.Close(); err != nil {
+			return swept, fmt.Errorf("failed to read expired posts: %w", err)
+		}
+
+		for _, loc := range due {
+			if err := r.removePost(ctx, loc); err != nil {
+				return swept, fmt.Errorf("failed to remove expired post %s: %w", loc.postID, err)
+			}
+			swept = append(swept, ExpiredPost{
+				PostID:    loc.postID.String(),
+				UserID:    loc.userID.String(),
+				ExpiresAt: loc.expiresAt,
+			})
+		}
+
+		if len(swept) >= limit {
+			break
+		}
+	}
+
+	return swept, nil
+}
@@ -3,53 +3,166 @@ package data
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/cache"
 )
 
 type PostRepository struct {
-	session *gocql.Session
+	session     *gocql.Session
+	likeCounter *cache.LikeCounter
+}
+
+// NewPostRepository creates a post repository. likeCounter can be nil if Redis
+// is not available; it is only used to drop the counters of removed posts.
+func NewPostRepository(session *gocql.Session, likeCounter *cache.LikeCounter) *PostRepository {
+	return &PostRepository{session: session, likeCounter: likeCounter}
+}
+
+// geoPartition is one proximity partition a post is written to
+type geoPartition struct {
+	table  string
+	prefix string
 }
 
-func NewPostRepository(session *gocql.Session) *PostRepository {
-	return &PostRepository{session: session}
+// geoTable returns the proximity table that holds partitions of the given precision
+func geoTable(precision uint) string {
+	if precision == DefaultGeohashPrecision {
+		return "posts_by_geohash"
+	}
+	return "posts_by_geocell"
+}
+
+// postGeoPartitions lists every proximity partition a post at (lat, lng) belongs to.
+// Posts with a hidden location are in none.
+func postGeoPartitions(lat, lng float64, locationPrecision string) []geoPartition {
+	if postLocationPrecision(locationPrecision) == LocationPrecisionHidden {
+		return nil
+	}
+	partitions := []geoPartition{{table: geoTable(DefaultGeohashPrecision), prefix: GetGeohashPrefix(lat, lng)}}
+	for _, precision := range GeocellPrecisions {
+		partitions = append(partitions, geoPartition{table: geoTable(precision), prefix: EncodeGeohash(lat, lng, precision)})
+	}
+	return partitions
 }
 
 // CreatePost inserts a new post into all denormalized tables
 func (r *PostRepository) CreatePost(ctx context.Context, req *CreatePostRequest) (*Post, error) {
 	postID := gocql.TimeUUID()
+	if req.PostID != "" {
+		id, err := gocql.ParseUUID(req.PostID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid post_id: %w", err)
+		}
+		postID = id
+	}
 	userID, err := gocql.ParseUUID(req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user_id: %w", err)
 	}
 
+	visibility := postVisibility(req.Visibility)
+	if !ValidVisibilities[visibility] {
+		return nil, fmt.Errorf("invalid visibility: %s", req.Visibility)
+	}
+
+	locationPrecision := postLocationPrecision(req.LocationPrecision)
+	if !ValidLocationPrecision(locationPrecision) {
+		return nil, fmt.Errorf("invalid location_precision: %s", req.LocationPrecision)
+	}
+
+	var quotedPostID interface{} = gocql.UnsetValue
+	if req.QuotedPostID != "" {
+		id, err := gocql.ParseUUID(req.QuotedPostID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted_post_id: %w", err)
+		}
+		quotedPostID = id
+	}
+
+	// Only the coarsened location is stored, so nothing finer can leak from the post tables
 	now := time.Now()
-	fullGeohash := EncodeGeohash(req.Latitude, req.Longitude, 7)
-	geohashPrefix := GetGeohashPrefix(req.Latitude, req.Longitude)
+	latitude, longitude, fullGeohash := CoarsenLocation(req.Latitude, req.Longitude, locationPrecision)
+
+	// Ephemeral posts are written with a TTL; TTL 0 keeps a row forever
+	ttl := 0
+	var expiresAt *time.Time
+	var expiresAtCol interface{} = gocql.UnsetValue
+	if req.ExpiresIn != 0 {
+		lifetime := time.Duration(req.ExpiresIn) * time.Hour
+		if lifetime < MinPostLifetime || lifetime > MaxPostLifetime {
+			return nil, fmt.Errorf("invalid expires_in: must be between 1 and 48 hours")
+		}
+		t := now.Add(lifetime)
+		expiresAt = &t
+		expiresAtCol = t
+		ttl = int(lifetime.Seconds())
+	}
+
+	var poll *Poll
+	if req.Poll != nil {
+		if err := req.Poll.Validate(now); err != nil {
+			return nil, fmt.Errorf("invalid poll: %w", err)
+		}
+		if expiresAt != nil && req.Poll.ClosesAt.After(*expiresAt) {
+			return nil, fmt.Errorf("invalid poll: closes_at must not be after the post expires")
+		}
+		poll = newPoll(req.Poll)
+	}
 
 	batch := r.session.NewBatch(gocql.LoggedBatch)
 	batch.WithContext(ctx)
 
-	// Insert into posts_by_geohash
-	batch.Query(`
-		INSERT INTO posts_by_geohash (geohash_prefix, created_at, post_id, user_id, content, media_urls, latitude, longitude, full_geohash, ip_address, user_agent)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, geohashPrefix, now, postID, userID, req.Content, req.MediaURLs, req.Latitude, req.Longitude, fullGeohash, req.IPAddress, req.UserAgent)
+	// Insert into posts_by_geohash (5-char) and posts_by_geocell (3, 4 and 6-char)
+	for _, part := range postGeoPartitions(latitude, longitude, locationPrecision) {
+		batch.Query(`
+			INSERT INTO `+part.table+` (geohash_prefix, created_at, post_id, user_id, content, media_urls, latitude, longitude, full_geohash, visibility, location_precision, expires_at, quoted_post_id, ip_address, user_agent)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			USING TTL ?
+		`, part.prefix, now, postID, userID, req.Content, req.MediaURLs, latitude, longitude, fullGeohash, visibility, locationPrecision, expiresAtCol, quotedPostID, req.IPAddress, req.UserAgent, ttl)
+	}
 
 	// Insert into posts_by_id
 	batch.Query(`
-		INSERT INTO posts_by_id (post_id, user_id, content, media_urls, latitude, longitude, geohash, ip_address, user_agent, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, postID, userID, req.Content, req.MediaURLs, req.Latitude, req.Longitude, fullGeohash, req.IPAddress, req.UserAgent, now)
+		INSERT INTO posts_by_id (post_id, user_id, content, media_urls, latitude, longitude, geohash, visibility, location_precision, expires_at, quoted_post_id, ip_address, user_agent, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		USING TTL ?
+	`, postID, userID, req.Content, req.MediaURLs, latitude, longitude, fullGeohash, visibility, locationPrecision, expiresAtCol, quotedPostID, req.IPAddress, req.UserAgent, now, ttl)
 
 	// Insert into posts_by_user
 	batch.Query(`
-		INSERT INTO posts_by_user (user_id, created_at, post_id, content, media_urls, latitude, longitude, ip_address, user_agent)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, userID, now, postID, req.Content, req.MediaURLs, req.Latitude, req.Longitude, req.IPAddress, req.UserAgent)
+		INSERT INTO posts_by_user (user_id, created_at, post_id, content, media_urls, latitude, longitude, visibility, location_precision, expires_at, quoted_post_id, ip_address, user_agent)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		USING TTL ?
+	`, userID, now, postID, req.Content, req.MediaURLs, latitude, longitude, visibility, locationPrecision, expiresAtCol, quotedPostID, req.IPAddress, req.UserAgent, ttl)
+
+	// Index ephemeral posts for the expiry sweeper, which cleans up what the TTL does not
+	if expiresAt != nil {
+		batch.Query(`
+			INSERT INTO posts_by_expiry (expiry_bucket, expires_at, post_id, user_id, created_at, latitude, longitude, location_precision)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			USING TTL ?
+		`, ExpiryBucket(*expiresAt), *expiresAt, postID, userID, now, latitude, longitude, locationPrecision, ttl+int(expirySweepLookback.Seconds()))
+	}
+
+	if req.Poll != nil {
+		addPollToBatch(batch, postID, userID, req.Poll, now, ttl)
+	}
+
+	// Hashtag pages are open to everyone, so only posts open to everyone are
+	// listed
+	listed, err := r.isOpenToEveryone(ctx, req.UserID, visibility)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check author privacy: %w", err)
+	}
+	if listed {
+		addHashtagsToBatch(batch, PostHashtags(req.Content), postID, userID, now, latitude, longitude, locationPrecision, ttl)
+	}
 
 	err = r.session.ExecuteBatch(batch)
 	if err != nil {
@@ -57,94 +170,204 @@ func (r *PostRepository) CreatePost(ctx context.Context, req *CreatePostRequest)
 	}
 
 	return &Post{
-		ID:        postID.String(),
-		UserID:    userID.String(),
-		Content:   req.Content,
-		MediaURLs: req.MediaURLs,
-		Latitude:  req.Latitude,
-		Longitude: req.Longitude,
-		Geohash:   fullGeohash,
-		CreatedAt: now,
+		ID:                postID.String(),
+		UserID:            userID.String(),
+		Content:           req.Content,
+		MediaURLs:         req.MediaURLs,
+		Latitude:          latitude,
+		Longitude:         longitude,
+		Geohash:           fullGeohash,
+		Visibility:        visibility,
+		LocationPrecision: locationPrecision,
+		QuotedPostID:      req.QuotedPostID,
+		Poll:              poll,
+		CreatedAt:         now,
+		ExpiresAt:         expiresAt,
 	}, nil
 }
 
-// GetNearbyPosts retrieves posts sorted by proximity to a given location
-// cursorTime is used for pagination - only returns posts created before this time
-func (r *PostRepository) GetNearbyPosts(ctx context.Context, latitude, longitude, radiusKM float64, limit int, cursorTime time.Time) ([]Post, error) {
+// maxScanPerCell bounds how many rows a single proximity partition may scan
+// while looking for posts inside the requested radius.
+const maxScanPerCell = 2000
+
+// maxNearbyLimit is the most posts GetNearbyPosts returns. It is above the
+// feed's page size so the ranked feed modes can read their whole pool
+// (rankedFeedPool) in one call.
+const maxNearbyLimit = 250
+
+// GetNearbyPosts retrieves posts within radiusKM of a location, newest first.
+// The partitions and their precision come from GeohashCover, so both small and
+// large radii are served from an appropriately sized set of cells.
+// after is used for pagination - only returns posts that sort after this keyset.
+// A cell stops after maxScanPerCell rows, so fewer than limit posts may come
+// back while older posts remain: resume is where the next read continues, and
+// is zero once no cell has older posts.
+func (r *PostRepository) GetNearbyPosts(ctx context.Context, latitude, longitude, radiusKM float64, limit int, after Keyset) (posts []Post, resume Keyset, err error) {
 	// Default values
 	if radiusKM <= 0 {
 		radiusKM = 10 // Default 10km radius
 	}
-	if limit <= 0 || limit > 100 {
-		limit = 50 // Default 50 posts, max 100
+	if limit <= 0 || limit > maxNearbyLimit {
+		limit = 50 // Default 50 posts
 	}
 
-	// Get all 9 geohash cells to query
-	neighbors := GetNeighbors(latitude, longitude)
+	precision, cells := GeohashCover(latitude, longitude, radiusKM)
+	table := geoTable(precision)
 
-	// Collect posts from all cells
-	var allPosts []Post
+	type cellResult struct {
+		posts []Post
+		cut   Keyset
+		err   error
+	}
+
+	sem := make(chan struct{}, 8)
+	results := make(chan cellResult, len(cells))
 
-	for _, geohashPrefix := range neighbors {
-		var iter *gocql.Iter
-
-		if cursorTime.IsZero() {
-			// No cursor - get newest posts
-			iter = r.session.Query(`
-				SELECT post_id, user_id, content, media_urls, latitude, longitude, full_geohash, created_at
-				FROM posts_by_geohash
-				WHERE geohash_prefix = ?
-				ORDER BY created_at DESC
-				LIMIT ?
-			`, geohashPrefix, limit*2).WithContext(ctx).Iter()
-		} else {
-			// With cursor - get posts older than cursor
-			iter = r.session.Query(`
-				SELECT post_id, user_id, content, media_urls, latitude, longitude, full_geohash, created_at
-				FROM posts_by_geohash
-				WHERE geohash_prefix = ? AND created_at < ?
-				ORDER BY created_at DESC
-				LIMIT ?
-			`, geohashPrefix, cursorTime, limit*2).WithContext(ctx).Iter()
+	for _, cell := range cells {
+		go func(prefix string) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			posts, cut, err := r.scanGeoPartition(ctx, table, prefix, latitude, longitude, radiusKM, limit, after)
+			results <- cellResult{posts, cut, err}
+		}(cell)
+	}
+
+	// Each cell contributes its newest `limit` in-radius posts, which is enough
+	// to assemble the global newest `limit`. A capped cell has only been read
+	// down to its cut; the newest cut bounds what is known to be complete.
+	var allPosts []Post
+	var cut Keyset
+	for range cells {
+		res := <-results
+		if res.err != nil {
+			return nil, Keyset{}, fmt.Errorf("error iterating posts: %w", res.err)
 		}
+		allPosts = append(allPosts, res.posts...)
+		if !res.cut.IsZero() && (cut.IsZero() || KeysetBefore(res.cut.CreatedAt, res.cut.ID, cut.CreatedAt, cut.ID)) {
+			cut = res.cut
+		}
+	}
 
-		var post Post
-		var postID, userID gocql.UUID
-		var mediaURLs []string
+	// Sort by (created_at DESC, post_id ASC) for stable pagination
+	SortPostsByKeyset(allPosts)
 
-		for iter.Scan(&postID, &userID, &post.Content, &mediaURLs, &post.Latitude, &post.Longitude, &post.Geohash, &post.CreatedAt) {
-			post.ID = postID.String()
-			post.UserID = userID.String()
-			post.MediaURLs = mediaURLs
+	// Posts past the cut could skip over unread rows of the capped cell, so
+	// they are left for the next read
+	if !cut.IsZero() {
+		n := sort.Search(len(allPosts), func(i int) bool {
+			return KeysetBefore(cut.CreatedAt, cut.ID, allPosts[i].CreatedAt, allPosts[i].ID)
+		})
+		allPosts = allPosts[:n]
+	}
+
+	// Apply limit
+	if len(allPosts) > limit {
+		allPosts = allPosts[:limit]
+	}
+
+	switch {
+	case len(allPosts) == limit:
+		last := allPosts[limit-1]
+		resume = Keyset{CreatedAt: last.CreatedAt, ID: last.ID}
+	case !cut.IsZero():
+		resume = cut
+	}
+	return allPosts, resume, nil
+}
+
+// scanGeoPartition streams one proximity partition newest-first and returns up
+// to limit posts that fall inside the radius, plus any posts sharing the last
+// one's timestamp. If the scan stops at maxScanPerCell rows first, cut is the
+// last row scanned; otherwise it is zero.
+func (r *PostRepository) scanGeoPartition(ctx context.Context, table, prefix string, latitude, longitude, radiusKM float64, limit int, after Keyset) (posts []Post, cut Keyset, err error) {
+	var iter *gocql.Iter
+
+	if after.IsZero() {
+		// No cursor - get newest posts
+		iter = r.session.Query(`
+			SELECT post_id, user_id, content, media_urls, latitude, longitude, full_geohash, visibility, location_precision, created_at, edited_at, expires_at, quoted_post_id
+			FROM `+table+`
+			WHERE geohash_prefix = ?
+		`, prefix).WithContext(ctx).PageSize(limit * 2).Iter()
+	} else {
+		// With cursor - posts at or before the cursor time; ties are resolved by post_id below
+		iter = r.session.Query(`
+			SELECT post_id, user_id, content, media_urls, latitude, longitude, full_geohash, visibility, location_precision, created_at, edited_at, expires_at, quoted_post_id
+			FROM `+table+`
+			WHERE geohash_prefix = ? AND created_at <= ?
+		`, prefix, after.CreatedAt).WithContext(ctx).PageSize(limit * 2).Iter()
+	}
+
+	var post Post
+	var postID, userID, quotedPostID gocql.UUID
+	var mediaURLs []string
+	var editedAt, expiresAt time.Time
+	var last Keyset // The last row scanned
+	scanned := 0
+
+	for iter.Scan(&postID, &userID, &post.Content, &mediaURLs, &post.Latitude, &post.Longitude, &post.Geohash, &post.Visibility, &post.LocationPrecision, &post.CreatedAt, &editedAt, &expiresAt, &quotedPostID) {
+		if len(posts) > 0 && keysetScanDone(len(posts), limit, posts[len(posts)-1].CreatedAt, post.CreatedAt) {
+			break
+		}
+		// Stop between timestamps, so rows sharing one are read together
+		if scanned >= maxScanPerCell && !post.CreatedAt.Equal(last.CreatedAt) {
+			cut = last
+			break
+		}
+		scanned++
+
+		id := postID.String()
+		if after.Admits(post.CreatedAt, id) {
+			last = Keyset{CreatedAt: post.CreatedAt, ID: id}
 
 			// Calculate distance and filter
 			distance := HaversineDistance(latitude, longitude, post.Latitude, post.Longitude)
 			if distance <= radiusKM {
+				post.ID = id
+				post.UserID = userID.String()
+				post.MediaURLs = mediaURLs
 				post.Distance = distance
-				allPosts = append(allPosts, post)
+				post.Visibility = postVisibility(post.Visibility)
+				post.LocationPrecision = postLocationPrecision(post.LocationPrecision)
+				post.EditedAt = optionalTime(editedAt)
+				post.ExpiresAt = optionalTime(expiresAt)
+				post.QuotedPostID = optionalUUID(quotedPostID)
+				posts = append(posts, post)
 			}
-
-			// Reset for next iteration
-			post = Post{}
-			mediaURLs = nil
 		}
 
-		if err := iter.Close(); err != nil {
-			return nil, fmt.Errorf("error iterating posts: %w", err)
-		}
+		// Reset for next iteration
+		post = Post{}
+		mediaURLs = nil
+		editedAt = time.Time{}
+		expiresAt = time.Time{}
+		quotedPostID = gocql.UUID{}
 	}
 
-	// Sort by created_at (newest first) for consistent pagination
-	sort.Slice(allPosts, func(i, j int) bool {
-		return allPosts[i].CreatedAt.After(allPosts[j].CreatedAt)
+	if err := iter.Close(); err != nil {
+		return nil, Keyset{}, err
+	}
+
+	return posts, cut, nil
+}
+
+// SortPostsByKeyset orders posts by (created_at DESC, post_id ASC), the order
+// pagination cursors are issued in. Reposts sort by when they were reposted.
+func SortPostsByKeyset(posts []Post) {
+	sort.Slice(posts, func(i, j int) bool {
+		a, b := posts[i].Keyset(), posts[j].Keyset()
+		return KeysetBefore(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
 	})
+}
 
-	// Apply limit
-	if len(allPosts) > limit {
-		allPosts = allPosts[:limit]
+// Keyset returns the post's position in a listing: when it was reposted for a
+// repost in a home timeline, otherwise when it was created
+func (p *Post) Keyset() Keyset {
+	if p.RepostedAt != nil {
+		return Keyset{CreatedAt: *p.RepostedAt, ID: p.ID}
 	}
-
-	return allPosts, nil
+	return Keyset{CreatedAt: p.CreatedAt, ID: p.ID}
 }
 
 // GetPostByID retrieves a post by its ID
@@ -155,14 +378,15 @@ func (r *PostRepository) GetPostByID(ctx context.Context, id string) (*Post, err
 	}
 
 	var post Post
-	var userID gocql.UUID
+	var userID, quotedPostID gocql.UUID
 	var mediaURLs []string
+	var editedAt, expiresAt time.Time
 
 	err = r.session.Query(`
-		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, created_at
+		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, visibility, location_precision, created_at, edited_at, expires_at, quoted_post_id
 		FROM posts_by_id
 		WHERE post_id = ?
-	`, postID).WithContext(ctx).Scan(&postID, &userID, &post.Content, &mediaURLs, &post.Latitude, &post.Longitude, &post.Geohash, &post.CreatedAt)
+	`, postID).WithContext(ctx).Scan(&postID, &userID, &post.Content, &mediaURLs, &post.Latitude, &post.Longitude, &post.Geohash, &post.Visibility, &post.LocationPrecision, &post.CreatedAt, &editedAt, &expiresAt, &quotedPostID)
 
 	if err != nil {
 		if err == gocql.ErrNotFound {
@@ -174,63 +398,138 @@ func (r *PostRepository) GetPostByID(ctx context.Context, id string) (*Post, err
 	post.ID = postID.String()
 	post.UserID = userID.String()
 	post.MediaURLs = mediaURLs
+	post.Visibility = postVisibility(post.Visibility)
+	post.LocationPrecision = postLocationPrecision(post.LocationPrecision)
+	post.EditedAt = optionalTime(editedAt)
+	post.ExpiresAt = optionalTime(expiresAt)
+	post.QuotedPostID = optionalUUID(quotedPostID)
 
 	return &post, nil
 }
 
-// GetPostsByUser retrieves all posts by a user with cursor-based pagination
-func (r *PostRepository) GetPostsByUser(ctx context.Context, userIDStr string, limit int, cursorTime time.Time) ([]Post, error) {
+// GetPostsByIDs fetches posts from posts_by_id concurrently (max 10 in flight).
+// The input order is preserved; posts that no longer exist are skipped.
+func (r *PostRepository) GetPostsByIDs(ctx context.Context, ids []string) ([]Post, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	type postResult struct {
+		index int
+		post  *Post
+		err   error
+	}
+
+	sem := make(chan struct{}, 10)
+	results := make(chan postResult, len(ids))
+
+	for i, id := range ids {
+		go func(idx int, postID string) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			post, err := r.GetPostByID(ctx, postID)
+			results <- postResult{idx, post, err}
+		}(i, id)
+	}
+
+	ordered := make([]*Post, len(ids))
+	var firstErr error
+	for range ids {
+		res := <-results
+		if res.err != nil {
+			if !strings.Contains(res.err.Error(), "not found") && firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		ordered[res.index] = res.post
+	}
+
+	posts := make([]Post, 0, len(ids))
+	for _, p := range ordered {
+		if p != nil {
+			posts = append(posts, *p)
+		}
+	}
+
+	if len(posts) == 0 && firstErr != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", firstErr)
+	}
+
+	return posts, nil
+}
+
+// GetPostsByUser retrieves all posts by a user with cursor-based pagination.
+// Results are ordered by (created_at DESC, post_id ASC) and start after the given keyset.
+func (r *PostRepository) GetPostsByUser(ctx context.Context, userIDStr string, limit int, after Keyset) ([]Post, error) {
 	userID, err := gocql.ParseUUID(userIDStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user_id: %w", err)
 	}
 
-	if limit <= 0 || limit > 100 {
-		limit = 50
+	if limit <= 0 || limit > 200 {
+		limit = 50 // Default 50 posts, max 200
 	}
 
 	var iter *gocql.Iter
 
-	if cursorTime.IsZero() {
+	if after.IsZero() {
 		// No cursor - get newest posts
 		iter = r.session.Query(`
-			SELECT post_id, content, media_urls, latitude, longitude, created_at
+			SELECT post_id, content, media_urls, latitude, longitude, visibility, location_precision, created_at, edited_at, expires_at, quoted_post_id
 			FROM posts_by_user
 			WHERE user_id = ?
-			ORDER BY created_at DESC
-			LIMIT ?
-		`, userID, limit).WithContext(ctx).Iter()
+		`, userID).WithContext(ctx).PageSize(limit + 1).Iter()
 	} else {
-		// With cursor - get posts older than cursor
+		// With cursor - posts at or before the cursor time; ties are resolved by post_id below
 		iter = r.session.Query(`
-			SELECT post_id, content, media_urls, latitude, longitude, created_at
+			SELECT post_id, content, media_urls, latitude, longitude, visibility, location_precision, created_at, edited_at, expires_at, quoted_post_id
 			FROM posts_by_user
-			WHERE user_id = ? AND created_at < ?
-			ORDER BY created_at DESC
-			LIMIT ?
-		`, userID, cursorTime, limit).WithContext(ctx).Iter()
+			WHERE user_id = ? AND created_at <= ?
+		`, userID, after.CreatedAt).WithContext(ctx).PageSize(limit + 1).Iter()
 	}
 
 	var posts []Post
 	var post Post
-	var postID gocql.UUID
+	var postID, quotedPostID gocql.UUID
 	var mediaURLs []string
+	var editedAt, expiresAt time.Time
 
-	for iter.Scan(&postID, &post.Content, &mediaURLs, &post.Latitude, &post.Longitude, &post.CreatedAt) {
-		post.ID = postID.String()
-		post.UserID = userIDStr
-		post.MediaURLs = mediaURLs
-		posts = append(posts, post)
+	for iter.Scan(&postID, &post.Content, &mediaURLs, &post.Latitude, &post.Longitude, &post.Visibility, &post.LocationPrecision, &post.CreatedAt, &editedAt, &expiresAt, &quotedPostID) {
+		if len(posts) > 0 && keysetScanDone(len(posts), limit, posts[len(posts)-1].CreatedAt, post.CreatedAt) {
+			break
+		}
+
+		if after.Admits(post.CreatedAt, postID.String()) {
+			post.ID = postID.String()
+			post.UserID = userIDStr
+			post.MediaURLs = mediaURLs
+			post.Visibility = postVisibility(post.Visibility)
+			post.LocationPrecision = postLocationPrecision(post.LocationPrecision)
+			post.EditedAt = optionalTime(editedAt)
+			post.ExpiresAt = optionalTime(expiresAt)
+			post.QuotedPostID = optionalUUID(quotedPostID)
+			posts = append(posts, post)
+		}
 
 		// Reset for next iteration
 		post = Post{}
 		mediaURLs = nil
+		editedAt = time.Time{}
+		expiresAt = time.Time{}
+		quotedPostID = gocql.UUID{}
 	}
 
 	if err := iter.Close(); err != nil {
 		return nil, fmt.Errorf("error iterating posts: %w", err)
 	}
 
+	SortPostsByKeyset(posts)
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+
 	return posts, nil
 }
 
@@ -249,7 +548,7 @@ func (r *PostRepository) SearchPosts(ctx context.Context, query string, limit in
 	searchPattern := "%" + normalizedQuery + "%"
 
 	iter := r.session.Query(`
-		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, created_at
+		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, visibility, location_precision, created_at, quoted_post_id
 		FROM posts_by_id
 		WHERE content LIKE ?
 		LIMIT ?
@@ -257,18 +556,22 @@ func (r *PostRepository) SearchPosts(ctx context.Context, query string, limit in
 
 	var posts []Post
 	var post Post
-	var postID, userID gocql.UUID
+	var postID, userID, quotedPostID gocql.UUID
 	var mediaURLs []string
 
 	for iter.Scan(&postID, &userID, &post.Content, &mediaURLs,
-		&post.Latitude, &post.Longitude, &post.Geohash, &post.CreatedAt) {
+		&post.Latitude, &post.Longitude, &post.Geohash, &post.Visibility, &post.LocationPrecision, &post.CreatedAt, &quotedPostID) {
 		post.ID = postID.String()
 		post.UserID = userID.String()
 		post.MediaURLs = mediaURLs
+		post.Visibility = postVisibility(post.Visibility)
+		post.LocationPrecision = postLocationPrecision(post.LocationPrecision)
+		post.QuotedPostID = optionalUUID(quotedPostID)
 		posts = append(posts, post)
 
 		post = Post{}
 		mediaURLs = nil
+		quotedPostID = gocql.UUID{}
 		if len(posts) >= limit {
 			break
 		}
@@ -298,27 +601,31 @@ func (r *PostRepository) searchPostsByScan(ctx context.Context, query string, li
 	}
 
 	iter := r.session.Query(`
-		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, created_at
+		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, visibility, location_precision, created_at, quoted_post_id
 		FROM posts_by_id
 		LIMIT ?
 	`, scanLimit).WithContext(ctx).Iter()
 
 	var posts []Post
 	var post Post
-	var postID, userID gocql.UUID
+	var postID, userID, quotedPostID gocql.UUID
 	var mediaURLs []string
 
 	for iter.Scan(&postID, &userID, &post.Content, &mediaURLs,
-		&post.Latitude, &post.Longitude, &post.Geohash, &post.CreatedAt) {
+		&post.Latitude, &post.Longitude, &post.Geohash, &post.Visibility, &post.LocationPrecision, &post.CreatedAt, &quotedPostID) {
 		if strings.Contains(strings.ToLower(post.Content), query) {
 			post.ID = postID.String()
 			post.UserID = userID.String()
 			post.MediaURLs = mediaURLs
+			post.Visibility = postVisibility(post.Visibility)
+			post.LocationPrecision = postLocationPrecision(post.LocationPrecision)
+			post.QuotedPostID = optionalUUID(quotedPostID)
 			posts = append(posts, post)
 		}
 
 		post = Post{}
 		mediaURLs = nil
+		quotedPostID = gocql.UUID{}
 		if len(posts) >= limit {
 			break
 		}
@@ -331,6 +638,161 @@ func (r *PostRepository) searchPostsByScan(ctx context.Context, query string, li
 	return posts, nil
 }
 
+// UpdatePost replaces a post's content (and media, when the request sets it) in
+// every denormalized table and records the previous version in post_revisions.
+// Only the author may edit, and only within editWindow of the post's creation;
+// a zero editWindow disables the time limit.
+func (r *PostRepository) UpdatePost(ctx context.Context, postIDStr, requestingUserID string, req *UpdatePostRequest, editWindow time.Duration) (*Post, error) {
+	post, err := r.GetPostByID(ctx, postIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.UserID != requestingUserID {
+		return nil, fmt.Errorf("forbidden: you can only edit your own posts")
+	}
+	if editWindow > 0 && time.Since(post.CreatedAt) > editWindow {
+		return nil, fmt.Errorf("edit window has expired")
+	}
+
+	postID, _ := gocql.ParseUUID(post.ID)
+	userID, _ := gocql.ParseUUID(post.UserID)
+
+	mediaURLs := post.MediaURLs
+	if req.ReplacesMedia() {
+		mediaURLs = req.MediaURLs
+	}
+	now := time.Now()
+
+	// Edits of an ephemeral post expire with it, or the updated cells would
+	// keep its rows alive past the TTL
+	ttl := 0
+	if post.ExpiresAt != nil {
+		remaining := post.ExpiresAt.Sub(now)
+		if remaining <= 0 {
+			return nil, fmt.Errorf("post not found")
+		}
+		ttl = int(math.Ceil(remaining.Seconds()))
+	}
+
+	batch := r.session.NewBatch(gocql.LoggedBatch)
+	batch.WithContext(ctx)
+
+	// Keep the version being replaced
+	batch.Query(`
+		INSERT INTO post_revisions (post_id, revision_id, editor_id, content, media_urls)
+		VALUES (?, ?, ?, ?, ?)
+		USING TTL ?
+	`, postID, gocql.UUIDFromTime(now), userID, post.Content, post.MediaURLs, ttl)
+
+	// Update posts_by_id
+	batch.Query(`
+		UPDATE posts_by_id USING TTL ? SET content = ?, media_urls = ?, edited_at = ? WHERE post_id = ?
+	`, ttl, req.Content, mediaURLs, now, postID)
+
+	// Update posts_by_geohash and posts_by_geocell
+	for _, part := range postGeoPartitions(post.Latitude, post.Longitude, post.LocationPrecision) {
+		batch.Query(`
+			UPDATE `+part.table+` USING TTL ? SET content = ?, media_urls = ?, edited_at = ?
+			WHERE geohash_prefix = ? AND created_at = ? AND post_id = ?
+		`, ttl, req.Content, mediaURLs, now, part.prefix, post.CreatedAt, postID)
+	}
+
+	// Update posts_by_user
+	batch.Query(`
+		UPDATE posts_by_user USING TTL ? SET content = ?, media_urls = ?, edited_at = ?
+		WHERE user_id = ? AND created_at = ? AND post_id = ?
+	`, ttl, req.Content, mediaURLs, now, userID, post.CreatedAt, postID)
+
+	// Move the post to the hashtag pages of its new content
+	listed, err := r.IsOpenToEveryone(ctx, post)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check author privacy: %w", err)
+	}
+	if listed {
+		newTags := PostHashtags(req.Content)
+		kept := make(map[string]bool, len(newTags))
+		for _, tag := range newTags {
+			kept[tag] = true
+		}
+		var removed []string
+		for _, tag := range PostHashtags(post.Content) {
+			if !kept[tag] {
+				removed = append(removed, tag)
+			}
+		}
+		removeHashtagsFromBatch(batch, removed, postID, post.CreatedAt)
+		addHashtagsToBatch(batch, newTags, postID, userID, post.CreatedAt, post.Latitude, post.Longitude, post.LocationPrecision, ttl)
+	}
+
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+
+	post.Content = req.Content
+	post.MediaURLs = mediaURLs
+	post.EditedAt = &now
+	return post, nil
+}
+
+// GetPostRevisions returns a post's previous versions, newest first
+func (r *PostRepository) GetPostRevisions(ctx context.Context, postIDStr string, limit int) ([]PostRevision, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	iter := r.session.Query(`
+		SELECT revision_id, editor_id, content, media_urls
+		FROM post_revisions
+		WHERE post_id = ?
+		LIMIT ?
+	`, postID, limit).WithContext(ctx).Iter()
+
+	var revisions []PostRevision
+	var revisionID, editorID gocql.UUID
+	var content string
+	var mediaURLs []string
+
+	for iter.Scan(&revisionID, &editorID, &content, &mediaURLs) {
+		revisions = append(revisions, PostRevision{
+			ID:        revisionID.String(),
+			PostID:    postIDStr,
+			EditorID:  editorID.String(),
+			Content:   content,
+			MediaURLs: mediaURLs,
+			RevisedAt: revisionID.Time(),
+		})
+		mediaURLs = nil
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get post revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// optionalTime maps an unset timestamp column (edited_at, expires_at) to nil
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// optionalUUID maps an unset UUID column (quoted_post_id) to ""
+func optionalUUID(id gocql.UUID) string {
+	if id == (gocql.UUID{}) {
+		return ""
+	}
+	return id.String()
+}
+
 // DeletePost removes a post from all denormalized tables.
 // Verifies ownership: only the post author can delete their post.
 func (r *PostRepository) DeletePost(ctx context.Context, postIDStr, requestingUserID string) error {
@@ -340,15 +802,13 @@ func (r *PostRepository) DeletePost(ctx context.Context, postIDStr, requestingUs
 	}
 
 	// Fetch the post to get the data needed for multi-table deletion
-	var userID gocql.UUID
-	var latitude, longitude float64
-	var createdAt time.Time
+	loc := postLocation{postID: postID}
 	var geohash string
 
 	err = r.session.Query(`
-		SELECT user_id, latitude, longitude, geohash, created_at
+		SELECT user_id, content, latitude, longitude, geohash, location_precision, created_at, expires_at
 		FROM posts_by_id WHERE post_id = ?
-	`, postID).WithContext(ctx).Scan(&userID, &latitude, &longitude, &geohash, &createdAt)
+	`, postID).WithContext(ctx).Scan(&loc.userID, &loc.content, &loc.latitude, &loc.longitude, &geohash, &loc.locationPrecision, &loc.createdAt, &loc.expiresAt)
 
 	if err != nil {
 		if err == gocql.ErrNotFound {
@@ -358,12 +818,37 @@ func (r *PostRepository) DeletePost(ctx context.Context, postIDStr, requestingUs
 	}
 
 	// Verify ownership
-	if userID.String() != requestingUserID {
+	if loc.userID.String() != requestingUserID {
 		return fmt.Errorf("forbidden: you can only delete your own posts")
 	}
 
-	// Calculate geohash prefix for posts_by_geohash deletion
-	geohashPrefix := GetGeohashPrefix(latitude, longitude)
+	return r.removePost(ctx, loc)
+}
+
+// postLocation holds the keys of every row written for a post
+type postLocation struct {
+	postID    gocql.UUID
+	userID    gocql.UUID
+	latitude  float64
+	longitude float64
+	createdAt time.Time
+	expiresAt time.Time // Zero for posts that do not expire
+	content   string    // Empty for expired posts, whose hashtag entries expire with them
+
+	locationPrecision string
+}
+
+// removePost deletes a post's rows along with its likes, comments, reposts,
+// edit history and counters. It is used both for deletion by the author and
+// by the expiry sweeper, and is safe to repeat.
+func (r *PostRepository) removePost(ctx context.Context, loc postLocation) error {
+	postID := loc.postID
+
+	// Clean up the likes and comments first: they are found through rows the
+	// batch below deletes, so a failure here leaves the post to be removed again
+	if err := r.removePostEngagement(ctx, postID); err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
 
 	// Batch delete from all denormalized tables
 	// Note: Counter tables (like_counts, comment_counts) cannot be part of a logged batch
@@ -372,31 +857,126 @@ func (r *PostRepository) DeletePost(ctx context.Context, postIDStr, requestingUs
 	// Delete from posts_by_id
 	batch.Query(`DELETE FROM posts_by_id WHERE post_id = ?`, postID)
 
-	// Delete from posts_by_geohash
-	batch.Query(`DELETE FROM posts_by_geohash WHERE geohash_prefix = ? AND created_at = ? AND post_id = ?`,
-		geohashPrefix, createdAt, postID)
+	// Delete from posts_by_geohash and posts_by_geocell
+	for _, part := range postGeoPartitions(loc.latitude, loc.longitude, loc.locationPrecision) {
+		batch.Query(`DELETE FROM `+part.table+` WHERE geohash_prefix = ? AND created_at = ? AND post_id = ?`,
+			part.prefix, loc.createdAt, postID)
+	}
 
 	// Delete from posts_by_user
 	batch.Query(`DELETE FROM posts_by_user WHERE user_id = ? AND created_at = ? AND post_id = ?`,
-		userID, createdAt, postID)
-
-	// Delete associated likes
-	batch.Query(`DELETE FROM likes WHERE target_type = ? AND target_id = ?`, TargetTypePost, postID)
-	batch.Query(`DELETE FROM like_state WHERE target_type = ? AND target_id = ?`, TargetTypePost, postID)
+		loc.userID, loc.createdAt, postID)
 
 	// Delete associated comments
 	batch.Query(`DELETE FROM comments WHERE post_id = ?`, postID)
+	batch.Query(`DELETE FROM comment_pins WHERE post_id = ?`, postID)
+	batch.Query(`DELETE FROM post_comment_settings WHERE post_id = ?`, postID)
 
-	err = r.session.ExecuteBatch(batch)
+	// Delete edit history
+	batch.Query(`DELETE FROM post_revisions WHERE post_id = ?`, postID)
+
+	// Delete plain reposts (timeline entries pointing at the post are skipped on read)
+	batch.Query(`DELETE FROM reposts WHERE post_id = ?`, postID)
+
+	// Delete the poll and its votes (the poll closer skips polls that are gone)
+	batch.Query(`DELETE FROM polls WHERE post_id = ?`, postID)
+	batch.Query(`DELETE FROM poll_votes WHERE post_id = ?`, postID)
+
+	// Delete its hashtag page entries
+	removeHashtagsFromBatch(batch, PostHashtags(loc.content), postID, loc.createdAt)
+
+	// Delete its mentions. Mentions tab entries of a missing post are skipped
+	// on read; those of an ephemeral post expire with it.
+	batch.Query(`DELETE FROM mentions WHERE target_type = ? AND target_id = ?`, TargetTypePost, postID)
+
+	// Drop the expiry index entry of an ephemeral post
+	if !loc.expiresAt.IsZero() {
+		batch.Query(`DELETE FROM posts_by_expiry WHERE expiry_bucket = ? AND expires_at = ? AND post_id = ?`,
+			ExpiryBucket(loc.expiresAt), loc.expiresAt, postID)
+	}
+
+	err := r.session.ExecuteBatch(batch)
 	if err != nil {
 		return fmt.Errorf("failed to delete post: %w", err)
 	}
 
 	// Delete counter tables separately (cannot be in a logged batch)
-	_ = r.session.Query(`DELETE FROM like_counts WHERE target_type = ? AND target_id = ?`,
-		TargetTypePost, postID).WithContext(ctx).Exec()
 	_ = r.session.Query(`DELETE FROM comment_counts WHERE post_id = ?`,
 		postID).WithContext(ctx).Exec()
+	_ = r.session.Query(`DELETE FROM repost_counts WHERE post_id = ?`,
+		postID).WithContext(ctx).Exec()
+
+	return nil
+}
 
+// removePostEngagement deletes the likes and reactions on a post and its
+// comments, and the comments' edit history and mentions
+func (r *PostRepository) removePostEngagement(ctx context.Context, postID gocql.UUID) error {
+	iter := r.session.Query(`SELECT comment_id FROM comments WHERE post_id = ?`, postID).WithContext(ctx).Iter()
+	var commentIDs []gocql.UUID
+	var commentID gocql.UUID
+	for iter.Scan(&commentID) {
+		commentIDs = append(commentIDs, commentID)
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to read comments: %w", err)
+	}
+
+	if err := r.removeLikes(ctx, TargetTypePost, postID); err != nil {
+		return err
+	}
+	for _, id := range commentIDs {
+		if err := r.removeLikes(ctx, TargetTypeComment, id); err != nil {
+			return err
+		}
+		batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+		batch.Query(`DELETE FROM comment_revisions WHERE comment_id = ?`, id)
+		batch.Query(`DELETE FROM mentions WHERE target_type = ? AND target_id = ?`, TargetTypeComment, id)
+		if err := r.session.ExecuteBatch(batch); err != nil {
+			return fmt.Errorf("failed to delete comment history: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeLikes deletes the likes and reactions on a post or comment: each
+// liker's likes_by_user row, the like tables and the counters
+func (r *PostRepository) removeLikes(ctx context.Context, targetType string, targetID gocql.UUID) error {
+	// likes_by_user rows are keyed by when each like was made, which only
+	// like_state records
+	iter := r.session.Query(`
+		SELECT user_id, created_at FROM like_state
+		WHERE target_type = ? AND target_id = ?
+	`, targetType, targetID).WithContext(ctx).Iter()
+	var userID gocql.UUID
+	var likedAt time.Time
+	for iter.Scan(&userID, &likedAt) {
+		if err := r.session.Query(`
+			DELETE FROM likes_by_user WHERE user_id = ? AND created_at = ? AND target_id = ?
+		`, userID, likedAt, targetID).WithContext(ctx).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to delete liked %s: %w", targetType, err)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to read likes: %w", err)
+	}
+
+	batch := r.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	batch.Query(`DELETE FROM likes WHERE target_type = ? AND target_id = ?`, targetType, targetID)
+	batch.Query(`DELETE FROM like_state WHERE target_type = ? AND target_id = ?`, targetType, targetID)
+	batch.Query(`DELETE FROM likes_by_target WHERE target_type = ? AND target_id = ?`, targetType, targetID)
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to delete likes: %w", err)
+	}
+
+	// Counters are best effort, like the post's other counters
+	_ = r.session.Query(`DELETE FROM like_counts WHERE target_type = ? AND target_id = ?`,
+		targetType, targetID).WithContext(ctx).Exec()
+	if r.likeCounter != nil {
+		if err := r.likeCounter.DeleteLikeCount(ctx, targetType, targetID.String()); err != nil {
+			fmt.Printf("WARNING: failed to delete Redis like counters: %v\n", err)
+		}
+	}
 	return nil
 }
@@ -10,7 +10,7 @@ import (
 )
 
 func TestPostRepository_Integration(t *testing.T) {
-	repo := NewPostRepository(testSession)
+	repo := NewPostRepository(testSession, nil)
 	ctx := context.Background()
 
 	// Helper to create a user for the posts
@@ -52,7 +52,7 @@ func TestPostRepository_Integration(t *testing.T) {
 		})
 
 		// 4. Query within 5KM
-		posts, err := repo.GetNearbyPosts(ctx, centerLat, centerLng, 5.0, 10, time.Time{})
+		posts, _, err := repo.GetNearbyPosts(ctx, centerLat, centerLng, 5.0, 10, Keyset{})
 		require.NoError(t, err)
 
 		// Assertions
@@ -74,8 +74,191 @@ func TestPostRepository_Integration(t *testing.T) {
 	})
 
 	t.Run("Get User Posts", func(t *testing.T) {
-		posts, err := repo.GetPostsByUser(ctx, user.ID, 10, time.Time{})
+		posts, err := repo.GetPostsByUser(ctx, user.ID, 10, Keyset{})
 		require.NoError(t, err)
 		assert.NotEmpty(t, posts)
 	})
+
+	t.Run("Update Post", func(t *testing.T) {
+		post, err := repo.CreatePost(ctx, &CreatePostRequest{
+			UserID: user.ID, Content: "Before edit",
+			Latitude: -6.1754, Longitude: 106.8272,
+			MediaURLs: []string{"http://img.com/before.jpg"},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.UpdatePost(ctx, post.ID, GenerateUUID(), &UpdatePostRequest{Content: "Hijacked"}, time.Hour)
+		assert.ErrorContains(t, err, "forbidden")
+
+		updated, err := repo.UpdatePost(ctx, post.ID, user.ID, &UpdatePostRequest{Content: "After edit"}, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "After edit", updated.Content)
+		assert.Equal(t, []string{"http://img.com/before.jpg"}, updated.MediaURLs, "Media is kept when not sent")
+		require.NotNil(t, updated.EditedAt)
+
+		// Every denormalized table sees the edit
+		byID, err := repo.GetPostByID(ctx, post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "After edit", byID.Content)
+		assert.NotNil(t, byID.EditedAt)
+
+		nearby, _, err := repo.GetNearbyPosts(ctx, -6.1754, 106.8272, 1.0, 50, Keyset{})
+		require.NoError(t, err)
+		for _, p := range nearby {
+			if p.ID == post.ID {
+				assert.Equal(t, "After edit", p.Content)
+			}
+		}
+
+		revisions, err := repo.GetPostRevisions(ctx, post.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "Before edit", revisions[0].Content)
+	})
+
+	t.Run("Update Post After Edit Window", func(t *testing.T) {
+		post, err := repo.CreatePost(ctx, &CreatePostRequest{
+			UserID: user.ID, Content: "Too late",
+			Latitude: -6.1754, Longitude: 106.8272,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.UpdatePost(ctx, post.ID, user.ID, &UpdatePostRequest{Content: "Edited"}, time.Nanosecond)
+		assert.ErrorContains(t, err, "edit window")
+	})
+
+	t.Run("Post Visibility", func(t *testing.T) {
+		follower, err := userRepo.CreateUser(ctx, &CreateUserRequest{Username: "vis_follower", Email: "vis_follower@test.com"})
+		require.NoError(t, err)
+		friend, err := userRepo.CreateUser(ctx, &CreateUserRequest{Username: "vis_friend", Email: "vis_friend@test.com"})
+		require.NoError(t, err)
+		stranger, err := userRepo.CreateUser(ctx, &CreateUserRequest{Username: "vis_stranger", Email: "vis_stranger@test.com"})
+		require.NoError(t, err)
+
+		require.NoError(t, NewFollowRepository(testSession).Follow(ctx, follower.ID, user.ID))
+		require.NoError(t, NewCloseFriendRepository(testSession).AddCloseFriend(ctx, user.ID, friend.ID))
+
+		var posts []Post
+		for _, v := range []string{VisibilityPublic, VisibilityFollowers, VisibilityCloseFriends, VisibilityOnlyMe} {
+			post, err := repo.CreatePost(ctx, &CreatePostRequest{
+				UserID: user.ID, Content: "Audience " + v, Visibility: v,
+				Latitude: -6.1754, Longitude: 106.8272,
+			})
+			require.NoError(t, err)
+			stored, err := repo.GetPostByID(ctx, post.ID)
+			require.NoError(t, err)
+			assert.Equal(t, v, stored.Visibility)
+			posts = append(posts, *stored)
+		}
+
+		visibleTo := func(viewerID string) []string {
+			var out []string
+			for _, p := range repo.FilterVisiblePosts(ctx, posts, viewerID) {
+				out = append(out, p.Visibility)
+			}
+			return out
+		}
+
+		assert.Equal(t, []string{VisibilityPublic, VisibilityFollowers, VisibilityCloseFriends, VisibilityOnlyMe}, visibleTo(user.ID))
+		assert.Equal(t, []string{VisibilityPublic, VisibilityFollowers}, visibleTo(follower.ID))
+		assert.Equal(t, []string{VisibilityPublic, VisibilityCloseFriends}, visibleTo(friend.ID))
+		assert.Equal(t, []string{VisibilityPublic}, visibleTo(stranger.ID))
+		assert.Equal(t, []string{VisibilityPublic}, visibleTo(""))
+
+		_, err = repo.CreatePost(ctx, &CreatePostRequest{
+			UserID: user.ID, Content: "Bad audience", Visibility: "friends_of_friends",
+			Latitude: -6.1754, Longitude: 106.8272,
+		})
+		assert.ErrorContains(t, err, "invalid visibility")
+
+		// Public posts of a private account are for its followers
+		require.NoError(t, userRepo.SetPrivate(ctx, user.ID, true))
+		defer userRepo.SetPrivate(ctx, user.ID, false) //nolint:errcheck
+
+		assert.Equal(t, []string{VisibilityPublic, VisibilityFollowers}, visibleTo(follower.ID))
+		assert.Equal(t, []string{VisibilityCloseFriends}, visibleTo(friend.ID))
+		assert.Empty(t, visibleTo(stranger.ID))
+		assert.Empty(t, visibleTo(""))
+
+		open, err := repo.IsOpenToEveryone(ctx, &posts[0])
+		require.NoError(t, err)
+		assert.False(t, open)
+		assert.ErrorContains(t, repo.CheckCanShare(ctx, &posts[0], follower.ID), "forbidden")
+	})
+
+	t.Run("Ephemeral Post Expires", func(t *testing.T) {
+		post, err := repo.CreatePost(ctx, &CreatePostRequest{
+			UserID: user.ID, Content: "Here for an hour", ExpiresIn: 1,
+			Latitude: -6.1754, Longitude: 106.8272,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, post.ExpiresAt)
+		assert.WithinDuration(t, post.CreatedAt.Add(time.Hour), *post.ExpiresAt, time.Second)
+
+		stored, err := repo.GetPostByID(ctx, post.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.ExpiresAt)
+
+		var ttl int
+		require.NoError(t, testSession.Query(`SELECT TTL(content) FROM posts_by_id WHERE post_id = ?`, post.ID).Scan(&ttl))
+		assert.InDelta(t, 3600, ttl, 60)
+
+		likeRepo := NewLikeRepository(testSession, nil)
+		_, err = likeRepo.ToggleLike(ctx, TargetTypePost, post.ID, user.ID, true)
+		require.NoError(t, err)
+		likesPost := func() bool {
+			keys, err := likeRepo.GetLikedPostKeys(ctx, user.ID, 100, Keyset{})
+			require.NoError(t, err)
+			for _, k := range keys {
+				if k.ID == post.ID {
+					return true
+				}
+			}
+			return false
+		}
+		require.Eventually(t, likesPost, 5*time.Second, 50*time.Millisecond)
+
+		commentRepo := NewCommentRepository(testSession, nil)
+		comment, err := commentRepo.CreateComment(ctx, &CreateCommentRequest{PostID: post.ID, UserID: user.ID, Content: "Quick"})
+		require.NoError(t, err)
+		_, err = commentRepo.EditComment(ctx, comment.ID, user.ID, "Quick!")
+		require.NoError(t, err)
+		_, err = likeRepo.SetReaction(ctx, TargetTypeComment, comment.ID, user.ID, ReactionLove)
+		require.NoError(t, err)
+
+		// Nothing is due before the expiry time
+		swept, err := repo.SweepExpiredPosts(ctx, time.Now(), 0)
+		require.NoError(t, err)
+		for _, p := range swept {
+			assert.NotEqual(t, post.ID, p.PostID)
+		}
+
+		swept, err = repo.SweepExpiredPosts(ctx, stored.ExpiresAt.Add(time.Second), 0)
+		require.NoError(t, err)
+		var ids []string
+		for _, p := range swept {
+			ids = append(ids, p.PostID)
+		}
+		assert.Contains(t, ids, post.ID)
+
+		_, err = repo.GetPostByID(ctx, post.ID)
+		assert.ErrorContains(t, err, "not found")
+		var likes int
+		require.NoError(t, testSession.Query(`SELECT COUNT(*) FROM likes WHERE target_type = ? AND target_id = ?`, TargetTypePost, post.ID).Scan(&likes))
+		assert.Zero(t, likes)
+		assert.False(t, likesPost())
+
+		// The comment's likes and edit history go with the post
+		require.NoError(t, testSession.Query(`SELECT COUNT(*) FROM like_state WHERE target_type = ? AND target_id = ?`, TargetTypeComment, comment.ID).Scan(&likes))
+		assert.Zero(t, likes)
+		revisions, err := commentRepo.GetCommentRevisions(ctx, comment.ID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+
+		_, err = repo.CreatePost(ctx, &CreatePostRequest{
+			UserID: user.ID, Content: "Too long", ExpiresIn: 49,
+			Latitude: -6.1754, Longitude: 106.8272,
+		})
+		assert.ErrorContains(t, err, "invalid expires_in")
+	})
 }
@@ -0,0 +1,269 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// RepostResult represents the state of a user's plain repost after a change
+type RepostResult struct {
+	IsReposted  bool      `json:"is_reposted"`
+	RepostCount int64     `json:"repost_count"`
+	Changed     bool      `json:"changed"` // Whether the state actually changed
+	RepostedAt  time.Time `json:"-"`
+}
+
+// PostRepostInfo contains repost information for a post
+type PostRepostInfo struct {
+	RepostCount int64
+	IsReposted  bool
+}
+
+// Repost records userID's plain repost of a post. Reposting twice is a no-op.
+// Callers check the post may be shared first (CheckCanShare).
+func (r *PostRepository) Repost(ctx context.Context, postIDStr, userIDStr string) (*RepostResult, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	now := time.Now()
+	existing := make(map[string]interface{})
+	applied, err := r.session.Query(`
+		INSERT INTO reposts (post_id, user_id, created_at)
+		VALUES (?, ?, ?)
+		IF NOT EXISTS
+	`, postID, userID, now).WithContext(ctx).MapScanCAS(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to repost: %w", err)
+	}
+
+	result := &RepostResult{IsReposted: true, Changed: applied, RepostedAt: now}
+	if applied {
+		if err := r.session.Query(`
+			UPDATE repost_counts SET count = count + 1 WHERE post_id = ?
+		`, postID).WithContext(ctx).Exec(); err != nil {
+			slog.Warn("Failed to increment repost count", "error", err, "post_id", postIDStr)
+		}
+	} else if t, ok := existing["created_at"].(time.Time); ok {
+		result.RepostedAt = t
+	}
+
+	result.RepostCount, _ = r.GetRepostCount(ctx, postIDStr)
+	return result, nil
+}
+
+// Unrepost removes userID's plain repost of a post. Unreposting a post that
+// is not reposted is a no-op. Timeline entries of the repost are skipped on
+// read from then on.
+func (r *PostRepository) Unrepost(ctx context.Context, postIDStr, userIDStr string) (*RepostResult, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	applied, err := r.session.Query(`
+		DELETE FROM reposts WHERE post_id = ? AND user_id = ? IF EXISTS
+	`, postID, userID).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove repost: %w", err)
+	}
+
+	if applied {
+		if err := r.session.Query(`
+			UPDATE repost_counts SET count = count - 1 WHERE post_id = ?
+		`, postID).WithContext(ctx).Exec(); err != nil {
+			slog.Warn("Failed to decrement repost count", "error", err, "post_id", postIDStr)
+		}
+	}
+
+	result := &RepostResult{IsReposted: false, Changed: applied}
+	result.RepostCount, _ = r.GetRepostCount(ctx, postIDStr)
+	return result, nil
+}
+
+// GetRepostCount returns how many users have plainly reposted a post
+func (r *PostRepository) GetRepostCount(ctx context.Context, postIDStr string) (int64, error) {
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	var count int64
+	err = r.session.Query(`
+		SELECT count FROM repost_counts WHERE post_id = ?
+	`, postID).WithContext(ctx).Scan(&count)
+	if err == gocql.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return max(count, 0), nil
+}
+
+// GetRepostsForPosts returns repost counts and userID's repost state for
+// multiple posts
+func (r *PostRepository) GetRepostsForPosts(ctx context.Context, postIDs []string, userID string) (map[string]PostRepostInfo, error) {
+	result := make(map[string]PostRepostInfo, len(postIDs))
+
+	ids := make([]gocql.UUID, 0, len(postIDs))
+	for _, id := range postIDs {
+		if pid, err := gocql.ParseUUID(id); err == nil {
+			ids = append(ids, pid)
+		}
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	iter := r.session.Query(`
+		SELECT post_id, count FROM repost_counts WHERE post_id IN ?
+	`, ids).WithContext(ctx).Iter()
+	var postID gocql.UUID
+	var count int64
+	for iter.Scan(&postID, &count) {
+		result[postID.String()] = PostRepostInfo{RepostCount: max(count, 0)}
+	}
+	if err := iter.Close(); err != nil {
+		return result, fmt.Errorf("failed to get repost counts: %w", err)
+	}
+
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return result, nil
+	}
+	iter = r.session.Query(`
+		SELECT post_id FROM reposts WHERE post_id IN ? AND user_id = ?
+	`, ids, uid).WithContext(ctx).Iter()
+	for iter.Scan(&postID) {
+		info := result[postID.String()]
+		info.IsReposted = true
+		result[postID.String()] = info
+	}
+	if err := iter.Close(); err != nil {
+		return result, fmt.Errorf("failed to get repost state: %w", err)
+	}
+
+	return result, nil
+}
+
+// repostTime returns when userID plainly reposted a post, and false if they
+// have not (or no longer have)
+func (r *PostRepository) repostTime(ctx context.Context, postID, userID gocql.UUID) (time.Time, bool, error) {
+	var at time.Time
+	err := r.session.Query(`
+		SELECT created_at FROM reposts WHERE post_id = ? AND user_id = ?
+	`, postID, userID).WithContext(ctx).Scan(&at)
+	if err == gocql.ErrNotFound {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return at, true, nil
+}
+
+// isBlockedBetween reports whether either user has blocked the other
+func (r *PostRepository) isBlockedBetween(ctx context.Context, a, b string) (bool, error) {
+	if a == "" || b == "" || a == b {
+		return false, nil
+	}
+	const stmt = `SELECT blocked_id FROM blocks WHERE blocker_id = ? AND blocked_id = ?`
+	blocked, err := r.rowExists(ctx, stmt, a, b)
+	if err != nil || blocked {
+		return blocked, err
+	}
+	return r.rowExists(ctx, stmt, b, a)
+}
+
+// CheckCanShare returns an error unless userID may repost or quote the post:
+// it must be public, and neither user may have blocked the other. Posts the
+// user cannot see are reported as not found.
+func (r *PostRepository) CheckCanShare(ctx context.Context, post *Post, userID string) error {
+	blocked, err := r.isBlockedBetween(ctx, post.UserID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check blocks: %w", err)
+	}
+	if blocked {
+		return fmt.Errorf("post not found")
+	}
+
+	canView, err := r.CanViewPost(ctx, post, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check post visibility: %w", err)
+	}
+	if !canView {
+		return fmt.Errorf("post not found")
+	}
+	// A private account's posts stay with its followers
+	if post.UserID == userID {
+		if postVisibility(post.Visibility) != VisibilityPublic {
+			return fmt.Errorf("forbidden: only public posts can be shared")
+		}
+		return nil
+	}
+	open, err := r.IsOpenToEveryone(ctx, post)
+	if err != nil {
+		return fmt.Errorf("failed to check post visibility: %w", err)
+	}
+	if !open {
+		return fmt.Errorf("forbidden: only public posts can be shared")
+	}
+	return nil
+}
+
+// GetQuotedPosts returns the posts quoted by posts, keyed by ID, as viewerID
+// may see them. Quoted posts that were deleted or expired, that are outside
+// the viewer's audience, or whose author and the viewer have blocked each
+// other are left out.
+func (r *PostRepository) GetQuotedPosts(ctx context.Context, posts []Post, viewerID string) (map[string]Post, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, p := range posts {
+		if p.QuotedPostID != "" && !seen[p.QuotedPostID] {
+			seen[p.QuotedPostID] = true
+			ids = append(ids, p.QuotedPostID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	quoted, err := r.GetPostsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	quoted = r.FilterVisiblePosts(ctx, quoted, viewerID)
+
+	blockedAuthors := make(map[string]bool)
+	result := make(map[string]Post, len(quoted))
+	for _, q := range quoted {
+		blocked, checked := blockedAuthors[q.UserID]
+		if !checked {
+			var err error
+			blocked, err = r.isBlockedBetween(ctx, q.UserID, viewerID)
+			if err != nil {
+				slog.Warn("Failed to check blocks for quoted post", "error", err, "post_id", q.ID, "viewer_id", viewerID)
+				blocked = true
+			}
+			blockedAuthors[q.UserID] = blocked
+		}
+		if !blocked {
+			result[q.ID] = q
+		}
+	}
+	return result, nil
+}
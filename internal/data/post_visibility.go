@@ -0,0 +1,155 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/gocql/gocql"
+)
+
+// Post audiences
+const (
+	VisibilityPublic       = "public"
+	VisibilityFollowers    = "followers"
+	VisibilityCloseFriends = "close_friends"
+	VisibilityOnlyMe       = "only_me"
+)
+
+// ValidVisibilities lists the accepted values for a post's visibility
+var ValidVisibilities = map[string]bool{
+	VisibilityPublic:       true,
+	VisibilityFollowers:    true,
+	VisibilityCloseFriends: true,
+	VisibilityOnlyMe:       true,
+}
+
+// postVisibility returns the stored audience of a post. Posts written before
+// visibility existed have no value and are public.
+func postVisibility(v string) string {
+	if v == "" {
+		return VisibilityPublic
+	}
+	return v
+}
+
+// CanViewPost reports whether viewerID may read the post. Authors always see
+// their own posts; followers and close_friends posts need the matching
+// relationship to the author, and only_me posts are hidden from everyone else.
+// Public posts of a private account are followers posts.
+func (r *PostRepository) CanViewPost(ctx context.Context, post *Post, viewerID string) (bool, error) {
+	return r.canView(ctx, post.UserID, postVisibility(post.Visibility), viewerID)
+}
+
+// IsOpenToEveryone reports whether a post may be read by anyone: it is
+// public and its author's account is not private. Only these posts are
+// indexed for search, counted for trending and listed on hashtag pages.
+func (r *PostRepository) IsOpenToEveryone(ctx context.Context, post *Post) (bool, error) {
+	return r.isOpenToEveryone(ctx, post.UserID, postVisibility(post.Visibility))
+}
+
+func (r *PostRepository) isOpenToEveryone(ctx context.Context, authorID, visibility string) (bool, error) {
+	if visibility != VisibilityPublic {
+		return false, nil
+	}
+	private, err := r.isPrivateAuthor(ctx, authorID)
+	if err != nil {
+		return false, err
+	}
+	return !private, nil
+}
+
+// isPrivateAuthor reports whether authorID's account is private. Authors
+// whose account is gone count as public.
+func (r *PostRepository) isPrivateAuthor(ctx context.Context, authorID string) (bool, error) {
+	id, err := gocql.ParseUUID(authorID)
+	if err != nil {
+		return false, fmt.Errorf("invalid id: %w", err)
+	}
+
+	var private bool
+	err = r.session.Query(`SELECT is_private FROM users WHERE id = ?`, id).WithContext(ctx).Scan(&private)
+	if err == gocql.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return private, nil
+}
+
+func (r *PostRepository) canView(ctx context.Context, authorID, visibility, viewerID string) (bool, error) {
+	if viewerID != "" && viewerID == authorID {
+		return true, nil
+	}
+	if visibility == VisibilityPublic {
+		open, err := r.isOpenToEveryone(ctx, authorID, visibility)
+		if err != nil || open {
+			return open, err
+		}
+		visibility = VisibilityFollowers
+	}
+	if viewerID == "" {
+		return false, nil
+	}
+
+	switch visibility {
+	case VisibilityFollowers:
+		return r.rowExists(ctx, `SELECT following_id FROM follows WHERE follower_id = ? AND following_id = ?`, viewerID, authorID)
+	case VisibilityCloseFriends:
+		return r.rowExists(ctx, `SELECT friend_id FROM close_friends WHERE user_id = ? AND friend_id = ?`, authorID, viewerID)
+	}
+	return false, nil
+}
+
+// rowExists runs a single-row lookup keyed by two UUIDs
+func (r *PostRepository) rowExists(ctx context.Context, stmt, first, second string) (bool, error) {
+	a, err := gocql.ParseUUID(first)
+	if err != nil {
+		return false, fmt.Errorf("invalid id: %w", err)
+	}
+	b, err := gocql.ParseUUID(second)
+	if err != nil {
+		return false, fmt.Errorf("invalid id: %w", err)
+	}
+
+	var found gocql.UUID
+	err = r.session.Query(stmt, a, b).WithContext(ctx).Scan(&found)
+	if err == gocql.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FilterVisiblePosts drops the posts viewerID may not read, keeping the order
+// of the rest. Relationship lookups are made once per author and audience. A
+// failed lookup hides the post rather than risk showing it.
+func (r *PostRepository) FilterVisiblePosts(ctx context.Context, posts []Post, viewerID string) []Post {
+	allowed := make(map[string]bool)
+	visible := make([]Post, 0, len(posts))
+
+	for _, p := range posts {
+		visibility := postVisibility(p.Visibility)
+		key := p.UserID + "|" + visibility
+
+		ok, seen := allowed[key]
+		if !seen {
+			var err error
+			ok, err = r.canView(ctx, p.UserID, visibility, viewerID)
+			if err != nil {
+				slog.Warn("Failed to check post visibility", "error", err, "post_id", p.ID, "viewer_id", viewerID)
+				ok = false
+			}
+			allowed[key] = ok
+		}
+
+		if ok {
+			visible = append(visible, p)
+		}
+	}
+
+	return visible
+}
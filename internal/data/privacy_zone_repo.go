@@ -0,0 +1,171 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// Privacy zone limits
+const (
+	MaxPrivacyZones          = 10
+	DefaultPrivacyZoneRadius = 500  // meters
+	MinPrivacyZoneRadius     = 100  // meters
+	MaxPrivacyZoneRadius     = 5000 // meters
+)
+
+// PrivacyZone is an area, such as a user's home, where their posts are
+// published at no finer than LocationPrecision
+type PrivacyZone struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Latitude          float64   `json:"latitude"`
+	Longitude         float64   `json:"longitude"`
+	RadiusM           int       `json:"radius_m"`
+	LocationPrecision string    `json:"location_precision"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// CreatePrivacyZoneRequest represents the request body for adding a privacy zone
+type CreatePrivacyZoneRequest struct {
+	Name              string  `json:"name" binding:"required"`
+	Latitude          float64 `json:"latitude" binding:"required"`
+	Longitude         float64 `json:"longitude" binding:"required"`
+	RadiusM           int     `json:"radius_m"`           // Defaults to 500
+	LocationPrecision string  `json:"location_precision"` // Defaults to city
+}
+
+// Contains reports whether (lat, lng) falls inside the zone
+func (z *PrivacyZone) Contains(lat, lng float64) bool {
+	return HaversineDistance(z.Latitude, z.Longitude, lat, lng)*1000 <= float64(z.RadiusM)
+}
+
+// PrivacyZoneRepository stores users' privacy zones. Zones are only ever
+// returned to their owner.
+type PrivacyZoneRepository struct {
+	session *gocql.Session
+}
+
+// NewPrivacyZoneRepository creates a new PrivacyZoneRepository
+func NewPrivacyZoneRepository(session *gocql.Session) *PrivacyZoneRepository {
+	return &PrivacyZoneRepository{session: session}
+}
+
+// CreatePrivacyZone adds a zone for userID
+func (r *PrivacyZoneRepository) CreatePrivacyZone(ctx context.Context, userID string, req *CreatePrivacyZoneRequest) (*PrivacyZone, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if req.RadiusM == 0 {
+		req.RadiusM = DefaultPrivacyZoneRadius
+	}
+	if req.RadiusM < MinPrivacyZoneRadius || req.RadiusM > MaxPrivacyZoneRadius {
+		return nil, fmt.Errorf("invalid radius_m: must be between %d and %d", MinPrivacyZoneRadius, MaxPrivacyZoneRadius)
+	}
+	if req.LocationPrecision == "" {
+		req.LocationPrecision = LocationPrecisionCity
+	}
+	if !ValidLocationPrecision(req.LocationPrecision) || req.LocationPrecision == LocationPrecisionExact {
+		return nil, fmt.Errorf("invalid location_precision: must be neighbourhood, city or hidden")
+	}
+
+	zones, err := r.GetPrivacyZones(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) >= MaxPrivacyZones {
+		return nil, fmt.Errorf("too many privacy zones: the limit is %d", MaxPrivacyZones)
+	}
+
+	zoneID := gocql.TimeUUID()
+	now := time.Now()
+	if err := r.session.Query(`
+		INSERT INTO privacy_zones (user_id, zone_id, name, latitude, longitude, radius_m, location_precision, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, uid, zoneID, req.Name, req.Latitude, req.Longitude, req.RadiusM, req.LocationPrecision, now).WithContext(ctx).Exec(); err != nil {
+		return nil, fmt.Errorf("failed to create privacy zone: %w", err)
+	}
+
+	return &PrivacyZone{
+		ID:                zoneID.String(),
+		Name:              req.Name,
+		Latitude:          req.Latitude,
+		Longitude:         req.Longitude,
+		RadiusM:           req.RadiusM,
+		LocationPrecision: req.LocationPrecision,
+		CreatedAt:         now,
+	}, nil
+}
+
+// GetPrivacyZones returns userID's zones, oldest first
+func (r *PrivacyZoneRepository) GetPrivacyZones(ctx context.Context, userID string) ([]PrivacyZone, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT zone_id, name, latitude, longitude, radius_m, location_precision, created_at
+		FROM privacy_zones WHERE user_id = ?
+	`, uid).WithContext(ctx).Iter()
+
+	var zones []PrivacyZone
+	var zone PrivacyZone
+	var zoneID gocql.UUID
+	for iter.Scan(&zoneID, &zone.Name, &zone.Latitude, &zone.Longitude, &zone.RadiusM, &zone.LocationPrecision, &zone.CreatedAt) {
+		zone.ID = zoneID.String()
+		zones = append(zones, zone)
+		zone = PrivacyZone{}
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get privacy zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+// DeletePrivacyZone removes one of userID's zones
+func (r *PrivacyZoneRepository) DeletePrivacyZone(ctx context.Context, userID, zoneIDStr string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	zoneID, err := gocql.ParseUUID(zoneIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid zone_id: %w", err)
+	}
+
+	applied, err := r.session.Query(`
+		DELETE FROM privacy_zones WHERE user_id = ? AND zone_id = ? IF EXISTS
+	`, uid, zoneID).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to delete privacy zone: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("privacy zone not found")
+	}
+	return nil
+}
+
+// ResolveLocationPrecision returns the precision a post at (lat, lng) is
+// published at: the requested one, coarsened by every zone of userID that
+// contains the point
+func (r *PrivacyZoneRepository) ResolveLocationPrecision(ctx context.Context, userID string, lat, lng float64, requested string) (string, error) {
+	zones, err := r.GetPrivacyZones(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	precision := postLocationPrecision(requested)
+	for i := range zones {
+		if zones[i].Contains(lat, lng) {
+			precision = CoarserLocationPrecision(precision, zones[i].LocationPrecision)
+		}
+	}
+	return precision, nil
+}
@@ -0,0 +1,191 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// MaxStoredSuggestions caps the suggestions stored per user
+const MaxStoredSuggestions = 50
+
+// suggestionRefreshShards spreads pending refreshes over this many partitions
+const suggestionRefreshShards = 8
+
+type SuggestionRepository struct {
+	session *gocql.Session
+}
+
+func NewSuggestionRepository(session *gocql.Session) *SuggestionRepository {
+	return &SuggestionRepository{session: session}
+}
+
+// suggestionRefreshShard returns the refresh partition of a user
+func suggestionRefreshShard(userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(userID)) //nolint:errcheck
+	return int(h.Sum32() % suggestionRefreshShards)
+}
+
+// SaveSuggestions replaces the stored suggestions of userID, best first
+func (r *SuggestionRepository) SaveSuggestions(ctx context.Context, userID string, suggestions []Suggestion) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if len(suggestions) > MaxStoredSuggestions {
+		suggestions = suggestions[:MaxStoredSuggestions]
+	}
+
+	now := time.Now()
+	batch := r.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+	for rank, s := range suggestions {
+		sid, err := gocql.ParseUUID(s.UserID)
+		if err != nil {
+			return fmt.Errorf("invalid suggested user_id: %w", err)
+		}
+		var mutualID *gocql.UUID
+		if id, err := gocql.ParseUUID(s.MutualID); err == nil {
+			mutualID = &id
+		}
+		batch.Query(`
+			INSERT INTO user_suggestions (user_id, rank, suggested_id, score, reason, mutual_id, mutual_count, place, computed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, uid, rank, sid, s.Score, s.Reason, mutualID, s.MutualCount, s.Place, now)
+	}
+
+	// Ranks past the new list are left over from a longer earlier one. With
+	// no suggestions a marker row records when they were computed.
+	if len(suggestions) == 0 {
+		batch.Query(`
+			INSERT INTO user_suggestions (user_id, rank, computed_at) VALUES (?, -1, ?)
+		`, uid, now)
+	} else {
+		batch.Query(`
+			DELETE FROM user_suggestions WHERE user_id = ? AND rank = -1
+		`, uid)
+	}
+	batch.Query(`
+		DELETE FROM user_suggestions WHERE user_id = ? AND rank >= ?
+	`, uid, len(suggestions))
+
+	if err := r.session.ExecuteBatch(batch); err != nil {
+		return fmt.Errorf("failed to save suggestions: %w", err)
+	}
+
+	return nil
+}
+
+// GetSuggestions returns the stored suggestions of userID, best first, and
+// when they were computed. The time is zero if they never were.
+func (r *SuggestionRepository) GetSuggestions(ctx context.Context, userID string) ([]Suggestion, time.Time, error) {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	iter := r.session.Query(`
+		SELECT rank, suggested_id, score, reason, mutual_id, mutual_count, place, computed_at
+		FROM user_suggestions WHERE user_id = ?
+	`, uid).WithContext(ctx).Iter()
+
+	var suggestions []Suggestion
+	var computedAt time.Time
+	var rank, mutualCount int
+	var suggestedID, mutualID gocql.UUID
+	var score float64
+	var reason, place string
+	var rowComputedAt time.Time
+
+	for iter.Scan(&rank, &suggestedID, &score, &reason, &mutualID, &mutualCount, &place, &rowComputedAt) {
+		if rowComputedAt.After(computedAt) {
+			computedAt = rowComputedAt
+		}
+		if rank >= 0 {
+			s := Suggestion{
+				UserID:      suggestedID.String(),
+				Score:       score,
+				Reason:      reason,
+				MutualCount: mutualCount,
+				Place:       place,
+			}
+			if mutualID != (gocql.UUID{}) {
+				s.MutualID = mutualID.String()
+			}
+			suggestions = append(suggestions, s)
+		}
+
+		mutualID = gocql.UUID{}
+		mutualCount = 0
+		place = ""
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	return suggestions, computedAt, nil
+}
+
+// RequestRefresh queues userID for the suggestion worker
+func (r *SuggestionRepository) RequestRefresh(ctx context.Context, userID string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	err = r.session.Query(`
+		INSERT INTO suggestion_refresh (shard, user_id, requested_at) VALUES (?, ?, ?)
+	`, suggestionRefreshShard(userID), uid, time.Now()).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to request suggestion refresh: %w", err)
+	}
+
+	return nil
+}
+
+// GetRefreshRequests returns up to limit users queued for the suggestion
+// worker, across all shards
+func (r *SuggestionRepository) GetRefreshRequests(ctx context.Context, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var userIDs []string
+	for shard := 0; shard < suggestionRefreshShards && len(userIDs) < limit; shard++ {
+		iter := r.session.Query(`
+			SELECT user_id FROM suggestion_refresh WHERE shard = ? LIMIT ?
+		`, shard, limit-len(userIDs)).WithContext(ctx).Iter()
+
+		var userID gocql.UUID
+		for iter.Scan(&userID) {
+			userIDs = append(userIDs, userID.String())
+		}
+		if err := iter.Close(); err != nil {
+			return userIDs, fmt.Errorf("failed to get suggestion refreshes: %w", err)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// ClearRefreshRequest takes userID off the suggestion worker's queue
+func (r *SuggestionRepository) ClearRefreshRequest(ctx context.Context, userID string) error {
+	uid, err := gocql.ParseUUID(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	err = r.session.Query(`
+		DELETE FROM suggestion_refresh WHERE shard = ? AND user_id = ?
+	`, suggestionRefreshShard(userID), uid).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to clear suggestion refresh: %w", err)
+	}
+
+	return nil
+}
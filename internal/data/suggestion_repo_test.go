@@ -0,0 +1,80 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestionRepository_Integration(t *testing.T) {
+	repo := NewSuggestionRepository(testSession)
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	first := uuid.New().String()
+	second := uuid.New().String()
+	mutualID := uuid.New().String()
+
+	t.Run("Never Computed", func(t *testing.T) {
+		suggestions, computedAt, err := repo.GetSuggestions(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, suggestions)
+		assert.True(t, computedAt.IsZero())
+	})
+
+	t.Run("Save And Replace", func(t *testing.T) {
+		require.NoError(t, repo.SaveSuggestions(ctx, userID, []Suggestion{
+			{UserID: first, Score: 6, Reason: SuggestionReasonMutual, MutualID: mutualID, MutualCount: 2},
+			{UserID: second, Score: 2, Reason: SuggestionReasonLocation, Place: "Kemang"},
+		}))
+
+		suggestions, computedAt, err := repo.GetSuggestions(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, computedAt.IsZero())
+		require.Len(t, suggestions, 2)
+		assert.Equal(t, first, suggestions[0].UserID)
+		assert.Equal(t, mutualID, suggestions[0].MutualID)
+		assert.Equal(t, 2, suggestions[0].MutualCount)
+		assert.Equal(t, "Kemang", suggestions[1].Place)
+		assert.Empty(t, suggestions[1].MutualID)
+
+		// A shorter list drops the old tail
+		require.NoError(t, repo.SaveSuggestions(ctx, userID, []Suggestion{
+			{UserID: second, Score: 2, Reason: SuggestionReasonFollowsYou},
+		}))
+		suggestions, _, err = repo.GetSuggestions(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, second, suggestions[0].UserID)
+
+		// An empty list still records when it was computed
+		require.NoError(t, repo.SaveSuggestions(ctx, userID, nil))
+		suggestions, computedAt, err = repo.GetSuggestions(ctx, userID)
+		require.NoError(t, err)
+		assert.Empty(t, suggestions)
+		assert.False(t, computedAt.IsZero())
+	})
+
+	t.Run("Refresh Queue", func(t *testing.T) {
+		require.NoError(t, repo.RequestRefresh(ctx, userID))
+		require.NoError(t, repo.RequestRefresh(ctx, userID))
+
+		queued, err := repo.GetRefreshRequests(ctx, 1000)
+		require.NoError(t, err)
+		count := 0
+		for _, id := range queued {
+			if id == userID {
+				count++
+			}
+		}
+		assert.Equal(t, 1, count)
+
+		require.NoError(t, repo.ClearRefreshRequest(ctx, userID))
+		queued, err = repo.GetRefreshRequests(ctx, 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, queued, userID)
+	})
+}
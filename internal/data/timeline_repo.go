@@ -0,0 +1,337 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// TimelineFanOutThreshold is the follower count at which an author stops being
+// fanned out on write. Posts from such authors are pulled from posts_by_user at
+// read time instead, so a single post never triggers millions of inserts.
+const TimelineFanOutThreshold int64 = 10000
+
+// TimelineRepository maintains per-user home timelines built from the follow graph
+type TimelineRepository struct {
+	session *gocql.Session
+	posts   *PostRepository
+}
+
+func NewTimelineRepository(session *gocql.Session) *TimelineRepository {
+	return &TimelineRepository{
+		session: session,
+		posts:   NewPostRepository(session, nil),
+	}
+}
+
+// FanOutPost pushes a new post into the author's own timeline and, for authors
+// below TimelineFanOutThreshold, into the timeline of every follower.
+func (r *TimelineRepository) FanOutPost(ctx context.Context, post *Post) error {
+	authorID, err := gocql.ParseUUID(post.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	postID, err := gocql.ParseUUID(post.ID)
+	if err != nil {
+		return fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	return r.fanOut(ctx, authorID, postID, post.CreatedAt, gocql.UnsetValue)
+}
+
+// FanOutRepost pushes a plain repost into the reposter's own timeline and, for
+// reposters below TimelineFanOutThreshold, into the timeline of every
+// follower. The entry is keyed by the repost time and carries the reposter as
+// its author, so it follows the reposter's follow graph.
+func (r *TimelineRepository) FanOutRepost(ctx context.Context, postIDStr, reposterIDStr string, repostedAt time.Time) error {
+	reposterID, err := gocql.ParseUUID(reposterIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+	postID, err := gocql.ParseUUID(postIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid post_id: %w", err)
+	}
+
+	return r.fanOut(ctx, reposterID, postID, repostedAt, reposterID)
+}
+
+// fanOut inserts an entry into authorID's timeline and those of their
+// followers. repostedBy is the reposter for repost entries and unset otherwise.
+func (r *TimelineRepository) fanOut(ctx context.Context, authorID, postID gocql.UUID, createdAt time.Time, repostedBy interface{}) error {
+	if err := r.insertEntry(ctx, authorID, authorID, postID, createdAt, repostedBy); err != nil {
+		return err
+	}
+
+	var followersCount int64
+	err := r.session.Query(`
+		SELECT followers_count FROM follow_counts WHERE user_id = ?
+	`, authorID).WithContext(ctx).Scan(&followersCount)
+	if err != nil && err != gocql.ErrNotFound {
+		return fmt.Errorf("failed to get follower count: %w", err)
+	}
+	if followersCount >= TimelineFanOutThreshold {
+		// High-follower author: readers pull these posts on demand
+		return nil
+	}
+
+	iter := r.session.Query(`
+		SELECT follower_id FROM followers WHERE user_id = ?
+	`, authorID).WithContext(ctx).PageSize(500).Iter()
+
+	var followerID gocql.UUID
+	var delivered, failed int
+	for iter.Scan(&followerID) {
+		if err := r.insertEntry(ctx, followerID, authorID, postID, createdAt, repostedBy); err != nil {
+			failed++
+			continue
+		}
+		delivered++
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("error iterating followers: %w", err)
+	}
+
+	if failed > 0 {
+		slog.Warn("Timeline fan-out incomplete", "post_id", postID.String(), "delivered", delivered, "failed", failed)
+	}
+
+	return nil
+}
+
+// BackfillFromAuthor copies an author's most recent posts into a new follower's
+// timeline so the home feed is not empty until the author posts again.
+func (r *TimelineRepository) BackfillFromAuthor(ctx context.Context, followerIDStr, authorIDStr string, limit int) error {
+	followerID, err := gocql.ParseUUID(followerIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid follower_id: %w", err)
+	}
+	authorID, err := gocql.ParseUUID(authorIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid author_id: %w", err)
+	}
+
+	posts, err := r.posts.GetPostsByUser(ctx, authorIDStr, limit, Keyset{})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		postID, err := gocql.ParseUUID(p.ID)
+		if err != nil {
+			continue
+		}
+		if err := r.insertEntry(ctx, followerID, authorID, postID, p.CreatedAt, gocql.UnsetValue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TimelineRepository) insertEntry(ctx context.Context, userID, authorID, postID gocql.UUID, createdAt time.Time, repostedBy interface{}) error {
+	err := r.session.Query(`
+		INSERT INTO home_timeline (user_id, created_at, post_id, author_id, reposted_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, createdAt, postID, authorID, repostedBy).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to insert timeline entry: %w", err)
+	}
+	return nil
+}
+
+// GetHomeTimeline returns posts from the accounts a user follows (and the user's
+// own posts), newest first. Pushed timeline entries are merged with posts pulled
+// from high-follower authors; entries from accounts the user no longer follows
+// are dropped, as are posts whose audience does not include the user. Results
+// are ordered by (created_at DESC, post_id ASC) and start after the given keyset.
+func (r *TimelineRepository) GetHomeTimeline(ctx context.Context, userIDStr string, limit int, after Keyset) ([]Post, error) {
+	userID, err := gocql.ParseUUID(userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	if limit <= 0 || limit > 250 {
+		limit = 50 // Default 50 posts, max 250
+	}
+
+	following, err := r.getAllFollowing(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool, len(following)+1)
+	allowed[userIDStr] = true
+	for _, id := range following {
+		allowed[id] = true
+	}
+
+	// Pushed entries
+	var iter *gocql.Iter
+	if after.IsZero() {
+		iter = r.session.Query(`
+			SELECT post_id, author_id, created_at, reposted_by FROM home_timeline
+			WHERE user_id = ?
+		`, userID).WithContext(ctx).PageSize(limit * 2).Iter()
+	} else {
+		iter = r.session.Query(`
+			SELECT post_id, author_id, created_at, reposted_by FROM home_timeline
+			WHERE user_id = ? AND created_at <= ?
+		`, userID, after.CreatedAt).WithContext(ctx).PageSize(limit * 2).Iter()
+	}
+
+	type timelineEntry struct {
+		postID     gocql.UUID
+		createdAt  time.Time
+		repostedBy gocql.UUID // Zero for the author's own posts
+	}
+
+	var entries []timelineEntry
+	var postIDs []string
+	wanted := make(map[string]bool)
+	var postID, authorID, repostedBy gocql.UUID
+	var createdAt, lastAt time.Time
+	for iter.Scan(&postID, &authorID, &createdAt, &repostedBy) {
+		if len(entries) > 0 && keysetScanDone(len(entries), limit, lastAt, createdAt) {
+			break
+		}
+		if allowed[authorID.String()] && after.Admits(createdAt, postID.String()) {
+			entries = append(entries, timelineEntry{postID: postID, createdAt: createdAt, repostedBy: repostedBy})
+			if !wanted[postID.String()] {
+				wanted[postID.String()] = true
+				postIDs = append(postIDs, postID.String())
+			}
+			lastAt = createdAt
+		}
+		repostedBy = gocql.UUID{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating timeline: %w", err)
+	}
+
+	fetched, err := r.posts.GetPostsByIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Post, len(fetched))
+	for _, p := range fetched {
+		byID[p.ID] = p
+	}
+
+	// Entries of deleted or expired posts are skipped, as are reposts that were
+	// undone since (a later repost of the same post has an entry of its own)
+	posts := make([]Post, 0, len(entries))
+	for _, e := range entries {
+		p, ok := byID[e.postID.String()]
+		if !ok {
+			continue
+		}
+		if e.repostedBy != (gocql.UUID{}) {
+			at, ok, err := r.posts.repostTime(ctx, e.postID, e.repostedBy)
+			if err != nil {
+				slog.Warn("Failed to check repost", "error", err, "post_id", p.ID, "reposted_by", e.repostedBy.String())
+				continue
+			}
+			if !ok || !at.Equal(e.createdAt) {
+				continue
+			}
+			repostedAt := e.createdAt
+			p.RepostedBy = e.repostedBy.String()
+			p.RepostedAt = &repostedAt
+		}
+		posts = append(posts, p)
+	}
+
+	// Pulled posts from authors that are not fanned out on write
+	highFollowerAuthors, err := r.getHighFollowerAuthors(ctx, following)
+	if err != nil {
+		slog.Warn("Failed to resolve high-follower authors", "error", err, "user_id", userIDStr)
+	}
+	for _, author := range highFollowerAuthors {
+		authorPosts, err := r.posts.GetPostsByUser(ctx, author, limit, after)
+		if err != nil {
+			slog.Warn("Failed to pull author posts for timeline", "error", err, "author_id", author)
+			continue
+		}
+		posts = append(posts, authorPosts...)
+	}
+
+	// Merge: de-duplicate (an author may have crossed the threshold, and a
+	// post may be both in the timeline and reposted into it) and sort
+	seen := make(map[string]bool, len(posts))
+	merged := make([]Post, 0, len(posts))
+	for _, p := range posts {
+		if seen[p.ID] {
+			continue
+		}
+		seen[p.ID] = true
+		merged = append(merged, p)
+	}
+
+	// Followers-only posts pass for followers; close_friends and only_me posts
+	// only for their audience
+	merged = r.posts.FilterVisiblePosts(ctx, merged, userIDStr)
+
+	SortPostsByKeyset(merged)
+
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+
+	return merged, nil
+}
+
+// getAllFollowing returns every account a user follows
+func (r *TimelineRepository) getAllFollowing(ctx context.Context, userID gocql.UUID) ([]string, error) {
+	iter := r.session.Query(`
+		SELECT following_id FROM follows WHERE follower_id = ?
+	`, userID).WithContext(ctx).PageSize(1000).Iter()
+
+	var following []string
+	var followingID gocql.UUID
+	for iter.Scan(&followingID) {
+		following = append(following, followingID.String())
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("error iterating following: %w", err)
+	}
+
+	return following, nil
+}
+
+// getHighFollowerAuthors returns the subset of userIDs at or above TimelineFanOutThreshold
+func (r *TimelineRepository) getHighFollowerAuthors(ctx context.Context, userIDs []string) ([]string, error) {
+	const chunkSize = 100
+
+	var authors []string
+	for start := 0; start < len(userIDs); start += chunkSize {
+		end := min(start+chunkSize, len(userIDs))
+
+		uuids := make([]gocql.UUID, 0, end-start)
+		for _, id := range userIDs[start:end] {
+			if uid, err := gocql.ParseUUID(id); err == nil {
+				uuids = append(uuids, uid)
+			}
+		}
+
+		iter := r.session.Query(`
+			SELECT user_id, followers_count FROM follow_counts WHERE user_id IN ?
+		`, uuids).WithContext(ctx).Iter()
+
+		var uid gocql.UUID
+		var followersCount int64
+		for iter.Scan(&uid, &followersCount) {
+			if followersCount >= TimelineFanOutThreshold {
+				authors = append(authors, uid.String())
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return authors, err
+		}
+	}
+
+	return authors, nil
+}
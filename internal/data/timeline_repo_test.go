@@ -0,0 +1,55 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineRepository_Integration(t *testing.T) {
+	repo := NewTimelineRepository(testSession)
+	postRepo := NewPostRepository(testSession, nil)
+	followRepo := NewFollowRepository(testSession)
+	ctx := context.Background()
+
+	reader := uuid.New().String()
+	author := uuid.New().String()
+	stranger := uuid.New().String()
+
+	require.NoError(t, followRepo.Follow(ctx, reader, author))
+
+	authorPost, err := postRepo.CreatePost(ctx, &CreatePostRequest{
+		UserID: author, Content: "From someone I follow", Latitude: -6.2, Longitude: 106.8,
+	})
+	require.NoError(t, err)
+	strangerPost, err := postRepo.CreatePost(ctx, &CreatePostRequest{
+		UserID: stranger, Content: "From a stranger", Latitude: -6.2, Longitude: 106.8,
+	})
+	require.NoError(t, err)
+
+	t.Run("Fan Out To Followers", func(t *testing.T) {
+		require.NoError(t, repo.FanOutPost(ctx, authorPost))
+		require.NoError(t, repo.FanOutPost(ctx, strangerPost))
+
+		posts, err := repo.GetHomeTimeline(ctx, reader, 10, Keyset{})
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(posts))
+		for _, p := range posts {
+			ids = append(ids, p.ID)
+		}
+		assert.Contains(t, ids, authorPost.ID)
+		assert.NotContains(t, ids, strangerPost.ID)
+	})
+
+	t.Run("Unfollowed Authors Are Dropped", func(t *testing.T) {
+		require.NoError(t, followRepo.Unfollow(ctx, reader, author))
+
+		posts, err := repo.GetHomeTimeline(ctx, reader, 10, Keyset{})
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+}
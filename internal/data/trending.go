@@ -0,0 +1,104 @@
+package data
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Trending windows. Hashtag usage is counted per hour for the recent window
+// and per day for the baseline it is compared with.
+const (
+	TrendingRecentHours  = 6
+	TrendingBaselineDays = 7
+	TrendingHalfLife     = 2 * time.Hour
+	MinTrendingCount     = 3 // Posts in the recent window a hashtag needs to trend
+)
+
+// TrendingPrecisions are the geohash precisions hashtag usage is counted at,
+// from finest to coarsest
+var TrendingPrecisions = []uint{4, 3}
+
+// TrendingCells returns the cells a post at (lat, lng) is counted in, one per
+// trending precision
+func TrendingCells(lat, lng float64) []string {
+	cells := make([]string, len(TrendingPrecisions))
+	for i, precision := range TrendingPrecisions {
+		cells[i] = EncodeGeohash(lat, lng, precision)
+	}
+	return cells
+}
+
+// TrendingCover returns the cells, all of one trending precision, whose counts
+// make up the region of radiusKM around (lat, lng)
+func TrendingCover(lat, lng, radiusKM float64) []string {
+	_, cells := geohashCoverAt(lat, lng, radiusKM, TrendingPrecisions)
+	return cells
+}
+
+// HashtagUsage is how often a hashtag was used in a region
+type HashtagUsage struct {
+	Hashtag  string
+	Recent   []int64 // Posts per hour, newest first; Recent[0] is the current hour
+	Baseline int64   // Posts in the TrendingBaselineDays before today (UTC)
+}
+
+// TrendingHashtag is a hashtag whose usage in a region is spiking
+type TrendingHashtag struct {
+	Hashtag         string  `json:"hashtag"`
+	Score           float64 `json:"score"`
+	RecentCount     int64   `json:"recent_count"`
+	BaselinePerHour float64 `json:"baseline_per_hour"`
+}
+
+// trendingScore compares the recent usage of a hashtag with its baseline.
+// Each recent hour is weighted by TrendingHalfLife decay, so a spike fades
+// as it ages, and the baseline rate is weighted the same way. The excess is
+// scaled by the square root of the expected count, so a jump from 0 to 5
+// outranks one from 100 to 105.
+func trendingScore(u HashtagUsage) (score float64, recent int64, perHour float64) {
+	perHour = float64(u.Baseline) / float64(TrendingBaselineDays*24)
+
+	var observed, weights float64
+	for hoursAgo, count := range u.Recent {
+		w := math.Pow(0.5, float64(hoursAgo)*float64(time.Hour)/float64(TrendingHalfLife))
+		observed += w * float64(count)
+		weights += w
+		recent += count
+	}
+
+	expected := perHour * weights
+	return (observed - expected) / math.Sqrt(expected+1), recent, perHour
+}
+
+// RankTrending returns the hashtags of usage that are spiking, highest score
+// first, at most limit. Hashtags in muted are left out.
+func RankTrending(usage []HashtagUsage, muted map[string]bool, limit int) []TrendingHashtag {
+	trending := []TrendingHashtag{}
+	for _, u := range usage {
+		if muted[u.Hashtag] {
+			continue
+		}
+		score, recent, perHour := trendingScore(u)
+		if recent < MinTrendingCount || score <= 0 {
+			continue
+		}
+		trending = append(trending, TrendingHashtag{
+			Hashtag:         u.Hashtag,
+			Score:           math.Round(score*100) / 100,
+			RecentCount:     recent,
+			BaselinePerHour: math.Round(perHour*100) / 100,
+		})
+	}
+
+	sort.Slice(trending, func(i, j int) bool {
+		if trending[i].Score != trending[j].Score {
+			return trending[i].Score > trending[j].Score
+		}
+		return trending[i].Hashtag < trending[j].Hashtag
+	})
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending
+}
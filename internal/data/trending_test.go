@@ -0,0 +1,76 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankTrending(t *testing.T) {
+	t.Run("Spike Outranks Steady Use", func(t *testing.T) {
+		usage := []HashtagUsage{
+			// Used about as often as in the baseline week
+			{Hashtag: "kopi", Recent: []int64{10, 10, 10, 10, 10, 10}, Baseline: 10 * 24 * TrendingBaselineDays},
+			// Barely used before today
+			{Hashtag: "banjir", Recent: []int64{8, 4, 0, 0, 0, 0}, Baseline: 2},
+		}
+		trending := RankTrending(usage, nil, 10)
+		require.Len(t, trending, 1)
+		assert.Equal(t, "banjir", trending[0].Hashtag)
+		assert.Equal(t, int64(12), trending[0].RecentCount)
+	})
+
+	t.Run("Spike Decays With Age", func(t *testing.T) {
+		usage := []HashtagUsage{
+			{Hashtag: "old", Recent: []int64{0, 0, 0, 0, 0, 6}},
+			{Hashtag: "new", Recent: []int64{6, 0, 0, 0, 0, 0}},
+		}
+		trending := RankTrending(usage, nil, 10)
+		require.Len(t, trending, 2)
+		assert.Equal(t, "new", trending[0].Hashtag)
+		assert.Greater(t, trending[0].Score, trending[1].Score)
+	})
+
+	t.Run("Too Few Posts Do Not Trend", func(t *testing.T) {
+		usage := []HashtagUsage{{Hashtag: "rare", Recent: []int64{MinTrendingCount - 1, 0, 0, 0, 0, 0}}}
+		assert.Empty(t, RankTrending(usage, nil, 10))
+	})
+
+	t.Run("Muted And Limit", func(t *testing.T) {
+		usage := []HashtagUsage{
+			{Hashtag: "a", Recent: []int64{9, 0, 0, 0, 0, 0}},
+			{Hashtag: "b", Recent: []int64{6, 0, 0, 0, 0, 0}},
+			{Hashtag: "c", Recent: []int64{3, 0, 0, 0, 0, 0}},
+		}
+		trending := RankTrending(usage, map[string]bool{"a": true}, 1)
+		require.Len(t, trending, 1)
+		assert.Equal(t, "b", trending[0].Hashtag)
+	})
+}
+
+func TestTrendingCells(t *testing.T) {
+	cells := TrendingCells(-6.2088, 106.8456)
+	require.Len(t, cells, len(TrendingPrecisions))
+	for i, precision := range TrendingPrecisions {
+		assert.Len(t, cells[i], int(precision))
+	}
+
+	// A small region is covered by cells of the finest precision
+	for _, cell := range TrendingCover(-6.2088, 106.8456, 10) {
+		assert.Len(t, cell, int(TrendingPrecisions[0]))
+	}
+	assert.LessOrEqual(t, len(TrendingCover(-6.2088, 106.8456, 100)), MaxCoverCells)
+}
+
+func TestNormalizeMutedWord(t *testing.T) {
+	word, err := NormalizeMutedWord("  #Spoiler ")
+	require.NoError(t, err)
+	assert.Equal(t, "spoiler", word)
+
+	for _, bad := range []string{"", " ", "#", strings.Repeat("a", MaxMutedWordLength+1)} {
+		_, err := NormalizeMutedWord(bad)
+		assert.Error(t, err, "expected error for %q", bad)
+	}
+}
@@ -167,12 +167,12 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*User, err
 
 	var user User
 	err = r.session.Query(`
-		SELECT id, username, email, full_name, bio, phone_number, profile_picture_url, cover_image_url, password_hash, is_deleted, created_at, updated_at
+		SELECT id, username, email, full_name, bio, phone_number, profile_picture_url, cover_image_url, is_private, password_hash, is_deleted, created_at, updated_at
 		FROM users
 		WHERE id = ?
 	`, userID).WithContext(ctx).Scan(
 		&userID, &user.Username, &user.Email, &user.FullName,
-		&user.Bio, &user.PhoneNumber, &user.ProfilePictureURL, &user.CoverImageURL, &user.PasswordHash, &user.IsDeleted, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.PhoneNumber, &user.ProfilePictureURL, &user.CoverImageURL, &user.IsPrivate, &user.PasswordHash, &user.IsDeleted, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -196,13 +196,13 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
 	var userID gocql.UUID
 
 	err := r.session.Query(`
-		SELECT id, username, email, full_name, bio, phone_number, profile_picture_url, cover_image_url, password_hash, is_deleted, created_at, updated_at
+		SELECT id, username, email, full_name, bio, phone_number, profile_picture_url, cover_image_url, is_private, password_hash, is_deleted, created_at, updated_at
 		FROM users
 		WHERE username = ?
 		ALLOW FILTERING
 	`, username).WithContext(ctx).Scan(
 		&userID, &user.Username, &user.Email, &user.FullName,
-		&user.Bio, &user.PhoneNumber, &user.ProfilePictureURL, &user.CoverImageURL, &user.PasswordHash, &user.IsDeleted, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.PhoneNumber, &user.ProfilePictureURL, &user.CoverImageURL, &user.IsPrivate, &user.PasswordHash, &user.IsDeleted, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -226,13 +226,13 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*Use
 	var userID gocql.UUID
 
 	err := r.session.Query(`
-		SELECT id, username, email, full_name, bio, phone_number, profile_picture_url, cover_image_url, password_hash, is_deleted, created_at, updated_at
+		SELECT id, username, email, full_name, bio, phone_number, profile_picture_url, cover_image_url, is_private, password_hash, is_deleted, created_at, updated_at
 		FROM users
 		WHERE email = ?
 		ALLOW FILTERING
 	`, email).WithContext(ctx).Scan(
 		&userID, &user.Username, &user.Email, &user.FullName,
-		&user.Bio, &user.PhoneNumber, &user.ProfilePictureURL, &user.CoverImageURL, &user.PasswordHash, &user.IsDeleted, &user.CreatedAt, &user.UpdatedAt,
+		&user.Bio, &user.PhoneNumber, &user.ProfilePictureURL, &user.CoverImageURL, &user.IsPrivate, &user.PasswordHash, &user.IsDeleted, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -271,6 +271,23 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id string, fullName, bi
 	return r.GetUserByID(ctx, id)
 }
 
+// SetPrivate switches whether following the user needs their approval
+func (r *UserRepository) SetPrivate(ctx context.Context, id string, private bool) error {
+	userID, err := gocql.ParseUUID(id)
+	if err != nil {
+		return fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	err = r.session.Query(`
+		UPDATE users SET is_private = ?, updated_at = ? WHERE id = ?
+	`, private, time.Now(), userID).WithContext(ctx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to update privacy: %w", err)
+	}
+
+	return nil
+}
+
 // UserExists checks if a user with given ID exists
 func (r *UserRepository) UserExists(ctx context.Context, id string) (bool, error) {
 	userID, err := gocql.ParseUUID(id)
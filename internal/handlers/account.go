@@ -3,11 +3,13 @@ package handlers
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"social-geo-go/internal/auth"
 	"social-geo-go/internal/data"
+	"social-geo-go/internal/search"
 )
 
 // DeleteAccountRequest represents the request body for account deletion
@@ -17,7 +19,7 @@ type DeleteAccountRequest struct {
 
 // DeleteAccount handles DELETE /api/v1/users/me
 // Requires password confirmation to prevent CSRF-style deletion
-func DeleteAccount(userRepo *data.UserRepository) gin.HandlerFunc {
+func DeleteAccount(userRepo *data.UserRepository, searchIndexer search.SearchIndexer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := auth.GetUserID(c)
 		if userID == "" {
@@ -71,6 +73,13 @@ func DeleteAccount(userRepo *data.UserRepository) gin.HandlerFunc {
 
 		slog.Info("[ACCOUNT] User account deleted", "user_id", userID)
 
+		// Remove the account from user search and autocomplete
+		search.PublishUserDeletedAsync(searchIndexer, search.UserDeletedEvent{
+			UserID:    userID,
+			Username:  user.Username,
+			DeletedAt: time.Now(),
+		})
+
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Your account has been deleted. All personal data has been anonymized.",
 		})
@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// attachBookmarks marks the posts the viewer has bookmarked
+func attachBookmarks(ctx context.Context, posts []data.Post, bookmarkRepo *data.BookmarkRepository, currentUserID string) {
+	if len(posts) == 0 || currentUserID == "" {
+		return
+	}
+
+	postIDs := make([]string, len(posts))
+	for i, p := range posts {
+		postIDs[i] = p.ID
+	}
+
+	bookmarked, err := bookmarkRepo.GetBookmarkedPosts(ctx, postIDs, currentUserID)
+	if err != nil {
+		slog.Warn("Failed to get bookmark state", "error", err)
+	}
+	for i := range posts {
+		posts[i].IsBookmarked = bookmarked[posts[i].ID]
+	}
+}
+
+// BookmarkPost handles POST /api/v1/posts/:id/bookmark
+// The optional body names the collection to save the post in. Bookmarking a
+// saved post again moves it to that collection, so retries are safe.
+func BookmarkPost(bookmarkRepo *data.BookmarkRepository, postRepo *data.PostRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		// The body is optional
+		var req data.BookmarkRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+				return
+			}
+		}
+
+		// Posts outside the caller's audience look the same as missing ones
+		post, err := postRepo.GetPostByID(c.Request.Context(), postID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+				return
+			}
+			slog.Error("Failed to fetch post", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bookmark post"})
+			return
+		}
+		canView, err := postRepo.CanViewPost(c.Request.Context(), post, userID)
+		if err != nil {
+			slog.Error("Failed to check post visibility", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bookmark post"})
+			return
+		}
+		if !canView {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+
+		result, err := bookmarkRepo.Bookmark(c.Request.Context(), userID, postID, req.CollectionID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid collection_id") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark collection not found"})
+				return
+			}
+			slog.Error("BookmarkPost failed", "error", err, "post_id", postID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bookmark post"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// UnbookmarkPost handles DELETE /api/v1/posts/:id/bookmark
+// Bookmarks of posts the caller can no longer see may still be removed
+func UnbookmarkPost(bookmarkRepo *data.BookmarkRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		result, err := bookmarkRepo.Unbookmark(c.Request.Context(), userID, postID)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+				return
+			}
+			slog.Error("UnbookmarkPost failed", "error", err, "post_id", postID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove bookmark"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetBookmarks handles GET /api/v1/users/me/bookmarks
+// Posts are listed most recently saved first, from one collection when
+// collection_id is set. Bookmarks of deleted posts are removed as they are
+// found; posts outside the caller's audience are skipped but stay saved.
+func GetBookmarks(bookmarkRepo *data.BookmarkRepository, postRepo *data.PostRepository, pollRepo *data.PollRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.Pagination
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+			return
+		}
+
+		cursor, err := data.DecodeCursor(data.CursorScopeBookmarks, req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+
+		limit := data.GetDefaultLimit(req.Limit, 20, 100)
+		collectionID := c.Query("collection_id")
+
+		// Pages are keyed by when the post was saved, like liked posts
+		var posts []data.Post
+		var savedKeys []data.Keyset
+		var next *data.Keyset
+		after := cursor.Keyset
+		for round := 0; round < maxVisibleFetchRounds; round++ {
+			keys, err := bookmarkRepo.GetBookmarkKeys(c.Request.Context(), userID, collectionID, limit+1, after)
+			if err != nil {
+				if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid collection_id") {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark collection not found"})
+					return
+				}
+				slog.Error("Failed to fetch bookmarks", "error", err, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
+				return
+			}
+
+			postIDs := make([]string, len(keys))
+			keyByID := make(map[string]data.Keyset, len(keys))
+			for i, k := range keys {
+				postIDs[i] = k.ID
+				keyByID[k.ID] = k
+			}
+
+			batch, err := postRepo.GetPostsByIDs(c.Request.Context(), postIDs)
+			if err != nil {
+				slog.Error("Failed to fetch bookmarks", "error", err, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bookmarks"})
+				return
+			}
+			pruneDeletedBookmarks(c.Request.Context(), bookmarkRepo, userID, postIDs, batch)
+
+			for _, p := range postRepo.FilterVisiblePosts(c.Request.Context(), batch, userID) {
+				posts = append(posts, p)
+				savedKeys = append(savedKeys, keyByID[p.ID])
+			}
+
+			if len(posts) > limit {
+				posts = posts[:limit]
+				next = &savedKeys[limit-1]
+				break
+			}
+			if len(keys) < limit+1 {
+				break
+			}
+			after = keys[len(keys)-1]
+			if round == maxVisibleFetchRounds-1 {
+				next = &after
+			}
+		}
+
+		var nextCursor string
+		if next != nil {
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeBookmarks,
+				Keyset: *next,
+			})
+		}
+
+		EnrichPosts(c.Request.Context(), posts, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, userID, store)
+
+		c.JSON(http.StatusOK, data.PaginatedResponse{
+			Data:       posts,
+			Count:      len(posts),
+			HasMore:    next != nil,
+			NextCursor: nextCursor,
+		})
+	}
+}
+
+// pruneDeletedBookmarks removes the bookmarks of postIDs missing from found,
+// the posts that have been deleted or have expired
+func pruneDeletedBookmarks(ctx context.Context, bookmarkRepo *data.BookmarkRepository, userID string, postIDs []string, found []data.Post) {
+	present := make(map[string]bool, len(found))
+	for _, p := range found {
+		present[p.ID] = true
+	}
+
+	var deleted []string
+	for _, id := range postIDs {
+		if !present[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	if len(deleted) == 0 {
+		return
+	}
+
+	if err := bookmarkRepo.RemoveBookmarks(ctx, userID, deleted); err != nil {
+		slog.Warn("Failed to remove bookmarks of deleted posts", "error", err, "user_id", userID)
+	}
+}
+
+// CreateBookmarkCollection handles POST /api/v1/users/me/bookmark-collections
+func CreateBookmarkCollection(bookmarkRepo *data.BookmarkRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.BookmarkCollectionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		collection, err := bookmarkRepo.CreateCollection(c.Request.Context(), userID, req.Name)
+		if err != nil {
+			respondBookmarkCollectionError(c, err, userID, "Failed to create bookmark collection")
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"collection": collection})
+	}
+}
+
+// GetBookmarkCollections handles GET /api/v1/users/me/bookmark-collections
+func GetBookmarkCollections(bookmarkRepo *data.BookmarkRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		collections, err := bookmarkRepo.GetCollections(c.Request.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get bookmark collections", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get bookmark collections"})
+			return
+		}
+
+		if collections == nil {
+			collections = []data.BookmarkCollection{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"collections": collections,
+			"count":       len(collections),
+		})
+	}
+}
+
+// RenameBookmarkCollection handles PUT /api/v1/users/me/bookmark-collections/:id
+func RenameBookmarkCollection(bookmarkRepo *data.BookmarkRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.BookmarkCollectionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		collection, err := bookmarkRepo.RenameCollection(c.Request.Context(), userID, c.Param("id"), req.Name)
+		if err != nil {
+			respondBookmarkCollectionError(c, err, userID, "Failed to rename bookmark collection")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"collection": collection})
+	}
+}
+
+// DeleteBookmarkCollection handles DELETE /api/v1/users/me/bookmark-collections/:id
+// The posts in the collection stay bookmarked
+func DeleteBookmarkCollection(bookmarkRepo *data.BookmarkRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		if err := bookmarkRepo.DeleteCollection(c.Request.Context(), userID, c.Param("id")); err != nil {
+			respondBookmarkCollectionError(c, err, userID, "Failed to delete bookmark collection")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Bookmark collection deleted"})
+	}
+}
+
+// respondBookmarkCollectionError writes the response for a failed change to a
+// bookmark collection
+func respondBookmarkCollectionError(c *gin.Context, err error, userID, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid collection_id"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bookmark collection not found"})
+	case strings.Contains(err.Error(), "already exists"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "too many"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		slog.Error(message, "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+)
+
+// AddCloseFriend handles POST /api/v1/users/:id/close-friend
+func AddCloseFriend(closeFriendRepo *data.CloseFriendRepository, userRepo *data.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		targetID := c.Param("id")
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		if userID == targetID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot add yourself as a close friend"})
+			return
+		}
+
+		if _, err := userRepo.GetUserByID(c.Request.Context(), targetID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		err := closeFriendRepo.AddCloseFriend(c.Request.Context(), userID, targetID)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+				return
+			}
+			slog.Error("Failed to add close friend", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to add close friend",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Added to close friends"})
+	}
+}
+
+// RemoveCloseFriend handles DELETE /api/v1/users/:id/close-friend
+func RemoveCloseFriend(closeFriendRepo *data.CloseFriendRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		targetID := c.Param("id")
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		err := closeFriendRepo.RemoveCloseFriend(c.Request.Context(), userID, targetID)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+				return
+			}
+			slog.Error("Failed to remove close friend", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to remove close friend",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Removed from close friends"})
+	}
+}
+
+// GetCloseFriends handles GET /api/v1/users/me/close-friends
+func GetCloseFriends(closeFriendRepo *data.CloseFriendRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		friends, err := closeFriendRepo.GetCloseFriends(c.Request.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get close friends", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get close friends",
+			})
+			return
+		}
+
+		if friends == nil {
+			friends = []string{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"close_friends": friends,
+			"count":         len(friends),
+		})
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gocql/gocql"
 	"time"
 
 	"social-geo-go/internal/auth"
+	"social-geo-go/internal/cache"
 	"social-geo-go/internal/data"
 	"social-geo-go/internal/notifications"
 	"social-geo-go/internal/notifications/kafka"
@@ -80,6 +82,8 @@ func enrichCommentsWithLikeInfo(ctx context.Context, comments []data.Comment, li
 			if info, ok := likeInfoMap[comments[i].ID]; ok {
 				comments[i].LikeCount = info.LikeCount
 				comments[i].IsLiked = info.IsLiked
+				comments[i].ReactionCounts = info.ReactionCounts
+				comments[i].MyReaction = info.MyReaction
 			}
 			if len(comments[i].Replies) > 0 {
 				enrich(comments[i].Replies)
@@ -90,7 +94,7 @@ func enrichCommentsWithLikeInfo(ctx context.Context, comments []data.Comment, li
 }
 
 // CreateComment handles POST /api/v1/posts/:id/comments
-func CreateComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+func CreateComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository, followRepo *data.FollowRepository, mentioner *Mentioner, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		postID := c.Param("id")
 		userID := auth.GetUserID(c)
@@ -108,6 +112,14 @@ func CreateComment(commentRepo *data.CommentRepository, postRepo *data.PostRepos
 			return
 		}
 
+		post, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to create comment")
+		if !ok {
+			return
+		}
+		if !requireCanComment(c, commentRepo, followRepo, post, userID) {
+			return
+		}
+
 		req.PostID = postID
 		req.UserID = userID
 		req.IPAddress = c.ClientIP()
@@ -128,8 +140,10 @@ func CreateComment(commentRepo *data.CommentRepository, postRepo *data.PostRepos
 		}
 
 		// Notify post author
-		post, _ := postRepo.GetPostByID(c.Request.Context(), req.PostID)
-		if post != nil && post.UserID != userID && notifDispatcher != nil {
+		if mentioner != nil {
+			comment.Mentions = mentioner.Record(c.Request.Context(), commentMentionTarget(comment), post, comment.Content, false)
+		}
+		if post.UserID != userID && notifDispatcher != nil {
 			go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
 				EventID:     gocql.TimeUUID().String(),
 				EventType:   data.NotificationTypeComment,
@@ -152,7 +166,7 @@ func CreateComment(commentRepo *data.CommentRepository, postRepo *data.PostRepos
 
 
 // EditComment handles PUT /api/v1/comments/:id
-func EditComment(commentRepo *data.CommentRepository) gin.HandlerFunc {
+func EditComment(commentRepo *data.CommentRepository, mentioner *Mentioner) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		commentID := c.Param("id")
 		userID := auth.GetUserID(c)
@@ -188,6 +202,11 @@ func EditComment(commentRepo *data.CommentRepository) gin.HandlerFunc {
 			return
 		}
 
+		// Only users newly mentioned by the edit are notified
+		if mentioner != nil {
+			comment.Mentions = mentioner.Record(c.Request.Context(), commentMentionTarget(comment), nil, comment.Content, true)
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Comment updated",
 			"comment": comment,
@@ -196,7 +215,9 @@ func EditComment(commentRepo *data.CommentRepository) gin.HandlerFunc {
 }
 
 // GetComments handles GET /api/v1/posts/:id/comments
-func GetComments(commentRepo *data.CommentRepository, userRepo *data.UserRepository, likeRepo *data.LikeRepository, store storage.MediaStore) gin.HandlerFunc {
+// sort is newest (default), oldest or top. The pinned comment leads the first
+// page in every sort.
+func GetComments(commentRepo *data.CommentRepository, postRepo *data.PostRepository, userRepo *data.UserRepository, likeRepo *data.LikeRepository, rankSnapshots *cache.RankSnapshots, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		postID := c.Param("id")
 
@@ -209,24 +230,66 @@ func GetComments(commentRepo *data.CommentRepository, userRepo *data.UserReposit
 
 		cursor := c.Query("cursor")
 
-		comments, nextCursor, hasMore, err := commentRepo.GetCommentsForPostPaginated(c.Request.Context(), postID, limit, cursor)
+		sortMode := c.DefaultQuery("sort", data.CommentSortNewest)
+		if !validCommentSort(sortMode) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "sort must be one of newest, oldest or top",
+			})
+			return
+		}
+
+		post, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to get comments")
+		if !ok {
+			return
+		}
+
+		ctx := c.Request.Context()
+		currentUserID := auth.GetUserID(c)
+		opts := commentListOptions(post, currentUserID)
+		pinned, _ := commentRepo.GetPinnedComment(ctx, postID)
+		if pinned != nil {
+			opts.PinnedID = pinned.ID
+		}
+
+		var comments []data.Comment
+		var nextCursor string
+		var hasMore bool
+		var err error
+		if sortMode == data.CommentSortTop {
+			comments, nextCursor, hasMore, err = getTopComments(ctx, commentRepo, likeRepo, rankSnapshots, postID, opts, limit, cursor)
+		} else {
+			comments, nextCursor, hasMore, err = commentRepo.GetCommentsForPostSorted(ctx, postID, sortMode, opts, limit, cursor)
+		}
 		if err != nil {
+			// A cursor from another sort is rejected
+			if strings.Contains(err.Error(), "invalid cursor") {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid cursor",
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "Failed to get comments",
 			})
 			return
 		}
 
+		if pinned != nil && cursor == "" {
+			lead := []data.Comment{*pinned}
+			commentRepo.AttachReplies(ctx, lead, opts)
+			comments = append(lead, comments...)
+		}
+
 		// Enrich
-		ctx := c.Request.Context()
-		currentUserID := auth.GetUserID(c)
 		enrichCommentsWithUserInfo(ctx, comments, userRepo, store)
 		enrichCommentsWithLikeInfo(ctx, comments, likeRepo, currentUserID)
+		enrichCommentsWithMentions(ctx, comments, commentRepo)
 
 		count, _ := commentRepo.GetCommentCount(ctx, postID)
 
 		c.JSON(http.StatusOK, gin.H{
 			"post_id":     postID,
+			"sort":        sortMode,
 			"total_count": count,
 			"count":       len(comments),
 			"data":        comments,
@@ -237,7 +300,7 @@ func GetComments(commentRepo *data.CommentRepository, userRepo *data.UserReposit
 }
 
 // GetReplies handles GET /api/v1/comments/:id/replies
-func GetReplies(commentRepo *data.CommentRepository, userRepo *data.UserRepository, likeRepo *data.LikeRepository, store storage.MediaStore) gin.HandlerFunc {
+func GetReplies(commentRepo *data.CommentRepository, postRepo *data.PostRepository, userRepo *data.UserRepository, likeRepo *data.LikeRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		parentID := c.Param("id")
 
@@ -250,7 +313,29 @@ func GetReplies(commentRepo *data.CommentRepository, userRepo *data.UserReposito
 
 		cursor := c.Query("cursor")
 
-		replies, nextCursor, hasMore, err := commentRepo.GetRepliesForComment(c.Request.Context(), parentID, limit, cursor)
+		ctx := c.Request.Context()
+		currentUserID := auth.GetUserID(c)
+
+		// Replies on posts outside the caller's audience look the same as
+		// missing ones
+		parent, err := commentRepo.GetCommentByID(ctx, parentID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get replies",
+			})
+			return
+		}
+		post, ok := requireVisiblePost(c, postRepo, parent.PostID, "Comment not found", "Failed to get replies")
+		if !ok {
+			return
+		}
+		opts := commentListOptions(post, currentUserID)
+
+		replies, nextCursor, hasMore, err := commentRepo.GetRepliesForComment(ctx, parentID, opts, limit, cursor)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "Failed to get replies",
@@ -259,10 +344,9 @@ func GetReplies(commentRepo *data.CommentRepository, userRepo *data.UserReposito
 		}
 
 		// Enrich
-		ctx := c.Request.Context()
-		currentUserID := auth.GetUserID(c)
 		enrichCommentsWithUserInfo(ctx, replies, userRepo, store)
 		enrichCommentsWithLikeInfo(ctx, replies, likeRepo, currentUserID)
+		enrichCommentsWithMentions(ctx, replies, commentRepo)
 
 		c.JSON(http.StatusOK, gin.H{
 			"parent_id":   parentID,
@@ -275,7 +359,7 @@ func GetReplies(commentRepo *data.CommentRepository, userRepo *data.UserReposito
 }
 
 // ReplyToComment handles POST /api/v1/comments/:id/reply
-func ReplyToComment(commentRepo *data.CommentRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+func ReplyToComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository, followRepo *data.FollowRepository, mentioner *Mentioner, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		parentID := c.Param("id")
 		userID := auth.GetUserID(c)
@@ -292,10 +376,18 @@ func ReplyToComment(commentRepo *data.CommentRepository, notifDispatcher *notifi
 			return
 		}
 
+		post, ok := requireVisiblePost(c, postRepo, parent.PostID, "Parent comment not found", "Failed to create reply")
+		if !ok {
+			return
+		}
+
 		if parent.IsDeleted {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot reply to a deleted comment"})
 			return
 		}
+		if !requireCanComment(c, commentRepo, followRepo, post, userID) {
+			return
+		}
 
 		var req data.CreateCommentRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -323,6 +415,10 @@ func ReplyToComment(commentRepo *data.CommentRepository, notifDispatcher *notifi
 			return
 		}
 
+		if mentioner != nil {
+			comment.Mentions = mentioner.Record(c.Request.Context(), commentMentionTarget(comment), nil, comment.Content, false)
+		}
+
 		// Notify parent comment author
 		if parent.UserID != userID && notifDispatcher != nil {
 			go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// maxCommentContextSiblings bounds the siblings returned on each side of a
+// comment by GetCommentContext
+const maxCommentContextSiblings = 20
+
+// GetCommentContext handles GET /api/v1/comments/:id/context
+// Returns a comment with its ancestors, its nearest siblings and its post, so
+// a notification about the comment can open the thread at its position
+func GetCommentContext(commentRepo *data.CommentRepository, postRepo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		commentID := c.Param("id")
+		ctx := c.Request.Context()
+		currentUserID := auth.GetUserID(c)
+
+		siblings := 3
+		if s := c.Query("siblings"); s != "" {
+			parsed, err := strconv.Atoi(s)
+			if err != nil || parsed < 0 || parsed > maxCommentContextSiblings {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "siblings must be between 0 and 20",
+				})
+				return
+			}
+			siblings = parsed
+		}
+
+		comment, err := commentRepo.GetCommentByID(ctx, commentID)
+		if err != nil {
+			commentContextError(c, err, commentID)
+			return
+		}
+
+		// Comments on posts outside the caller's audience look the same as
+		// missing ones
+		post, err := postRepo.GetPostByID(ctx, comment.PostID)
+		if err != nil {
+			commentContextError(c, err, commentID)
+			return
+		}
+		canView, err := postRepo.CanViewPost(ctx, post, currentUserID)
+		if err != nil {
+			commentContextError(c, err, commentID)
+			return
+		}
+		if !canView {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return
+		}
+
+		opts := data.CommentListOptions{ViewerID: currentUserID, PostAuthorID: post.UserID}
+		thread, err := commentRepo.GetCommentContext(ctx, commentID, opts, siblings)
+		if err != nil {
+			commentContextError(c, err, commentID)
+			return
+		}
+
+		// Enrich every comment of the thread together
+		all := []data.Comment{thread.Comment}
+		all = append(all, thread.Ancestors...)
+		all = append(all, thread.Before...)
+		all = append(all, thread.After...)
+
+		enrichCommentsWithUserInfo(ctx, all, userRepo, store)
+		enrichCommentsWithLikeInfo(ctx, all, likeRepo, currentUserID)
+		enrichCommentsWithMentions(ctx, all, commentRepo)
+
+		pinnedID, _ := commentRepo.GetPinnedCommentID(ctx, post.ID)
+		for i := range all {
+			all[i].IsPinned = pinnedID != "" && all[i].ID == pinnedID
+			// Masked ancestors don't reveal who they mention
+			if all[i].Content == data.HiddenCommentContent && all[i].IsHidden {
+				all[i].Mentions = nil
+			}
+		}
+
+		thread.Comment = all[0]
+		rest := all[1:]
+		thread.Ancestors, rest = rest[:len(thread.Ancestors)], rest[len(thread.Ancestors):]
+		thread.Before, rest = rest[:len(thread.Before)], rest[len(thread.Before):]
+		thread.After = rest
+
+		post = enrichPostDetails(ctx, post, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
+		if author, err := userRepo.GetUserByID(ctx, post.UserID); err == nil && author != nil {
+			post.Username = author.Username
+			post.ProfilePictureURL = storage.ResolveMediaURL(store, author.ProfilePictureURL)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"comment":         thread.Comment,
+			"ancestors":       nonNilComments(thread.Ancestors),
+			"siblings_before": nonNilComments(thread.Before),
+			"siblings_after":  nonNilComments(thread.After),
+			"has_more_before": thread.HasMoreBefore,
+			"has_more_after":  thread.HasMoreAfter,
+			"post":            resolvePostForResponse(store, post),
+		})
+	}
+}
+
+// commentContextError maps an error loading a comment's context to a response
+func commentContextError(c *gin.Context, err error, commentID string) {
+	if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	slog.Error("Failed to fetch comment context", "error", err, "comment_id", commentID)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment"})
+}
+
+// nonNilComments renders a missing list as []
+func nonNilComments(comments []data.Comment) []data.Comment {
+	if comments == nil {
+		return []data.Comment{}
+	}
+	return comments
+}
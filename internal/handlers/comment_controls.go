@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+)
+
+// commentRestriction returns why userID may not comment on post, or "" when
+// they may. The post author may always comment.
+func commentRestriction(ctx context.Context, commentRepo *data.CommentRepository, followRepo *data.FollowRepository, post *data.Post, userID string) (string, error) {
+	if post.UserID == userID {
+		return "", nil
+	}
+
+	settings, err := commentRepo.GetCommentSettings(ctx, post.ID)
+	if err != nil {
+		return "", err
+	}
+
+	settings.SetLocksAt(post.CreatedAt)
+	if settings.IsLocked(time.Now()) {
+		return "Comments on this post are locked", nil
+	}
+
+	switch settings.Audience {
+	case data.CommentAudienceNobody:
+		return "Comments on this post are turned off", nil
+	case data.CommentAudienceFollowers:
+		if followRepo == nil {
+			return "", nil
+		}
+		following, err := followRepo.IsFollowing(ctx, userID, post.UserID)
+		if err != nil {
+			return "", err
+		}
+		if !following {
+			return "Only people who follow the author can comment on this post", nil
+		}
+	}
+	return "", nil
+}
+
+// requireCanComment reports whether userID may comment on post, responding
+// with 403 when they may not
+func requireCanComment(c *gin.Context, commentRepo *data.CommentRepository, followRepo *data.FollowRepository, post *data.Post, userID string) bool {
+	reason, err := commentRestriction(c.Request.Context(), commentRepo, followRepo, post, userID)
+	if err != nil {
+		slog.Error("Failed to check comment settings", "error", err, "post_id", post.ID, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return false
+	}
+	if reason != "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": reason})
+		return false
+	}
+	return true
+}
+
+// requireVisiblePost loads a post the caller may read. Posts outside the
+// caller's audience look the same as missing ones: both get a 404 with
+// notFound, and other failures a 500 with failure.
+func requireVisiblePost(c *gin.Context, postRepo *data.PostRepository, postID, notFound, failure string) (*data.Post, bool) {
+	post, err := postRepo.GetPostByID(c.Request.Context(), postID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+			return nil, false
+		}
+		slog.Error("Failed to fetch post", "error", err, "post_id", postID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+		return nil, false
+	}
+	canView, err := postRepo.CanViewPost(c.Request.Context(), post, auth.GetUserID(c))
+	if err != nil {
+		slog.Error("Failed to check post visibility", "error", err, "post_id", postID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+		return nil, false
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return nil, false
+	}
+	return post, true
+}
+
+// requireVisibleComment loads a comment on a post the caller may read, the
+// same way requireVisiblePost loads a post. Comments on posts outside the
+// caller's audience get the 404 of a missing comment.
+func requireVisibleComment(c *gin.Context, commentRepo *data.CommentRepository, postRepo *data.PostRepository, commentID, failure string) (*data.Comment, bool) {
+	comment, err := commentRepo.GetCommentByID(c.Request.Context(), commentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			return nil, false
+		}
+		slog.Error("Failed to fetch comment", "error", err, "comment_id", commentID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+		return nil, false
+	}
+	if _, ok := requireVisiblePost(c, postRepo, comment.PostID, "Comment not found", failure); !ok {
+		return nil, false
+	}
+	return comment, true
+}
+
+// GetCommentSettings handles GET /api/v1/posts/:id/comment-settings
+// can_comment tells the caller whether they may comment right now
+func GetCommentSettings(commentRepo *data.CommentRepository, postRepo *data.PostRepository, followRepo *data.FollowRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		post, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to fetch comment settings")
+		if !ok {
+			return
+		}
+
+		settings, err := commentRepo.GetCommentSettings(c.Request.Context(), postID)
+		if err != nil {
+			slog.Error("Failed to fetch comment settings", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment settings"})
+			return
+		}
+		settings.SetLocksAt(post.CreatedAt)
+
+		reason, err := commentRestriction(c.Request.Context(), commentRepo, followRepo, post, userID)
+		if err != nil {
+			slog.Error("Failed to check comment settings", "error", err, "post_id", postID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment settings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"settings":    settings,
+			"can_comment": reason == "",
+		})
+	}
+}
+
+// UpdateCommentSettings handles PUT /api/v1/posts/:id/comment-settings
+// Only the post author may change who can comment
+func UpdateCommentSettings(commentRepo *data.CommentRepository, postRepo *data.PostRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.UpdateCommentSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil || !data.ValidCommentAudience(req.Audience) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "audience must be one of everyone, followers or nobody and lock_after_days between 0 and 3650",
+			})
+			return
+		}
+
+		post, ok := requirePostAuthor(c, postRepo, postID, userID)
+		if !ok {
+			return
+		}
+
+		settings, err := commentRepo.SetCommentSettings(c.Request.Context(), postID, req.Audience, req.LockAfterDays)
+		if err != nil {
+			slog.Error("Failed to update comment settings", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment settings"})
+			return
+		}
+		settings.SetLocksAt(post.CreatedAt)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Comment settings updated",
+			"settings": settings,
+		})
+	}
+}
+
+// GetCommentRevisions handles GET /api/v1/comments/:id/revisions
+// Edit and delete history of a comment, readable by the author of its post
+// and moderators
+func GetCommentRevisions(commentRepo *data.CommentRepository, postRepo *data.PostRepository, moderators map[string]bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		commentID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		comment, err := commentRepo.GetCommentByID(c.Request.Context(), commentID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+				return
+			}
+			slog.Error("Failed to fetch comment", "error", err, "comment_id", commentID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment revisions"})
+			return
+		}
+
+		if !moderators[userID] {
+			if _, ok := requirePostAuthor(c, postRepo, comment.PostID, userID); !ok {
+				return
+			}
+		}
+
+		limit := 20
+		if l := c.Query("limit"); l != "" {
+			fmt.Sscanf(l, "%d", &limit) //nolint:errcheck
+		}
+
+		revisions, err := commentRepo.GetCommentRevisions(c.Request.Context(), commentID, limit)
+		if err != nil {
+			slog.Error("Failed to fetch comment revisions", "error", err, "comment_id", commentID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment revisions"})
+			return
+		}
+		if revisions == nil {
+			revisions = []data.CommentRevision{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":  revisions,
+			"count": len(revisions),
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+)
+
+// PinCommentRequest represents the request body for pinning a comment
+type PinCommentRequest struct {
+	CommentID string `json:"comment_id" binding:"required"`
+}
+
+// requirePostAuthor returns the post if userID wrote it, responding with 404
+// or 403 when they did not
+func requirePostAuthor(c *gin.Context, postRepo *data.PostRepository, postID, userID string) (*data.Post, bool) {
+	post, err := postRepo.GetPostByID(c.Request.Context(), postID)
+	if err != nil || post == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return nil, false
+	}
+	if post.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the post author can moderate its comments"})
+		return nil, false
+	}
+	return post, true
+}
+
+// notifyCommenter tells a comment's author the post author pinned or hid it
+func notifyCommenter(notifDispatcher *notifications.NotificationDispatcher, actorID string, comment *data.Comment, eventType, message string) {
+	if notifDispatcher == nil || comment.UserID == actorID {
+		return
+	}
+	go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
+		EventID:     gocql.TimeUUID().String(),
+		EventType:   eventType,
+		ActorID:     actorID,
+		RecipientID: comment.UserID,
+		TargetType:  data.TargetTypeComment,
+		TargetID:    comment.ID,
+		Message:     message,
+		Payload: map[string]string{
+			"post_id":         comment.PostID,
+			"comment_preview": truncateText(comment.Content, 100),
+		},
+		CreatedAt: time.Now().Format(time.RFC3339),
+	})
+}
+
+// commentModerationError maps a pin or hide error to a response
+func commentModerationError(c *gin.Context, err error, action string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		slog.Error("Failed to "+action+" comment", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action + " comment"})
+	}
+}
+
+// PinComment handles PUT /api/v1/posts/:id/pinned-comment
+// The post author pins one top-level comment, replacing any earlier pin
+func PinComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req PinCommentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "comment_id is required"})
+			return
+		}
+
+		if _, ok := requirePostAuthor(c, postRepo, postID, userID); !ok {
+			return
+		}
+
+		previous, _ := commentRepo.GetPinnedCommentID(c.Request.Context(), postID)
+
+		comment, err := commentRepo.PinComment(c.Request.Context(), postID, req.CommentID)
+		if err != nil {
+			commentModerationError(c, err, "pin")
+			return
+		}
+
+		if previous != comment.ID {
+			notifyCommenter(notifDispatcher, userID, comment, data.NotificationTypeCommentPinned, "pinned your comment")
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Comment pinned",
+			"comment": comment,
+		})
+	}
+}
+
+// UnpinComment handles DELETE /api/v1/posts/:id/pinned-comment
+func UnpinComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		if _, ok := requirePostAuthor(c, postRepo, postID, userID); !ok {
+			return
+		}
+
+		if err := commentRepo.UnpinComment(c.Request.Context(), postID); err != nil {
+			commentModerationError(c, err, "unpin")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Comment unpinned"})
+	}
+}
+
+// HideComment handles POST /api/v1/comments/:id/hide
+// The post author hides a comment on their post without deleting it. It stays
+// visible to them and to the commenter.
+func HideComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+	return setCommentHidden(commentRepo, postRepo, notifDispatcher, true)
+}
+
+// UnhideComment handles DELETE /api/v1/comments/:id/hide
+func UnhideComment(commentRepo *data.CommentRepository, postRepo *data.PostRepository) gin.HandlerFunc {
+	return setCommentHidden(commentRepo, postRepo, nil, false)
+}
+
+func setCommentHidden(commentRepo *data.CommentRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher, hidden bool) gin.HandlerFunc {
+	action, message := "unhide", "Comment unhidden"
+	if hidden {
+		action, message = "hide", "Comment hidden"
+	}
+
+	return func(c *gin.Context) {
+		commentID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		existing, err := commentRepo.GetCommentByID(c.Request.Context(), commentID)
+		if err != nil {
+			commentModerationError(c, err, action)
+			return
+		}
+
+		if _, ok := requirePostAuthor(c, postRepo, existing.PostID, userID); !ok {
+			return
+		}
+
+		comment, err := commentRepo.SetCommentHidden(c.Request.Context(), commentID, hidden)
+		if err != nil {
+			commentModerationError(c, err, action)
+			return
+		}
+
+		if hidden && !existing.IsHidden {
+			notifyCommenter(notifDispatcher, userID, comment, data.NotificationTypeCommentHidden, "hid your comment")
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": message,
+			"comment": comment,
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"social-geo-go/internal/cache"
+	"social-geo-go/internal/data"
+)
+
+// maxRankedComments bounds how many comments of a post, replies included,
+// the top sort reads. Every page re-ranks the comments written before the
+// cursor's AsOf; their scores come from the first page's rank snapshot.
+const maxRankedComments = 1000
+
+// topCommentReplyWeight is how many likes one reply in a thread is worth when
+// ranking top comments
+const topCommentReplyWeight = 2
+
+// validCommentSort reports whether sortMode is a comment sort mode
+func validCommentSort(sortMode string) bool {
+	switch sortMode {
+	case data.CommentSortNewest, data.CommentSortOldest, data.CommentSortTop:
+		return true
+	}
+	return false
+}
+
+// topCommentScore ranks a top-level comment by its likes and the replies in
+// its thread
+func topCommentScore(likes, replies int64) float64 {
+	return float64(likes + topCommentReplyWeight*replies)
+}
+
+// rankedComment is a top-level comment with the score it is ordered by
+type rankedComment struct {
+	comment data.Comment
+	score   float64
+}
+
+// getTopComments returns a page of top-level comments ranked by
+// topCommentScore (DESC, then comment_id ASC), with their first replies.
+// Likes keep changing while a user scrolls, so later pages rank comments by
+// the scores the first page saved; without that snapshot, live likes are read.
+func getTopComments(ctx context.Context, commentRepo *data.CommentRepository, likeRepo *data.LikeRepository, snapshots *cache.RankSnapshots, postID string, opts data.CommentListOptions, limit int, cursorStr string) ([]data.Comment, string, bool, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	cursor, err := data.DecodeCursor(data.CursorScopeCommentsTop, cursorStr)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	// Like the ranked feeds, later pages rank the comments that existed when
+	// the first page was loaded
+	asOf := time.UnixMilli(time.Now().UnixMilli())
+	if cursor.Rank != nil {
+		asOf = cursor.Rank.AsOf
+	}
+
+	roots, replyCounts, err := commentRepo.GetCommentThreads(ctx, postID, opts, asOf, maxRankedComments)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	snapshotKey := rankSnapshotKey(data.CursorScopeCommentsTop, postID, opts.ViewerID, asOf)
+	snapshot := loadRankSnapshot(ctx, snapshots, cursor, snapshotKey)
+
+	var likeInfo map[string]data.CommentLikeInfo
+	if likeRepo != nil && snapshot == nil {
+		commentIDs := make([]string, len(roots))
+		for i, c := range roots {
+			commentIDs[i] = c.ID
+		}
+		likeInfo, _ = likeRepo.GetLikesForComments(ctx, commentIDs, "")
+	}
+
+	ranked := make([]rankedComment, 0, len(roots))
+	for _, c := range roots {
+		score := topCommentScore(likeInfo[c.ID].LikeCount, replyCounts[c.ID])
+		if snapshot != nil {
+			var ok bool
+			if score, ok = snapshot[c.ID]; !ok {
+				continue
+			}
+		}
+		ranked = append(ranked, rankedComment{comment: c, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		return data.RankBefore(ranked[i].score, ranked[i].comment.ID, ranked[j].score, ranked[j].comment.ID)
+	})
+
+	start := 0
+	if cursor.Rank != nil {
+		for start < len(ranked) && !data.RankBefore(cursor.Rank.Score, cursor.Keyset.ID, ranked[start].score, ranked[start].comment.ID) {
+			start++
+		}
+	}
+	page := ranked[start:]
+	hasMore := len(page) > limit
+	if hasMore {
+		page = page[:limit]
+	}
+
+	if cursor.Rank == nil && hasMore {
+		scores := make(map[string]float64, len(ranked))
+		for _, r := range ranked {
+			scores[r.comment.ID] = r.score
+		}
+		saveRankSnapshot(ctx, snapshots, snapshotKey, scores)
+	}
+
+	comments := make([]data.Comment, len(page))
+	for i, r := range page {
+		comments[i] = r.comment
+	}
+	commentRepo.AttachReplies(ctx, comments, opts)
+
+	var nextCursor string
+	if hasMore && len(page) > 0 {
+		last := page[len(page)-1]
+		nextCursor = data.EncodeCursor(data.Cursor{
+			Scope:  data.CursorScopeCommentsTop,
+			Keyset: data.Keyset{CreatedAt: last.comment.CreatedAt, ID: last.comment.ID},
+			Rank:   &data.CursorRank{AsOf: asOf, Score: last.score},
+		})
+	}
+
+	return comments, nextCursor, hasMore, nil
+}
+
+// commentListOptions returns the listing options for a viewer of a post's
+// comments
+func commentListOptions(post *data.Post, viewerID string) data.CommentListOptions {
+	return data.CommentListOptions{ViewerID: viewerID, PostAuthorID: post.UserID}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidCommentSort(t *testing.T) {
+	for _, sortMode := range []string{"newest", "oldest", "top"} {
+		assert.True(t, validCommentSort(sortMode), sortMode)
+	}
+	for _, sortMode := range []string{"", "best", "TOP"} {
+		assert.False(t, validCommentSort(sortMode), sortMode)
+	}
+}
+
+func TestTopCommentScore(t *testing.T) {
+	// A reply counts for more than a like
+	assert.Greater(t, topCommentScore(0, 2), topCommentScore(3, 0))
+	assert.Equal(t, float64(7), topCommentScore(3, 2))
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+)
+
+// validateDraftRequest checks the fields a draft has so far. Only scheduled
+// drafts must be complete, since nobody is around to fix them at publish time.
+func validateDraftRequest(req *data.SaveDraftRequest, userID string, publisher *PostPublisher, now time.Time) error {
+	postReq := req.PostRequest(userID)
+	if err := validatePostRequest(postReq); err != nil {
+		return err
+	}
+	if _, err := preparePostMedia(publisher.Store, userID, nil, req.MediaKeys); err != nil {
+		return &postInputError{err.Error()}
+	}
+
+	if req.PublishAt == nil {
+		return nil
+	}
+	if !req.IsComplete() {
+		return &postInputError{"Scheduled drafts need content, latitude and longitude"}
+	}
+	if !req.PublishAt.After(now) {
+		return &postInputError{"publish_at must be in the future"}
+	}
+	if req.PublishAt.After(now.Add(data.MaxScheduleAhead)) {
+		return &postInputError{"publish_at must be within 30 days"}
+	}
+	return nil
+}
+
+// respondDraftError writes the response for an error from saving a draft
+func respondDraftError(c *gin.Context, err error, action, userID string) {
+	var inputErr *postInputError
+	switch {
+	case errors.As(err, &inputErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": inputErr.Error()})
+	case errors.Is(err, data.ErrSchedulingUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scheduling posts is not available"})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Draft not found"})
+	case strings.Contains(err.Error(), "too many"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		slog.Error("Failed to "+action+" draft", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action + " draft"})
+	}
+}
+
+// CreateDraft handles POST /api/v1/posts/drafts
+func CreateDraft(draftRepo *data.DraftRepository, publisher *PostPublisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		var req data.SaveDraftRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if err := validateDraftRequest(&req, userID, publisher, time.Now()); err != nil {
+			respondDraftError(c, err, "create", userID)
+			return
+		}
+
+		// Scheduled posts are attributed to the client that scheduled them
+		req.IPAddress = c.ClientIP()
+		req.UserAgent = c.Request.UserAgent()
+
+		draft, err := draftRepo.CreateDraft(c.Request.Context(), userID, &req)
+		if err != nil {
+			respondDraftError(c, err, "create", userID)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"draft": draft})
+	}
+}
+
+// GetDrafts handles GET /api/v1/posts/drafts
+func GetDrafts(draftRepo *data.DraftRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		drafts, err := draftRepo.GetDrafts(c.Request.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get drafts", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get drafts"})
+			return
+		}
+
+		if drafts == nil {
+			drafts = []data.PostDraft{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":  drafts,
+			"count": len(drafts),
+		})
+	}
+}
+
+// GetDraft handles GET /api/v1/posts/drafts/:id
+func GetDraft(draftRepo *data.DraftRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		draft, err := draftRepo.GetDraft(c.Request.Context(), userID, c.Param("id"))
+		if err != nil {
+			respondDraftError(c, err, "get", userID)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"draft": draft})
+	}
+}
+
+// UpdateDraft handles PUT /api/v1/posts/drafts/:id. The body replaces the
+// whole draft, including its schedule: omitting publish_at unschedules it.
+func UpdateDraft(draftRepo *data.DraftRepository, publisher *PostPublisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		var req data.SaveDraftRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if err := validateDraftRequest(&req, userID, publisher, time.Now()); err != nil {
+			respondDraftError(c, err, "update", userID)
+			return
+		}
+
+		req.IPAddress = c.ClientIP()
+		req.UserAgent = c.Request.UserAgent()
+
+		draft, err := draftRepo.UpdateDraft(c.Request.Context(), userID, c.Param("id"), &req)
+		if err != nil {
+			respondDraftError(c, err, "update", userID)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"draft": draft})
+	}
+}
+
+// DeleteDraft handles DELETE /api/v1/posts/drafts/:id
+func DeleteDraft(draftRepo *data.DraftRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		if err := draftRepo.DeleteDraft(c.Request.Context(), userID, c.Param("id")); err != nil {
+			respondDraftError(c, err, "delete", userID)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Draft deleted"})
+	}
+}
+
+// PublishDraft handles POST /api/v1/posts/drafts/:id/publish. The draft is
+// published now, whether or not it was scheduled, and then deleted.
+func PublishDraft(draftRepo *data.DraftRepository, publisher *PostPublisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		draft, err := draftRepo.GetDraft(ctx, userID, c.Param("id"))
+		if err != nil {
+			respondDraftError(c, err, "publish", userID)
+			return
+		}
+
+		saved := draft.SaveRequest()
+		if !saved.IsComplete() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Drafts need content, latitude and longitude to be published"})
+			return
+		}
+
+		req := saved.PostRequest(userID)
+		req.IPAddress = c.ClientIP()
+		req.UserAgent = c.Request.UserAgent()
+
+		post, err := publishDraftOnce(ctx, draftRepo, publisher, draft, req)
+		if errors.Is(err, errDraftPublished) {
+			_ = draftRepo.DeleteDraft(ctx, userID, draft.ID)
+			c.JSON(http.StatusConflict, gin.H{"error": "Draft has already been published"})
+			return
+		}
+		if err != nil {
+			respondDraftError(c, err, "publish", userID)
+			return
+		}
+
+		if err := draftRepo.DeleteDraft(ctx, userID, draft.ID); err != nil {
+			slog.Warn("failed to delete published draft",
+				"draft_id", draft.ID,
+				"post_id", post.ID,
+				"error", err,
+			)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Post created successfully",
+			"post":    resolvePostForResponse(publisher.Store, post),
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+func TestValidateDraftRequest(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	userID := "7f1c2b8e-3a4d-4e5f-8a9b-0c1d2e3f4a5b"
+	publisher := &PostPublisher{Store: storage.NewMemoryMediaStore("http://localhost:8080/media")}
+	lat, lng := -6.2088, 106.8456
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		req     data.SaveDraftRequest
+		wantErr bool
+	}{
+		{"empty unscheduled draft", data.SaveDraftRequest{}, false},
+		{"content only", data.SaveDraftRequest{Content: "Half written"}, false},
+		{"bad visibility", data.SaveDraftRequest{Visibility: "friends"}, true},
+		{"bad expires_in", data.SaveDraftRequest{ExpiresIn: 72}, true},
+		{"foreign media key", data.SaveDraftRequest{MediaKeys: []string{"posts/someone-else/a.jpg"}}, true},
+		{"scheduled complete", data.SaveDraftRequest{Content: "Later", Latitude: &lat, Longitude: &lng, PublishAt: at(time.Hour)}, false},
+		{"scheduled without location", data.SaveDraftRequest{Content: "Later", PublishAt: at(time.Hour)}, true},
+		{"scheduled without content", data.SaveDraftRequest{Latitude: &lat, Longitude: &lng, PublishAt: at(time.Hour)}, true},
+		{"scheduled in the past", data.SaveDraftRequest{Content: "Later", Latitude: &lat, Longitude: &lng, PublishAt: at(-time.Minute)}, true},
+		{"scheduled too far ahead", data.SaveDraftRequest{Content: "Later", Latitude: &lat, Longitude: &lng, PublishAt: at(31 * 24 * time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDraftRequest(&tt.req, userID, publisher, now)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var inputErr *postInputError
+			assert.True(t, errors.As(err, &inputErr), "want a postInputError, got %v", err)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gocql/gocql"
@@ -31,10 +33,15 @@ func setupE2ERouter() *gin.Engine {
 
 	// Repositories
 	userRepo := data.NewUserRepository(testSession)
-	postRepo := data.NewPostRepository(testSession)
+	postRepo := data.NewPostRepository(testSession, nil)
 	commentRepo := data.NewCommentRepository(testSession, nil)
 	followRepo := data.NewFollowRepository(testSession)
+	timelineRepo := data.NewTimelineRepository(testSession)
 	likeRepo := data.NewLikeRepository(testSession, nil) // nil redis for tests
+	pollRepo := data.NewPollRepository(testSession, nil)
+	bookmarkRepo := data.NewBookmarkRepository(testSession)
+	mentionRepo := data.NewMentionRepository(testSession)
+	hashtagRepo := data.NewHashtagRepository(testSession)
 	notifRepo := data.NewNotificationRepository(testSession, nil)
 	notifDispatcher := notifications.NewDispatcher(nil, notifRepo, nil)
 	locRepo := data.NewLocationRepository(testSession, nil) // nil geocoder for tests
@@ -43,7 +50,26 @@ func setupE2ERouter() *gin.Engine {
 	deviceRepo := data.NewDeviceRepository(testSession)
 	resetRepo := data.NewPasswordResetRepository(testSession)
 	modRepo := data.NewModerationRepository(testSession)
+	zoneRepo := data.NewPrivacyZoneRepository(testSession)
+	draftRepo := data.NewDraftRepository(testSession, nil) // nil redis: drafts cannot be scheduled
+	mentioner := &Mentioner{
+		Users:      userRepo,
+		Posts:      postRepo,
+		Mentions:   mentionRepo,
+		Moderation: modRepo,
+		Notifier:   notifDispatcher,
+	}
+	publisher := &PostPublisher{
+		Posts:     postRepo,
+		Users:     userRepo,
+		Zones:     zoneRepo,
+		Timeline:  timelineRepo,
+		Notifier:  notifDispatcher,
+		Store:     mediaStore,
+		Mentioner: mentioner,
+	}
 	dmRepo := data.NewDMRepository(testSession)
+	moderators := map[string]bool{} // no moderators: only post authors read post and comment history
 
 	// Public routes
 	r.POST("/auth/register", Register(userRepo, nil))
@@ -57,23 +83,28 @@ func setupE2ERouter() *gin.Engine {
 	api.Use(auth.AuthRequired())
 	{
 		// Feed
-		api.GET("/feed", GetFeed(postRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, mediaStore))
+		api.GET("/feed", GetFeed(postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, nil, mediaStore))
+		api.GET("/feed/following", GetFollowingFeed(timelineRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, mediaStore))
 
 		// Profile
 		api.GET("/users/me", GetCurrentUser(userRepo, mediaStore))
-		api.PUT("/users/me", UpdateProfile(userRepo, followRepo, nil, mediaStore))
-		api.DELETE("/users/me", DeleteAccount(userRepo))
+		api.PUT("/users/me", UpdateProfile(userRepo, followRepo, timelineRepo, nil, mediaStore))
+		api.DELETE("/users/me", DeleteAccount(userRepo, nil))
 
 		// Users
-		api.GET("/users/:id", GetUser(userRepo, mediaStore))
-		api.GET("/users/username/:username", GetUserByUsername(userRepo, mediaStore))
-		api.GET("/users/:id/posts", GetUserPosts(postRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
+		api.GET("/users/:id", GetUser(userRepo, followRepo, mediaStore))
+		api.GET("/users/username/:username", GetUserByUsername(userRepo, followRepo, mediaStore))
+		api.GET("/users/:id/posts", GetUserPosts(postRepo, followRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
 
 		// Follow
-		api.POST("/users/:id/follow", FollowUser(followRepo, notifDispatcher))
+		api.POST("/users/:id/follow", FollowUser(followRepo, userRepo, timelineRepo, notifDispatcher))
 		api.DELETE("/users/:id/follow", UnfollowUser(followRepo))
-		api.GET("/users/:id/followers", GetFollowers(followRepo))
-		api.GET("/users/:id/following", GetFollowing(followRepo))
+		api.GET("/users/:id/followers", GetFollowers(followRepo, userRepo, mediaStore))
+		api.GET("/users/:id/following", GetFollowing(followRepo, userRepo, mediaStore))
+		api.GET("/users/suggestions", GetSuggestions(data.NewSuggestionRepository(testSession), userRepo, followRepo, modRepo, mediaStore))
+		api.GET("/users/me/follow-requests", GetFollowRequests(followRepo, userRepo, mediaStore))
+		api.POST("/users/me/follow-requests/:id/approve", ApproveFollowRequest(followRepo, timelineRepo, notifDispatcher))
+		api.DELETE("/users/me/follow-requests/:id", DenyFollowRequest(followRepo))
 
 		// Block/Mute
 		api.POST("/users/:id/block", BlockUser(modRepo))
@@ -82,31 +113,85 @@ func setupE2ERouter() *gin.Engine {
 		api.DELETE("/users/:id/mute", UnmuteUser(modRepo))
 		api.GET("/users/me/blocked", GetBlockedUsers(modRepo))
 		api.GET("/users/me/muted", GetMutedUsers(modRepo))
+		api.POST("/users/me/muted-words", MuteWord(modRepo))
+		api.DELETE("/users/me/muted-words/:word", UnmuteWord(modRepo))
+		api.GET("/users/me/muted-words", GetMutedWords(modRepo))
+
+		// Privacy zones
+		api.GET("/users/me/privacy-zones", GetPrivacyZones(zoneRepo))
+		api.POST("/users/me/privacy-zones", CreatePrivacyZone(zoneRepo))
+		api.DELETE("/users/me/privacy-zones/:id", DeletePrivacyZone(zoneRepo))
+
+		// Mentions
+		api.GET("/users/me/mentions", GetMentions(mentionRepo, postRepo, commentRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, modRepo, mediaStore))
+
+		// Bookmarks
+		api.GET("/users/me/bookmarks", GetBookmarks(bookmarkRepo, postRepo, pollRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
+		api.GET("/users/me/bookmark-collections", GetBookmarkCollections(bookmarkRepo))
+		api.POST("/users/me/bookmark-collections", CreateBookmarkCollection(bookmarkRepo))
+		api.PUT("/users/me/bookmark-collections/:id", RenameBookmarkCollection(bookmarkRepo))
+		api.DELETE("/users/me/bookmark-collections/:id", DeleteBookmarkCollection(bookmarkRepo))
 
 		// Posts
-		api.POST("/posts", CreatePost(postRepo, userRepo, notifDispatcher, nil, mediaStore))
-		api.GET("/posts/:id", GetPost(postRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
-		api.DELETE("/posts/:id", DeletePost(postRepo))
+		api.POST("/posts", CreatePost(publisher))
+		api.GET("/posts/:id", GetPost(postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
+		api.PUT("/posts/:id", UpdatePost(postRepo, mentioner, nil, mediaStore, time.Hour))
+		api.GET("/posts/:id/revisions", GetPostRevisions(postRepo, mediaStore))
+		api.DELETE("/posts/:id", DeletePost(postRepo, nil))
+
+		// Drafts
+		api.GET("/posts/drafts", GetDrafts(draftRepo))
+		api.POST("/posts/drafts", CreateDraft(draftRepo, publisher))
+		api.GET("/posts/drafts/:id", GetDraft(draftRepo))
+		api.PUT("/posts/drafts/:id", UpdateDraft(draftRepo, publisher))
+		api.DELETE("/posts/drafts/:id", DeleteDraft(draftRepo))
+		api.POST("/posts/drafts/:id/publish", PublishDraft(draftRepo, publisher))
 
 		// data.Post likes
-		api.POST("/posts/:id/like", LikePost(likeRepo))
+		api.POST("/posts/:id/like", LikePost(likeRepo, postRepo))
 		api.DELETE("/posts/:id/like", UnlikePost(likeRepo))
 		api.POST("/posts/:id/toggle-like", TogglePostLike(likeRepo, postRepo, notifDispatcher))
+		api.PUT("/posts/:id/reaction", SetPostReaction(likeRepo, postRepo, notifDispatcher))
+		api.DELETE("/posts/:id/reaction", RemovePostReaction(likeRepo))
+		api.GET("/posts/:id/likes", GetPostLikers(likeRepo, postRepo, userRepo, followRepo, modRepo, mediaStore))
+
+		// Reposts
+		api.POST("/posts/:id/repost", RepostPost(postRepo, timelineRepo, notifDispatcher))
+		api.DELETE("/posts/:id/repost", UnrepostPost(postRepo))
+
+		// Polls
+		api.POST("/posts/:id/poll/vote", VotePoll(pollRepo, postRepo))
+
+		// Bookmarks
+		api.POST("/posts/:id/bookmark", BookmarkPost(bookmarkRepo, postRepo))
+		api.DELETE("/posts/:id/bookmark", UnbookmarkPost(bookmarkRepo))
 
 		// Comments
-		api.POST("/posts/:id/comments", CreateComment(commentRepo, postRepo, notifDispatcher))
-		api.GET("/posts/:id/comments", GetComments(commentRepo, userRepo, likeRepo, mediaStore))
+		api.POST("/posts/:id/comments", CreateComment(commentRepo, postRepo, followRepo, mentioner, notifDispatcher))
+		api.GET("/posts/:id/comments", GetComments(commentRepo, postRepo, userRepo, likeRepo, nil, mediaStore))
+		api.PUT("/posts/:id/pinned-comment", PinComment(commentRepo, postRepo, notifDispatcher))
+		api.DELETE("/posts/:id/pinned-comment", UnpinComment(commentRepo, postRepo))
+		api.GET("/posts/:id/comment-settings", GetCommentSettings(commentRepo, postRepo, followRepo))
+		api.PUT("/posts/:id/comment-settings", UpdateCommentSettings(commentRepo, postRepo))
 
 		// data.Comment actions
-		api.POST("/comments/:id/reply", ReplyToComment(commentRepo, notifDispatcher))
-		api.POST("/comments/:id/like", LikeComment(likeRepo))
+		api.POST("/comments/:id/reply", ReplyToComment(commentRepo, postRepo, followRepo, mentioner, notifDispatcher))
+		api.POST("/comments/:id/like", LikeComment(likeRepo, commentRepo, postRepo))
 		api.DELETE("/comments/:id/like", UnlikeComment(likeRepo))
-		api.POST("/comments/:id/toggle-like", ToggleCommentLike(likeRepo, commentRepo, notifDispatcher))
+		api.POST("/comments/:id/toggle-like", ToggleCommentLike(likeRepo, commentRepo, postRepo, notifDispatcher))
+		api.PUT("/comments/:id/reaction", SetCommentReaction(likeRepo, commentRepo, postRepo, notifDispatcher))
+		api.DELETE("/comments/:id/reaction", RemoveCommentReaction(likeRepo))
+		api.GET("/comments/:id/likes", GetCommentLikers(likeRepo, commentRepo, postRepo, userRepo, followRepo, modRepo, mediaStore))
+		api.PUT("/comments/:id", EditComment(commentRepo, mentioner))
 		api.DELETE("/comments/:id", DeleteComment(commentRepo))
+		api.POST("/comments/:id/hide", HideComment(commentRepo, postRepo, notifDispatcher))
+		api.DELETE("/comments/:id/hide", UnhideComment(commentRepo, postRepo))
+		api.GET("/comments/:id/revisions", GetCommentRevisions(commentRepo, postRepo, moderators))
+		api.GET("/comments/:id/context", GetCommentContext(commentRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, mediaStore))
 
 		// Search
 		api.GET("/search/users", SearchUsers(userRepo, mediaStore))
-		api.GET("/search/posts", SearchPosts(postRepo, userRepo, likeRepo, commentRepo, mediaStore))
+		api.GET("/search/posts", SearchPosts(postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, mediaStore))
 
 		// Notifications
 		api.GET("/notifications", GetNotifications(notifRepo))
@@ -117,6 +202,13 @@ func setupE2ERouter() *gin.Engine {
 		api.DELETE("/locations/:geohash/follow", UnfollowLocation(locFollowRepo))
 		api.GET("/locations/following", GetFollowedLocations(locFollowRepo))
 
+		// Hashtags
+		api.GET("/hashtags/:tag", GetHashtag(hashtagRepo))
+		api.GET("/hashtags/:tag/posts", GetHashtagPosts(hashtagRepo, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, modRepo, mediaStore))
+		api.POST("/hashtags/:tag/follow", FollowHashtag(hashtagRepo))
+		api.DELETE("/hashtags/:tag/follow", UnfollowHashtag(hashtagRepo))
+		api.GET("/users/me/hashtags", GetFollowedHashtags(hashtagRepo))
+
 		RegisterMediaRoutes(api, &MediaHandler{Store: mediaStore})
 
 		// Upload
@@ -470,6 +562,316 @@ func TestE2E_Posts(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("Privacy Zone Coarsens Location", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/users/me/privacy-zones", map[string]interface{}{
+			"name":      "Home",
+			"latitude":  -6.3653,
+			"longitude": 106.8269,
+			"radius_m":  300,
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create zone failed: %s", w.Body.String())
+
+		// A neighbourhood post inside the city-level zone is published at city level
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"content":            "Posted from home",
+			"latitude":           -6.3654,
+			"longitude":          106.8270,
+			"location_precision": "neighbourhood",
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create post failed: %s", w.Body.String())
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		post := resp["post"].(map[string]interface{})
+		assert.Equal(t, "city", post["location_precision"])
+		assert.Len(t, post["geohash"], 4)
+
+		// Hidden posts publish no location at all
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"content":            "Somewhere",
+			"latitude":           -6.2088,
+			"longitude":          106.8456,
+			"location_precision": "hidden",
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create post failed: %s", w.Body.String())
+
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		post = resp["post"].(map[string]interface{})
+		assert.Equal(t, "hidden", post["location_precision"])
+		assert.NotContains(t, post, "geohash")
+	})
+
+	t.Run("Draft Save And Publish", func(t *testing.T) {
+		// Drafts may be incomplete until they are published
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/posts/drafts", map[string]interface{}{
+			"content": "Half written",
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create draft failed: %s", w.Body.String())
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		draftID := resp["draft"].(map[string]interface{})["id"].(string)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/drafts/"+draftID+"/publish", nil, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("PUT", "/api/v1/posts/drafts/"+draftID, map[string]interface{}{
+			"content":   "Fully written",
+			"latitude":  -6.2088,
+			"longitude": 106.8456,
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "update draft failed: %s", w.Body.String())
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/posts/drafts", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, float64(1), resp["count"])
+
+		// Scheduling needs Redis, which the test router does not have
+		w = httptest.NewRecorder()
+		req = authedRequest("PUT", "/api/v1/posts/drafts/"+draftID, map[string]interface{}{
+			"content":    "Fully written",
+			"latitude":   -6.2088,
+			"longitude":  106.8456,
+			"publish_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+		}, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/drafts/"+draftID+"/publish", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "publish draft failed: %s", w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, "Fully written", resp["post"].(map[string]interface{})["content"])
+
+		// Publishing consumes the draft
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/posts/drafts/"+draftID, nil, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Repost And Quote", func(t *testing.T) {
+		require.NotEmpty(t, postID)
+
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/posts/"+postID+"/repost", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "repost failed: %s", w.Body.String())
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, true, resp["is_reposted"])
+		assert.Equal(t, float64(1), resp["repost_count"])
+
+		// Reposting twice is a no-op
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/"+postID+"/repost", nil, token)
+		router.ServeHTTP(w, req)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, false, resp["changed"])
+		assert.Equal(t, float64(1), resp["repost_count"])
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"content":        "Quoting my own post",
+			"latitude":       -6.9175,
+			"longitude":      107.6191,
+			"quoted_post_id": postID,
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create quote post failed: %s", w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		quoteID := resp["post"].(map[string]interface{})["id"].(string)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/posts/"+quoteID, nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		quoted := resp["post"].(map[string]interface{})["quoted_post"].(map[string]interface{})
+		assert.Equal(t, postID, quoted["id"])
+		assert.Equal(t, "E2E test post content", quoted["content"])
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"content":        "Quoting nothing",
+			"latitude":       -6.2088,
+			"longitude":      106.8456,
+			"quoted_post_id": gocql.TimeUUID().String(),
+		}, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("DELETE", "/api/v1/posts/"+postID+"/repost", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, false, resp["is_reposted"])
+		assert.Equal(t, float64(0), resp["repost_count"])
+	})
+
+	t.Run("Poll Vote", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"content":   "Which café on this street is best?",
+			"latitude":  -6.2088,
+			"longitude": 106.8456,
+			"poll": map[string]interface{}{
+				"options":   []string{"Kopi Kenangan", "Tanamera"},
+				"closes_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+			},
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create poll post failed: %s", w.Body.String())
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		pollPostID := resp["post"].(map[string]interface{})["id"].(string)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/"+pollPostID+"/poll/vote", map[string]int{"option": 1}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "vote failed: %s", w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, true, resp["changed"])
+		assert.Equal(t, float64(1), resp["poll"].(map[string]interface{})["total_votes"])
+
+		// One vote per user
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/"+pollPostID+"/poll/vote", map[string]int{"option": 0}, token)
+		router.ServeHTTP(w, req)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, false, resp["changed"])
+		assert.Equal(t, float64(1), resp["poll"].(map[string]interface{})["voted_option"])
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/posts/"+pollPostID, nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		poll := resp["post"].(map[string]interface{})["poll"].(map[string]interface{})
+		assert.Equal(t, float64(1), poll["total_votes"])
+
+		// Posts without a poll cannot be voted on
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/"+postID+"/poll/vote", map[string]int{"option": 0}, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Bookmarks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/users/me/bookmark-collections", map[string]string{"name": "Coffee"}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create collection failed: %s", w.Body.String())
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		collectionID := resp["collection"].(map[string]interface{})["id"].(string)
+
+		// Collection names are unique per user
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/users/me/bookmark-collections", map[string]string{"name": "coffee"}, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"content":   "Saved for later",
+			"latitude":  -6.2088,
+			"longitude": 106.8456,
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		savedPostID := resp["post"].(map[string]interface{})["id"].(string)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/posts/"+savedPostID+"/bookmark", map[string]string{"collection_id": collectionID}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "bookmark failed: %s", w.Body.String())
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, true, resp["changed"])
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/users/me/bookmarks?collection_id="+collectionID, nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		bookmarks := resp["data"].([]interface{})
+		require.Len(t, bookmarks, 1)
+		assert.Equal(t, savedPostID, bookmarks[0].(map[string]interface{})["id"])
+		assert.Equal(t, true, bookmarks[0].(map[string]interface{})["is_bookmarked"])
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/posts/"+savedPostID, nil, token)
+		router.ServeHTTP(w, req)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, true, resp["post"].(map[string]interface{})["is_bookmarked"])
+
+		// Deleted posts drop out of bookmarks on the next read
+		w = httptest.NewRecorder()
+		req = authedRequest("DELETE", "/api/v1/posts/"+savedPostID, nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/users/me/bookmarks", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		for _, b := range resp["data"].([]interface{}) {
+			assert.NotEqual(t, savedPostID, b.(map[string]interface{})["id"])
+		}
+
+		w = httptest.NewRecorder()
+		req = authedRequest("DELETE", "/api/v1/posts/"+savedPostID+"/bookmark", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, false, resp["changed"])
+	})
+
+	t.Run("Revisions For Post Audience", func(t *testing.T) {
+		require.NotEmpty(t, postID)
+		otherToken, _ := registerAndLogin(t, router, "e2e_post_reader", "e2e_post_reader@test.com", "password123")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/posts/"+postID, map[string]interface{}{
+			"content": "E2E test post content, edited",
+		}, token))
+		require.Equal(t, http.StatusOK, w.Code, "edit post failed: %s", w.Body.String())
+
+		// The post is public, so anyone may read its history
+		for _, reader := range []string{token, otherToken} {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, authedRequest("GET", "/api/v1/posts/"+postID+"/revisions", nil, reader))
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+			revisions := resp["data"].([]interface{})
+			require.Len(t, revisions, 1)
+			assert.Equal(t, "E2E test post content", revisions[0].(map[string]interface{})["content"])
+		}
+	})
+
 	t.Run("Create data.Post Invalid Body", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		req := authedRequest("POST", "/api/v1/posts", map[string]string{
@@ -541,6 +943,56 @@ func TestE2E_Comments(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, w.Code)
 	})
 
+	t.Run("Mentions", func(t *testing.T) {
+		mentionedToken, mentionedID := registerAndLogin(t, router, "e2e_mentioned_user", "e2e_mentioned@test.com", "password123")
+
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/posts/"+postID+"/comments", map[string]string{
+			"content": "Look @e2e_mentioned_user, and @no_such_user_e2e",
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, "create comment failed: %s", w.Body.String())
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		comment := resp["comment"].(map[string]interface{})
+		mentions := comment["mentions"].([]interface{})
+		require.Len(t, mentions, 1)
+		mention := mentions[0].(map[string]interface{})
+		assert.Equal(t, mentionedID, mention["user_id"])
+		assert.Equal(t, float64(5), mention["offset"])
+		assert.Equal(t, float64(19), mention["length"])
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/users/me/mentions", nil, mentionedToken)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		items := resp["data"].([]interface{})
+		require.NotEmpty(t, items)
+		item := items[0].(map[string]interface{})
+		assert.Equal(t, "comment", item["target_type"])
+		assert.Equal(t, comment["id"], item["comment"].(map[string]interface{})["id"])
+		assert.Equal(t, postID, item["post"].(map[string]interface{})["id"])
+
+		// A deleted comment leaves the mentions tab
+		w = httptest.NewRecorder()
+		req = authedRequest("DELETE", "/api/v1/comments/"+comment["id"].(string), nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/users/me/mentions", nil, mentionedToken)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		for _, i := range resp["data"].([]interface{}) {
+			if c, ok := i.(map[string]interface{})["comment"].(map[string]interface{}); ok {
+				assert.NotEqual(t, comment["id"], c["id"])
+			}
+		}
+	})
+
 	t.Run("Delete data.Comment", func(t *testing.T) {
 		require.NotEmpty(t, commentID)
 
@@ -632,75 +1084,541 @@ func TestE2E_Likes(t *testing.T) {
 		req = authedRequest("POST", "/api/v1/comments/"+commentID+"/toggle-like", nil, token)
 		router.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusOK, w.Code)
-	})
-}
-
-// ============== FOLLOW TESTS ==============
-
-func TestE2E_Follow(t *testing.T) {
-	router := setupE2ERouter()
-	token1, _ := registerAndLogin(t, router, "e2e_follow_a", "e2e_follow_a@test.com", "password123")
-	_, userID2 := registerAndLogin(t, router, "e2e_follow_b", "e2e_follow_b@test.com", "password123")
 
-	t.Run("Follow data.User", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		req := authedRequest("POST", "/api/v1/users/"+userID2+"/follow", nil, token1)
+		// React to the comment
+		w = httptest.NewRecorder()
+		req = authedRequest("PUT", "/api/v1/comments/"+commentID+"/reaction", map[string]string{"reaction": "🔥"}, token)
 		router.ServeHTTP(w, req)
-
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
-	t.Run("Get Followers", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		req := authedRequest("GET", "/api/v1/users/"+userID2+"/followers", nil, token1)
-		router.ServeHTTP(w, req)
+	t.Run("Reactions", func(t *testing.T) {
+		react := func(reaction string) map[string]interface{} {
+			w := httptest.NewRecorder()
+			req := authedRequest("PUT", "/api/v1/posts/"+postID+"/reaction", map[string]string{"reaction": reaction}, token)
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+			return resp
+		}
 
-	t.Run("Get Following", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		req := authedRequest("GET", "/api/v1/users/"+userID2+"/following", nil, token1)
-		router.ServeHTTP(w, req)
+		resp := react("😂")
+		assert.Equal(t, "😂", resp["my_reaction"])
+		assert.Equal(t, false, resp["is_liked"])
+		counts := resp["reaction_counts"].(map[string]interface{})
+		assert.Equal(t, float64(1), counts["😂"])
+		assert.Nil(t, counts["❤️"])
 
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
+		// Reacting again with the same reaction is a no-op
+		resp = react("😂")
+		assert.Equal(t, false, resp["changed"])
+
+		// ❤️ replaces the other reaction and counts as a like
+		resp = react("❤️")
+		assert.Equal(t, true, resp["is_liked"])
+		assert.Equal(t, float64(1), resp["like_count"])
+		counts = resp["reaction_counts"].(map[string]interface{})
+		assert.Nil(t, counts["😂"])
 
-	t.Run("Unfollow data.User", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		req := authedRequest("DELETE", "/api/v1/users/"+userID2+"/follow", nil, token1)
+		req := authedRequest("GET", "/api/v1/posts/"+postID, nil, token)
 		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"my_reaction":"❤️"`)
 
-		assert.Equal(t, http.StatusOK, w.Code)
-	})
+		w = httptest.NewRecorder()
+		req = authedRequest("PUT", "/api/v1/posts/"+postID+"/reaction", map[string]string{"reaction": "👍"}, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	t.Run("Cannot Follow Self", func(t *testing.T) {
-		// Get user1's ID
-		w := httptest.NewRecorder()
-		req := authedRequest("GET", "/api/v1/users/me", nil, token1)
+		w = httptest.NewRecorder()
+		req = authedRequest("DELETE", "/api/v1/posts/"+postID+"/reaction", nil, token)
 		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"my_reaction":""`)
+	})
+
+	t.Run("Followers Post Hidden From Strangers", func(t *testing.T) {
+		strangerToken, _ := registerAndLogin(t, router, "e2e_like_stranger", "e2e_like_stranger@test.com", "password123")
 
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"user_id":    userID,
+			"content":    "Followers only",
+			"visibility": data.VisibilityFollowers,
+			"latitude":   -6.2088,
+			"longitude":  106.8456,
+		}, token))
+		require.Equal(t, http.StatusCreated, w.Code)
 		var resp map[string]interface{}
 		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
-		userID1 := resp["user"].(map[string]interface{})["id"].(string)
+		hiddenID := resp["post"].(map[string]interface{})["id"].(string)
 
 		w = httptest.NewRecorder()
-		req = authedRequest("POST", "/api/v1/users/"+userID1+"/follow", nil, token1)
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts/"+hiddenID+"/comments", map[string]string{"content": "hello"}, token))
+		require.Equal(t, http.StatusCreated, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		commentID := resp["comment"].(map[string]interface{})["id"].(string)
+
+		for _, path := range []string{
+			"/api/v1/posts/" + hiddenID + "/toggle-like",
+			"/api/v1/posts/" + hiddenID + "/like",
+			"/api/v1/comments/" + commentID + "/toggle-like",
+			"/api/v1/comments/" + commentID + "/like",
+		} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authedRequest("POST", path, nil, strangerToken))
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
 	})
 }
 
-// ============== SEARCH TESTS ==============
-
-func TestE2E_Search(t *testing.T) {
+func TestE2E_Likers(t *testing.T) {
 	router := setupE2ERouter()
-	token, userID := registerAndLogin(t, router, "e2e_searchable", "e2e_search@test.com", "password123")
+	viewerToken, viewerID := registerAndLogin(t, router, "e2e_likers_viewer", "e2e_likers_viewer@test.com", "password123")
+	friendToken, friendID := registerAndLogin(t, router, "e2e_likers_friend", "e2e_likers_friend@test.com", "password123")
+	otherToken, otherID := registerAndLogin(t, router, "e2e_likers_other", "e2e_likers_other@test.com", "password123")
+	blockedToken, blockedID := registerAndLogin(t, router, "e2e_likers_blocked", "e2e_likers_blocked@test.com", "password123")
 
-	t.Run("Search Users", func(t *testing.T) {
-		t.Skip("Skipping SearchUsers test pending full SAI test container support")
-		w := httptest.NewRecorder()
+	w := httptest.NewRecorder()
+	req := authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+		"user_id":   viewerID,
+		"content":   "Who liked this?",
+		"latitude":  -6.2088,
+		"longitude": 106.8456,
+	}, viewerToken)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	postID := created["post"].(map[string]interface{})["id"].(string)
+
+	for _, path := range []string{"/users/" + friendID + "/follow", "/users/" + blockedID + "/block"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1"+path, nil, viewerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// The friend likes first, so they would be last in like-time order
+	for _, token := range []string{friendToken, otherToken, blockedToken} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts/"+postID+"/toggle-like", map[string]bool{"like": true}, token))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	likers := func(cursor string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/posts/"+postID+"/likes?limit=1&cursor="+cursor, nil, viewerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		return resp
+	}
+
+	t.Run("Followed First Then Others Without Blocked", func(t *testing.T) {
+		// likes_by_target is written asynchronously
+		var ids []string
+		var following []bool
+		assert.Eventually(t, func() bool {
+			ids, following = nil, nil
+			cursor := ""
+			for page := 0; page < 5; page++ {
+				resp := likers(cursor)
+				for _, l := range resp["data"].([]interface{}) {
+					liker := l.(map[string]interface{})
+					ids = append(ids, liker["user_id"].(string))
+					following = append(following, liker["is_following"].(bool))
+				}
+				if resp["has_more"] != true {
+					break
+				}
+				cursor = resp["next_cursor"].(string)
+			}
+			return len(ids) == 2
+		}, 5*time.Second, 100*time.Millisecond)
+
+		assert.Equal(t, []string{friendID, otherID}, ids)
+		assert.Equal(t, []bool{true, false}, following)
+	})
+
+	t.Run("Unknown Post", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/posts/"+gocql.TimeUUID().String()+"/likes", nil, viewerToken))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestE2E_CommentSortPinHide(t *testing.T) {
+	router := setupE2ERouter()
+	authorToken, authorID := registerAndLogin(t, router, "e2e_pin_author", "e2e_pin_author@test.com", "password123")
+	commenterToken, _ := registerAndLogin(t, router, "e2e_pin_commenter", "e2e_pin_commenter@test.com", "password123")
+	viewerToken, _ := registerAndLogin(t, router, "e2e_pin_viewer", "e2e_pin_viewer@test.com", "password123")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+		"user_id":   authorID,
+		"content":   "Sort my comments",
+		"latitude":  -6.2088,
+		"longitude": 106.8456,
+	}, authorToken))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	postID := created["post"].(map[string]interface{})["id"].(string)
+
+	var commentIDs []string
+	for _, content := range []string{"first", "second", "third"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts/"+postID+"/comments", map[string]string{"content": content}, commenterToken))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		commentIDs = append(commentIDs, resp["comment"].(map[string]interface{})["id"].(string))
+	}
+
+	listComments := func(query, token string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/posts/"+postID+"/comments?"+query, nil, token))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		var ids []string
+		for _, c := range resp["data"].([]interface{}) {
+			ids = append(ids, c.(map[string]interface{})["id"].(string))
+		}
+		return ids
+	}
+
+	t.Run("Sort Orders", func(t *testing.T) {
+		assert.Equal(t, []string{commentIDs[2], commentIDs[1], commentIDs[0]}, listComments("sort=newest", viewerToken))
+		assert.Equal(t, commentIDs, listComments("sort=oldest", viewerToken))
+
+		// The oldest comment gets a like and becomes the top comment
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/comments/"+commentIDs[0]+"/like", nil, viewerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, commentIDs[0], listComments("sort=top", viewerToken)[0])
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/posts/"+postID+"/comments?sort=best", nil, viewerToken))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Only Author Pins", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/posts/"+postID+"/pinned-comment", map[string]string{"comment_id": commentIDs[1]}, commenterToken))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/posts/"+postID+"/pinned-comment", map[string]string{"comment_id": commentIDs[1]}, authorToken))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		ids := listComments("sort=oldest", viewerToken)
+		assert.Equal(t, []string{commentIDs[1], commentIDs[0], commentIDs[2]}, ids)
+	})
+
+	t.Run("Hidden From Other Viewers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/comments/"+commentIDs[2]+"/hide", nil, authorToken))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.NotContains(t, listComments("sort=oldest", viewerToken), commentIDs[2])
+		assert.Contains(t, listComments("sort=oldest", commenterToken), commentIDs[2])
+		assert.Contains(t, listComments("sort=oldest", authorToken), commentIDs[2])
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("DELETE", "/api/v1/comments/"+commentIDs[2]+"/hide", nil, authorToken))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, listComments("sort=oldest", viewerToken), commentIDs[2])
+	})
+}
+
+func TestE2E_CommentControls(t *testing.T) {
+	router := setupE2ERouter()
+	authorToken, authorID := registerAndLogin(t, router, "e2e_ctl_author", "e2e_ctl_author@test.com", "password123")
+	followerToken, _ := registerAndLogin(t, router, "e2e_ctl_follower", "e2e_ctl_follower@test.com", "password123")
+	strangerToken, _ := registerAndLogin(t, router, "e2e_ctl_stranger", "e2e_ctl_stranger@test.com", "password123")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+		"user_id":   authorID,
+		"content":   "Followers only",
+		"latitude":  -6.2088,
+		"longitude": 106.8456,
+	}, authorToken))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	postID := created["post"].(map[string]interface{})["id"].(string)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("POST", "/api/v1/users/"+authorID+"/follow", nil, followerToken))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	comment := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts/"+postID+"/comments", map[string]string{"content": "hello"}, token))
+		return w
+	}
+	setAudience := func(token, audience string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/posts/"+postID+"/comment-settings", map[string]interface{}{"audience": audience}, token))
+		return w.Code
+	}
+
+	t.Run("Only Author Changes Settings", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, setAudience(strangerToken, data.CommentAudienceNobody))
+		assert.Equal(t, http.StatusBadRequest, setAudience(authorToken, "friends"))
+	})
+
+	var followerComment map[string]interface{}
+
+	t.Run("Followers Only", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, setAudience(authorToken, data.CommentAudienceFollowers))
+
+		assert.Equal(t, http.StatusForbidden, comment(strangerToken).Code)
+
+		w := comment(followerToken)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		followerComment = resp["comment"].(map[string]interface{})
+
+		// Replies follow the same settings
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/comments/"+followerComment["id"].(string)+"/reply", map[string]string{"content": "me too"}, strangerToken))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Nobody But The Author", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, setAudience(authorToken, data.CommentAudienceNobody))
+		assert.Equal(t, http.StatusForbidden, comment(followerToken).Code)
+		assert.Equal(t, http.StatusCreated, comment(authorToken).Code)
+	})
+
+	t.Run("Revisions For Post Author", func(t *testing.T) {
+		commentID := followerComment["id"].(string)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/comments/"+commentID, map[string]string{"content": "hello, edited"}, followerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+		var edited map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &edited) //nolint:errcheck
+		assert.Equal(t, true, edited["comment"].(map[string]interface{})["edited"])
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/comments/"+commentID+"/revisions", nil, strangerToken))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/comments/"+commentID+"/revisions", nil, authorToken))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		revisions := resp["data"].([]interface{})
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "hello", revisions[0].(map[string]interface{})["content"])
+	})
+
+	t.Run("Post Outside Audience", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+			"user_id":    authorID,
+			"content":    "Only me",
+			"visibility": data.VisibilityOnlyMe,
+			"latitude":   -6.2088,
+			"longitude":  106.8456,
+		}, authorToken))
+		require.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		hiddenID := resp["post"].(map[string]interface{})["id"].(string)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts/"+hiddenID+"/comments", map[string]string{"content": "hello"}, authorToken))
+		require.Equal(t, http.StatusCreated, w.Code)
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		commentID := resp["comment"].(map[string]interface{})["id"].(string)
+
+		for _, req := range []struct{ method, path string }{
+			{"GET", "/api/v1/posts/" + hiddenID + "/comments"},
+			{"POST", "/api/v1/posts/" + hiddenID + "/comments"},
+			{"GET", "/api/v1/posts/" + hiddenID + "/comment-settings"},
+			{"GET", "/api/v1/comments/" + commentID + "/replies"},
+			{"POST", "/api/v1/comments/" + commentID + "/reply"},
+			{"PUT", "/api/v1/posts/" + hiddenID + "/reaction"},
+			{"PUT", "/api/v1/comments/" + commentID + "/reaction"},
+		} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authedRequest(req.method, req.path, map[string]string{"content": "hi", "reaction": "🔥"}, followerToken))
+			assert.Equal(t, http.StatusNotFound, w.Code, req.method+" "+req.path)
+		}
+	})
+}
+
+func TestE2E_CommentContext(t *testing.T) {
+	router := setupE2ERouter()
+	token, userID := registerAndLogin(t, router, "e2e_ctx_user", "e2e_ctx_user@test.com", "password123")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+		"user_id":   userID,
+		"content":   "Deep link me",
+		"latitude":  -6.2088,
+		"longitude": 106.8456,
+	}, token))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	postID := created["post"].(map[string]interface{})["id"].(string)
+
+	newComment := func(path, content string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", path, map[string]string{"content": content}, token))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		return resp["comment"].(map[string]interface{})["id"].(string)
+	}
+	rootID := newComment("/api/v1/posts/"+postID+"/comments", "root")
+	replyID := newComment("/api/v1/comments/"+rootID+"/reply", "reply")
+
+	t.Run("Thread Around A Reply", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/comments/"+replyID+"/context", nil, token))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, replyID, resp["comment"].(map[string]interface{})["id"])
+		assert.Equal(t, "e2e_ctx_user", resp["comment"].(map[string]interface{})["username"])
+
+		ancestors := resp["ancestors"].([]interface{})
+		require.Len(t, ancestors, 1)
+		assert.Equal(t, rootID, ancestors[0].(map[string]interface{})["id"])
+
+		post := resp["post"].(map[string]interface{})
+		assert.Equal(t, postID, post["id"])
+		assert.Equal(t, float64(2), post["comment_count"])
+	})
+
+	t.Run("Unknown Comment", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/comments/"+gocql.TimeUUID().String()+"/context", nil, token))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid Siblings", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/comments/"+replyID+"/context?siblings=500", nil, token))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// ============== FOLLOW TESTS ==============
+
+func TestE2E_Follow(t *testing.T) {
+	router := setupE2ERouter()
+	token1, userID1 := registerAndLogin(t, router, "e2e_follow_a", "e2e_follow_a@test.com", "password123")
+	_, userID2 := registerAndLogin(t, router, "e2e_follow_b", "e2e_follow_b@test.com", "password123")
+
+	t.Run("Follow data.User", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/users/"+userID2+"/follow", nil, token1)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Get Followers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/users/"+userID2+"/followers", nil, token1)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		followers := resp["data"].([]interface{})
+		require.Len(t, followers, 1)
+		follower := followers[0].(map[string]interface{})
+		assert.Equal(t, userID1, follower["user_id"])
+		assert.NotEmpty(t, follower["username"])
+		assert.Equal(t, false, follower["is_following"])
+		assert.Equal(t, false, follower["follows_you"])
+	})
+
+	t.Run("Get Followers By Prefix", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/users/"+userID2+"/followers?q=zzz_nobody", nil, token1)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, float64(0), resp["count"])
+	})
+
+	t.Run("Get Following", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/users/"+userID1+"/following", nil, token1)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		following := resp["data"].([]interface{})
+		require.Len(t, following, 1)
+		assert.Equal(t, userID2, following[0].(map[string]interface{})["user_id"])
+		assert.Equal(t, true, following[0].(map[string]interface{})["is_following"])
+	})
+
+	t.Run("Unfollow data.User", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("DELETE", "/api/v1/users/"+userID2+"/follow", nil, token1)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Cannot Follow Self", func(t *testing.T) {
+		// Get user1's ID
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/users/me", nil, token1)
+		router.ServeHTTP(w, req)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		userID1 := resp["user"].(map[string]interface{})["id"].(string)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("POST", "/api/v1/users/"+userID1+"/follow", nil, token1)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// ============== SEARCH TESTS ==============
+
+func TestE2E_Search(t *testing.T) {
+	router := setupE2ERouter()
+	token, userID := registerAndLogin(t, router, "e2e_searchable", "e2e_search@test.com", "password123")
+
+	t.Run("Search Users", func(t *testing.T) {
+		t.Skip("Skipping SearchUsers test pending full SAI test container support")
+		w := httptest.NewRecorder()
 		req := authedRequest("GET", "/api/v1/search/users?q=e2e_search", nil, token)
 		router.ServeHTTP(w, req)
 
@@ -797,6 +1715,148 @@ func TestE2E_LocationFollow(t *testing.T) {
 	})
 }
 
+// ============== HASHTAG TESTS ==============
+
+func TestE2E_Hashtags(t *testing.T) {
+	router := setupE2ERouter()
+	token, userID := registerAndLogin(t, router, "e2e_hashtag_user", "e2e_hashtag@test.com", "password123")
+
+	w := httptest.NewRecorder()
+	req := authedRequest("POST", "/api/v1/posts", map[string]interface{}{
+		"user_id":   userID,
+		"content":   "Tagged E2E post #E2ETag",
+		"latitude":  -6.2088,
+		"longitude": 106.8456,
+	}, token)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	postID := created["post"].(map[string]interface{})["id"].(string)
+
+	t.Run("Get Hashtag Posts", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/hashtags/e2etag/posts", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		posts := resp["data"].([]interface{})
+		require.Len(t, posts, 1)
+		assert.Equal(t, postID, posts[0].(map[string]interface{})["id"])
+	})
+
+	t.Run("Get Hashtag Posts Outside Radius", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/hashtags/e2etag/posts?latitude=51.5&longitude=-0.12&radius_km=10", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Empty(t, resp["data"])
+	})
+
+	t.Run("Invalid Hashtag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/hashtags/not-a-tag/posts", nil, token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Follow Hashtag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/hashtags/E2ETag/follow", nil, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/users/me/hashtags", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, float64(1), resp["count"])
+	})
+
+	t.Run("Unfollow Hashtag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("DELETE", "/api/v1/hashtags/e2etag/follow", nil, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/hashtags/e2etag", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, false, resp["is_following"])
+	})
+}
+
+// ============== MUTED WORD TESTS ==============
+
+func TestE2E_MutedWords(t *testing.T) {
+	router := setupE2ERouter()
+	token, _ := registerAndLogin(t, router, "e2e_mutedword_user", "e2e_mutedword@test.com", "password123")
+
+	t.Run("Mute Word", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/users/me/muted-words", map[string]interface{}{
+			"word": "#Spoiler",
+		}, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, "spoiler", resp["word"])
+	})
+
+	t.Run("Mute Empty Word", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("POST", "/api/v1/users/me/muted-words", map[string]interface{}{
+			"word": "#",
+		}, token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Get Muted Words", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("GET", "/api/v1/users/me/muted-words", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, []interface{}{"spoiler"}, resp["muted_words"])
+	})
+
+	t.Run("Unmute Word", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := authedRequest("DELETE", "/api/v1/users/me/muted-words/spoiler", nil, token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req = authedRequest("GET", "/api/v1/users/me/muted-words", nil, token)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, float64(0), resp["count"])
+	})
+}
+
 // ============== ERROR DETAIL LEAK PREVENTION ==============
 
 func TestE2E_NoErrorDetailsLeaked(t *testing.T) {
@@ -926,3 +1986,167 @@ func TestE2E_KeyBackup(t *testing.T) {
 		assert.Equal(t, 0, count)
 	})
 }
+
+func TestE2E_PrivateAccount(t *testing.T) {
+	router := setupE2ERouter()
+	ownerToken, ownerID := registerAndLogin(t, router, "e2e_priv_owner", "e2e_priv_owner@test.com", "password123")
+	requesterToken, requesterID := registerAndLogin(t, router, "e2e_priv_requester", "e2e_priv_requester@test.com", "password123")
+	strangerToken, strangerID := registerAndLogin(t, router, "e2e_priv_stranger", "e2e_priv_stranger@test.com", "password123")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authedRequest("PUT", "/api/v1/users/me", map[string]interface{}{"is_private": true}, ownerToken))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	getProfile := func(token string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/users/"+ownerID, nil, token))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		return resp
+	}
+	getPosts := func(token string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/users/"+ownerID+"/posts", nil, token))
+		return w.Code
+	}
+
+	t.Run("Basic Profile For Non-Followers", func(t *testing.T) {
+		resp := getProfile(strangerToken)
+		user := resp["user"].(map[string]interface{})
+		assert.Equal(t, true, user["is_private"])
+		assert.NotContains(t, user, "email")
+		assert.Equal(t, data.FollowStatusNone, resp["follow_status"])
+
+		assert.Equal(t, http.StatusForbidden, getPosts(strangerToken))
+		assert.Equal(t, http.StatusOK, getPosts(ownerToken))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/users/"+ownerID+"/followers", nil, strangerToken))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Follow Becomes Request", func(t *testing.T) {
+		for _, token := range []string{requesterToken, strangerToken} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authedRequest("POST", "/api/v1/users/"+ownerID+"/follow", nil, token))
+			require.Equal(t, http.StatusAccepted, w.Code)
+		}
+
+		assert.Equal(t, data.FollowStatusRequested, getProfile(requesterToken)["follow_status"])
+		assert.Equal(t, http.StatusForbidden, getPosts(requesterToken))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/users/me/follow-requests", nil, ownerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		assert.Equal(t, float64(2), resp["count"])
+	})
+
+	t.Run("Approve And Deny", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/users/me/follow-requests/"+requesterID+"/approve", nil, ownerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, data.FollowStatusFollowing, getProfile(requesterToken)["follow_status"])
+		assert.Equal(t, http.StatusOK, getPosts(requesterToken))
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("DELETE", "/api/v1/users/me/follow-requests/"+strangerID, nil, ownerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, data.FollowStatusNone, getProfile(strangerToken)["follow_status"])
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("DELETE", "/api/v1/users/me/follow-requests/"+strangerID, nil, ownerToken))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Going Public Approves Pending Requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/users/"+ownerID+"/follow", nil, strangerToken))
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/users/me", map[string]interface{}{"is_private": false}, ownerToken))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, data.FollowStatusFollowing, getProfile(strangerToken)["follow_status"])
+	})
+}
+
+func TestE2E_Suggestions(t *testing.T) {
+	router := setupE2ERouter()
+	token, userID := registerAndLogin(t, router, "e2e_sugg_me", "e2e_sugg_me@test.com", "password123")
+	friendToken, friendID := registerAndLogin(t, router, "e2e_sugg_friend", "e2e_sugg_friend@test.com", "password123")
+	_, fofID := registerAndLogin(t, router, "e2e_sugg_fof", "e2e_sugg_fof@test.com", "password123")
+	fanToken, fanID := registerAndLogin(t, router, "e2e_sugg_fan", "e2e_sugg_fan@test.com", "password123")
+
+	follow := func(token, id string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/users/"+id+"/follow", nil, token))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	follow(token, friendID)
+	follow(friendToken, fofID)
+	follow(fanToken, userID)
+
+	getSuggestions := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("GET", "/api/v1/users/suggestions", nil, token))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &resp) //nolint:errcheck
+		return resp
+	}
+
+	t.Run("Queued Until Computed", func(t *testing.T) {
+		resp := getSuggestions()
+		assert.Equal(t, float64(0), resp["count"])
+		assert.Nil(t, resp["computed_at"])
+	})
+
+	ctx := context.Background()
+	suggestionRepo := data.NewSuggestionRepository(testSession)
+	followRepo := data.NewFollowRepository(testSession)
+	modRepo := data.NewModerationRepository(testSession)
+	refreshQueuedSuggestions(ctx, suggestionRepo, followRepo, data.NewLocationFollowRepository(testSession), data.NewPostRepository(testSession, nil), data.NewLocationRepository(testSession, nil), modRepo)
+
+	t.Run("Ranked With Explanations", func(t *testing.T) {
+		resp := getSuggestions()
+		require.NotNil(t, resp["computed_at"])
+		suggestions := resp["data"].([]interface{})
+		require.Len(t, suggestions, 2)
+
+		fof := suggestions[0].(map[string]interface{})
+		assert.Equal(t, fofID, fof["user_id"])
+		assert.Equal(t, data.SuggestionReasonMutual, fof["reason"])
+		assert.Equal(t, "Followed by e2e_sugg_friend", fof["explanation"])
+
+		fan := suggestions[1].(map[string]interface{})
+		assert.Equal(t, fanID, fan["user_id"])
+		assert.Equal(t, "Follows you", fan["explanation"])
+	})
+
+	t.Run("Follows And Mutes Since Are Left Out", func(t *testing.T) {
+		follow(token, fofID)
+		require.NoError(t, modRepo.MuteUser(ctx, userID, fanID))
+
+		assert.Equal(t, float64(0), getSuggestions()["count"])
+	})
+
+	t.Run("Pending Follow Requests Are Left Out", func(t *testing.T) {
+		privateToken, privateID := registerAndLogin(t, router, "e2e_sugg_private", "e2e_sugg_private@test.com", "password123")
+		follow(privateToken, userID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("PUT", "/api/v1/users/me", map[string]interface{}{"is_private": true}, privateToken))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, authedRequest("POST", "/api/v1/users/"+privateID+"/follow", nil, token))
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		require.NoError(t, computeSuggestions(ctx, suggestionRepo, followRepo, nil, nil, nil, modRepo, userID))
+		assert.Equal(t, float64(0), getSuggestions()["count"])
+	})
+}
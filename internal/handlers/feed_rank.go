@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"social-geo-go/internal/cache"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/search"
+)
+
+// Feed sort modes accepted by GET /api/v1/feed
+const (
+	FeedSortRecent  = "recent"
+	FeedSortHot     = "hot"
+	FeedSortNearest = "nearest"
+)
+
+// rankedFeedPool is how many of the newest in-radius posts the hot and nearest
+// modes rank. Every page re-ranks the posts published before the cursor's
+// AsOf; hot scores come from the first page's rank snapshot.
+const rankedFeedPool = 250
+
+// feedCursorScope returns the cursor scope for a sort mode, or false if the mode is unknown
+func feedCursorScope(sortMode string) (string, bool) {
+	switch sortMode {
+	case "", FeedSortRecent:
+		return data.CursorScopeFeed, true
+	case FeedSortHot:
+		return data.CursorScopeFeedHot, true
+	case FeedSortNearest:
+		return data.CursorScopeFeedNearest, true
+	}
+	return "", false
+}
+
+// rankedPost is a feed post with the score it is ordered by (higher first)
+type rankedPost struct {
+	post  data.Post
+	score float64
+}
+
+// rankSnapshotKey identifies the scores of one ranked listing: a scope, what
+// is ranked (an origin or a post) and the viewer, as of the first page
+func rankSnapshotKey(scope, subject, viewerID string, asOf time.Time) string {
+	return fmt.Sprintf("%s:%s:%s:%d", scope, subject, viewerID, asOf.UnixMilli())
+}
+
+// loadRankSnapshot returns the scores saved by the first page of a ranked
+// listing. It returns nil on the first page and when the snapshot is gone,
+// in which case the page is ranked by live counts.
+func loadRankSnapshot(ctx context.Context, snapshots *cache.RankSnapshots, cursor data.Cursor, key string) map[string]float64 {
+	if snapshots == nil || cursor.Rank == nil {
+		return nil
+	}
+	scores, err := snapshots.GetScores(ctx, key)
+	if err != nil {
+		slog.Warn("failed to load rank snapshot", "key", key, "error", err)
+		return nil
+	}
+	return scores
+}
+
+// saveRankSnapshot keeps the first page's scores for the pages after it
+func saveRankSnapshot(ctx context.Context, snapshots *cache.RankSnapshots, key string, scores map[string]float64) {
+	if snapshots == nil {
+		return
+	}
+	if err := snapshots.SaveScores(ctx, key, scores); err != nil {
+		slog.Warn("failed to save rank snapshot", "key", key, "error", err)
+	}
+}
+
+// rankFeedPosts scores posts for the hot or nearest mode and sorts them by
+// (score DESC, post_id ASC). Nearest uses the negated distance as its score so
+// both modes share one ordering and cursor format.
+//
+// Hot scores depend on likes and comments, which keep changing while a user
+// scrolls. With a snapshot from the first page, posts keep the scores they had
+// there (posts the first page did not rank are left out); without one, the
+// live counts are read.
+func rankFeedPosts(ctx context.Context, posts []data.Post, sortMode string, asOf time.Time, snapshot map[string]float64, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository) []rankedPost {
+	var likeInfo map[string]data.PostLikeInfo
+	var commentCounts map[string]int64
+	if sortMode == FeedSortHot && snapshot == nil {
+		postIDs := make([]string, len(posts))
+		for i, p := range posts {
+			postIDs[i] = p.ID
+		}
+		if likeRepo != nil {
+			likeInfo, _ = likeRepo.GetLikesForPosts(ctx, postIDs, "")
+		}
+		if commentRepo != nil {
+			commentCounts, _ = commentRepo.GetCommentCountsForPosts(ctx, postIDs)
+		}
+	}
+
+	ranked := make([]rankedPost, 0, len(posts))
+	for _, p := range posts {
+		score := -p.Distance
+		if sortMode == FeedSortHot {
+			if snapshot != nil {
+				var ok bool
+				if score, ok = snapshot[p.ID]; !ok {
+					continue
+				}
+			} else {
+				score = search.HotScore(likeInfo[p.ID].LikeCount, commentCounts[p.ID], p.CreatedAt, asOf, p.Distance)
+			}
+		}
+		ranked = append(ranked, rankedPost{post: p, score: score})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		return data.RankBefore(ranked[i].score, ranked[i].post.ID, ranked[j].score, ranked[j].post.ID)
+	})
+	return ranked
+}
+
+// pageRankedPosts returns the ranked posts that come after the cursor position,
+// up to limit, and whether more remain.
+func pageRankedPosts(ranked []rankedPost, cursor data.Cursor, limit int) ([]rankedPost, bool) {
+	start := 0
+	if cursor.Rank != nil {
+		for start < len(ranked) && !data.RankBefore(cursor.Rank.Score, cursor.Keyset.ID, ranked[start].score, ranked[start].post.ID) {
+			start++
+		}
+	}
+
+	page := ranked[start:]
+	hasMore := len(page) > limit
+	if hasMore {
+		page = page[:limit]
+	}
+	return page, hasMore
+}
+
+// excludePostAuthors drops posts written or reposted by any of the excluded
+// (blocked or muted) users
+func excludePostAuthors(posts []data.Post, excludedUsers map[string]bool) []data.Post {
+	if len(excludedUsers) == 0 {
+		return posts
+	}
+	var filtered []data.Post
+	for _, p := range posts {
+		if !excludedUsers[p.UserID] && !excludedUsers[p.RepostedBy] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
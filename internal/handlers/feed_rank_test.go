@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"social-geo-go/internal/data"
+)
+
+func TestFeedCursorScope(t *testing.T) {
+	for sortMode, want := range map[string]string{
+		"":        data.CursorScopeFeed,
+		"recent":  data.CursorScopeFeed,
+		"hot":     data.CursorScopeFeedHot,
+		"nearest": data.CursorScopeFeedNearest,
+	} {
+		scope, ok := feedCursorScope(sortMode)
+		assert.True(t, ok, sortMode)
+		assert.Equal(t, want, scope, sortMode)
+	}
+
+	_, ok := feedCursorScope("popular")
+	assert.False(t, ok)
+}
+
+func TestRankedFeedPaging(t *testing.T) {
+	asOf := time.UnixMilli(1767609000000)
+	posts := []data.Post{
+		{ID: "a", CreatedAt: asOf.Add(-time.Minute), Distance: 3},
+		{ID: "b", CreatedAt: asOf.Add(-2 * time.Minute), Distance: 1},
+		{ID: "c", CreatedAt: asOf.Add(-3 * time.Minute), Distance: 1},
+		{ID: "d", CreatedAt: asOf.Add(-4 * time.Minute), Distance: 2},
+	}
+
+	ranked := rankFeedPosts(context.Background(), posts, FeedSortNearest, asOf, nil, nil, nil)
+	ids := make([]string, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.post.ID
+	}
+	// Equal distances fall back to post ID order
+	require.Equal(t, []string{"b", "c", "d", "a"}, ids)
+
+	var seen []string
+	cursor := data.Cursor{}
+	for {
+		page, hasMore := pageRankedPosts(ranked, cursor, 1)
+		require.Len(t, page, 1)
+		last := page[0]
+		seen = append(seen, last.post.ID)
+		if !hasMore {
+			break
+		}
+		// Round-trip through the encoded cursor, as a client would
+		encoded := data.EncodeCursor(data.Cursor{
+			Scope:  data.CursorScopeFeedNearest,
+			Keyset: data.Keyset{CreatedAt: last.post.CreatedAt, ID: last.post.ID},
+			Rank:   &data.CursorRank{AsOf: asOf, Score: last.score},
+		})
+		var err error
+		cursor, err = data.DecodeCursor(data.CursorScopeFeedNearest, encoded)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, ids, seen)
+}
+
+func TestRankedFeedSnapshot(t *testing.T) {
+	asOf := time.UnixMilli(1767609000000)
+	posts := []data.Post{
+		{ID: "a", CreatedAt: asOf.Add(-time.Minute)},
+		{ID: "b", CreatedAt: asOf.Add(-2 * time.Minute)},
+		{ID: "c", CreatedAt: asOf.Add(-3 * time.Minute)},
+	}
+
+	// Later pages keep the first page's scores, and posts it did not rank
+	// stay out
+	ranked := rankFeedPosts(context.Background(), posts, FeedSortHot, asOf, map[string]float64{"a": 0.1, "b": 0.7}, nil, nil)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "b", ranked[0].post.ID)
+	assert.Equal(t, 0.7, ranked[0].score)
+	assert.Equal(t, "a", ranked[1].post.ID)
+}
+
+func TestExcludePostAuthors(t *testing.T) {
+	posts := []data.Post{
+		{ID: "1", UserID: "alice"},
+		{ID: "2", UserID: "bob"},
+		{ID: "3", UserID: "alice", RepostedBy: "bob"},
+		{ID: "4", UserID: "carol", RepostedBy: "alice"},
+	}
+
+	var ids []string
+	for _, p := range excludePostAuthors(posts, map[string]bool{"bob": true}) {
+		ids = append(ids, p.ID)
+	}
+	// Posts written or reposted by an excluded user are both dropped
+	assert.Equal(t, []string{"1", "4"}, ids)
+	assert.Len(t, excludePostAuthors(posts, nil), 4)
+}
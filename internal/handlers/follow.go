@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gocql/gocql"
@@ -13,10 +13,13 @@ import (
 	"social-geo-go/internal/data"
 	"social-geo-go/internal/notifications"
 	"social-geo-go/internal/notifications/kafka"
+	"social-geo-go/internal/storage"
 )
 
 // FollowUser handles POST /api/v1/users/:id/follow
-func FollowUser(followRepo *data.FollowRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+// Following a private account sends a follow request instead, answered with
+// 202 until the owner approves it
+func FollowUser(followRepo *data.FollowRepository, userRepo *data.UserRepository, timelineRepo *data.TimelineRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		followerID := auth.GetUserID(c)
 		followingID := c.Param("id")
@@ -26,6 +29,29 @@ func FollowUser(followRepo *data.FollowRepository, notifDispatcher *notification
 			return
 		}
 
+		if userRepo != nil && followerID != followingID {
+			target, err := userRepo.GetUserByID(c.Request.Context(), followingID)
+			if err != nil {
+				if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+					c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+				return
+			}
+			if target.IsPrivate {
+				following, err := followRepo.IsFollowing(c.Request.Context(), followerID, followingID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				if !following {
+					requestFollow(c, followRepo, notifDispatcher, followerID, followingID)
+					return
+				}
+			}
+		}
+
 		err := followRepo.Follow(c.Request.Context(), followerID, followingID)
 		if err != nil {
 			status := http.StatusInternalServerError
@@ -38,6 +64,9 @@ func FollowUser(followRepo *data.FollowRepository, notifDispatcher *notification
 			return
 		}
 
+		// Seed the follower's home timeline with the author's recent posts
+		backfillFollowedTimeline(timelineRepo, followerID, followingID)
+
 		// Create notification for followed user
 		if notifDispatcher != nil {
 			go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
@@ -52,11 +81,39 @@ func FollowUser(followRepo *data.FollowRepository, notifDispatcher *notification
 			})
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "User followed"})
+		c.JSON(http.StatusOK, gin.H{"message": "User followed", "status": data.FollowStatusFollowing})
 	}
 }
 
+// requestFollow asks a private account for permission to follow it
+func requestFollow(c *gin.Context, followRepo *data.FollowRepository, notifDispatcher *notifications.NotificationDispatcher, followerID, followingID string) {
+	err := followRepo.RequestFollow(c.Request.Context(), followerID, followingID)
+	if err != nil && !strings.Contains(err.Error(), "already requested") {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Repeating a pending request doesn't notify the owner again
+	if err == nil && notifDispatcher != nil {
+		go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
+			EventID:     gocql.TimeUUID().String(),
+			EventType:   data.NotificationTypeFollowRequest,
+			ActorID:     followerID,
+			RecipientID: followingID,
+			TargetType:  "user",
+			TargetID:    followerID,
+			Message:     "requested to follow you",
+			CreatedAt:   time.Now().Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Follow requested", "status": data.FollowStatusRequested})
+}
+
 // UnfollowUser handles DELETE /api/v1/users/:id/follow
+// Also withdraws a pending follow request
 func UnfollowUser(followRepo *data.FollowRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		followerID := auth.GetUserID(c)
@@ -68,6 +125,13 @@ func UnfollowUser(followRepo *data.FollowRepository) gin.HandlerFunc {
 		}
 
 		err := followRepo.Unfollow(c.Request.Context(), followerID, followingID)
+		if err != nil && err.Error() == "follow relationship not found" {
+			// Withdraw a pending request to a private account instead
+			if derr := followRepo.DeleteFollowRequest(c.Request.Context(), followerID, followingID); derr == nil {
+				c.JSON(http.StatusOK, gin.H{"message": "Follow request cancelled"})
+				return
+			}
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
@@ -80,61 +144,31 @@ func UnfollowUser(followRepo *data.FollowRepository) gin.HandlerFunc {
 }
 
 // GetFollowers handles GET /api/v1/users/:id/followers
-func GetFollowers(followRepo *data.FollowRepository) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		userID := c.Param("id")
-
-		limit := 50
-		if l := c.Query("limit"); l != "" {
-			if parsed, err := strconv.Atoi(l); err == nil {
-				limit = parsed
-			}
-		}
-
-		followers, err := followRepo.GetFollowers(c.Request.Context(), userID, limit)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
-
-		counts, _ := followRepo.GetFollowCounts(c.Request.Context(), userID)
-
-		c.JSON(http.StatusOK, gin.H{
-			"user_id":   userID,
-			"count":     counts.FollowersCount,
-			"followers": followers,
-		})
-	}
+// Most recent followers first, with cursor pagination
+func GetFollowers(followRepo *data.FollowRepository, userRepo *data.UserRepository, store storage.MediaStore) gin.HandlerFunc {
+	return getFollowList(followRepo, userRepo, store, true)
 }
 
 // GetFollowing handles GET /api/v1/users/:id/following
-func GetFollowing(followRepo *data.FollowRepository) gin.HandlerFunc {
+func GetFollowing(followRepo *data.FollowRepository, userRepo *data.UserRepository, store storage.MediaStore) gin.HandlerFunc {
+	return getFollowList(followRepo, userRepo, store, false)
+}
+
+func getFollowList(followRepo *data.FollowRepository, userRepo *data.UserRepository, store storage.MediaStore, followers bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := c.Param("id")
 
-		limit := 50
-		if l := c.Query("limit"); l != "" {
-			if parsed, err := strconv.Atoi(l); err == nil {
-				limit = parsed
-			}
-		}
-
-		following, err := followRepo.GetFollowing(c.Request.Context(), userID, limit)
+		user, err := userRepo.GetUserByID(c.Request.Context(), userID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
 			})
 			return
 		}
+		if !requireProfileAccess(c, followRepo, user) {
+			return
+		}
 
-		counts, _ := followRepo.GetFollowCounts(c.Request.Context(), userID)
-
-		c.JSON(http.StatusOK, gin.H{
-			"user_id":   userID,
-			"count":     counts.FollowingCount,
-			"following": following,
-		})
+		listFollows(c, followRepo, userRepo, store, userID, followers)
 	}
 }
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// followListPage is one page of a followers or following list and the cursor
+// of the next page
+type followListPage struct {
+	users []data.FollowListUser
+	next  *data.Cursor
+}
+
+// listFollows responds with a page of userID's followers, or of the accounts
+// they follow. q keeps only usernames starting with it.
+func listFollows(c *gin.Context, followRepo *data.FollowRepository, userRepo *data.UserRepository, store storage.MediaStore, userID string, followers bool) {
+	var req data.Pagination
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid query parameters",
+		})
+		return
+	}
+
+	scope := data.CursorScopeFollowingList
+	if followers {
+		scope = data.CursorScopeFollowerList
+	}
+	cursor, err := data.DecodeCursor(scope, req.Cursor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid cursor",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	limit := data.GetDefaultLimit(req.Limit, 20, 100)
+	prefix := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(c.Query("q")), "@"))
+
+	page, err := collectFollowList(ctx, followRepo, userRepo, store, userID, followers, cursor, prefix, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid user ID",
+			})
+			return
+		}
+		slog.Error("Failed to fetch follow list", "error", err, "user_id", userID, "followers", followers)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch follow list",
+		})
+		return
+	}
+
+	// Relationship flags are relative to the caller
+	if viewerID := auth.GetUserID(c); viewerID != "" && len(page.users) > 0 {
+		ids := make([]string, len(page.users))
+		for i, u := range page.users {
+			ids[i] = u.UserID
+		}
+		following, err := followRepo.GetFollowingAmong(ctx, viewerID, ids)
+		if err != nil {
+			slog.Warn("Failed to check following", "error", err, "user_id", viewerID)
+		}
+		followedBy, err := followRepo.GetFollowersAmong(ctx, viewerID, ids)
+		if err != nil {
+			slog.Warn("Failed to check followers", "error", err, "user_id", viewerID)
+		}
+		for i := range page.users {
+			page.users[i].IsFollowing = following[page.users[i].UserID]
+			page.users[i].FollowsYou = followedBy[page.users[i].UserID]
+		}
+	}
+
+	var nextCursor string
+	if page.next != nil {
+		nextCursor = data.EncodeCursor(*page.next)
+	}
+
+	c.JSON(http.StatusOK, data.PaginatedResponse{
+		Data:       page.users,
+		Count:      len(page.users),
+		HasMore:    page.next != nil,
+		NextCursor: nextCursor,
+	})
+}
+
+// collectFollowList reads a page of a follow list starting at cursor.
+// Followers come most recent first; following comes in following_id order.
+// Refills like collectPosts when deleted accounts or the prefix filter thin
+// out a batch.
+func collectFollowList(ctx context.Context, followRepo *data.FollowRepository, userRepo *data.UserRepository, store storage.MediaStore, userID string, followers bool, cursor data.Cursor, prefix string, limit int) (*followListPage, error) {
+	page := &followListPage{users: make([]data.FollowListUser, 0, limit)}
+	var keys []data.Keyset
+
+	after := cursor.Keyset
+	for round := 0; round < maxVisibleFetchRounds; round++ {
+		var batch []data.Keyset
+		var err error
+		if followers {
+			batch, err = followRepo.GetFollowerKeys(ctx, userID, limit+1, after)
+		} else {
+			batch, err = followRepo.GetFollowingKeys(ctx, userID, limit+1, after.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, len(batch))
+		for i, k := range batch {
+			ids[i] = k.ID
+		}
+		users, _ := userRepo.GetUsersByIDs(ctx, ids)
+
+		for _, k := range batch {
+			// Deleted accounts are skipped
+			info, ok := users[k.ID]
+			if !ok || !strings.HasPrefix(strings.ToLower(info.Username), prefix) {
+				continue
+			}
+			keys = append(keys, k)
+			page.users = append(page.users, data.FollowListUser{
+				UserID:            k.ID,
+				Username:          info.Username,
+				ProfilePictureURL: storage.ResolveMediaURL(store, info.ProfilePictureURL),
+				FollowedAt:        k.CreatedAt,
+			})
+		}
+
+		if len(page.users) > limit {
+			page.users = page.users[:limit]
+			page.next = &data.Cursor{Scope: cursor.Scope, Keyset: keys[limit-1]}
+			break
+		}
+		if len(batch) < limit+1 {
+			break
+		}
+		after = batch[len(batch)-1]
+		if round == maxVisibleFetchRounds-1 {
+			page.next = &data.Cursor{Scope: cursor.Scope, Keyset: after}
+		}
+	}
+
+	return page, nil
+}
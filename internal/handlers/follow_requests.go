@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+	"social-geo-go/internal/storage"
+)
+
+// followStatus returns whether viewerID follows targetID, has asked to, or
+// neither
+func followStatus(ctx context.Context, followRepo *data.FollowRepository, viewerID, targetID string) string {
+	if viewerID == "" || viewerID == targetID || followRepo == nil {
+		return data.FollowStatusNone
+	}
+	if following, err := followRepo.IsFollowing(ctx, viewerID, targetID); err == nil && following {
+		return data.FollowStatusFollowing
+	}
+	if requested, err := followRepo.HasRequestedFollow(ctx, viewerID, targetID); err == nil && requested {
+		return data.FollowStatusRequested
+	}
+	return data.FollowStatusNone
+}
+
+// canViewProfile reports whether viewerID may see more than the basic
+// profile of user: their posts, likes and follow lists
+func canViewProfile(ctx context.Context, followRepo *data.FollowRepository, user *data.User, viewerID string) (bool, error) {
+	if !user.IsPrivate || viewerID == user.ID {
+		return true, nil
+	}
+	if viewerID == "" || followRepo == nil {
+		return false, nil
+	}
+	return followRepo.IsFollowing(ctx, viewerID, user.ID)
+}
+
+// requireProfileAccess reports whether the caller may see more than the
+// basic profile of user, responding with 403 when they may not
+func requireProfileAccess(c *gin.Context, followRepo *data.FollowRepository, user *data.User) bool {
+	allowed, err := canViewProfile(c.Request.Context(), followRepo, user, auth.GetUserID(c))
+	if err != nil {
+		slog.Error("Failed to check profile access", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":      "This account is private",
+			"is_private": true,
+		})
+		return false
+	}
+	return true
+}
+
+// basicProfile is what non-followers see of a private account
+func basicProfile(user *data.User) gin.H {
+	return gin.H{
+		"id":                  user.ID,
+		"username":            user.Username,
+		"full_name":           user.FullName,
+		"bio":                 user.Bio,
+		"profile_picture_url": user.ProfilePictureURL,
+		"cover_image_url":     user.CoverImageURL,
+		"is_private":          user.IsPrivate,
+		"created_at":          user.CreatedAt,
+	}
+}
+
+// respondWithUserProfile writes user as seen by the caller, along with
+// whether the caller follows them
+func respondWithUserProfile(c *gin.Context, followRepo *data.FollowRepository, user *data.User) {
+	ctx := c.Request.Context()
+	viewerID := auth.GetUserID(c)
+	status := followStatus(ctx, followRepo, viewerID, user.ID)
+
+	allowed, err := canViewProfile(ctx, followRepo, user, viewerID)
+	if err != nil {
+		slog.Error("Failed to check profile access", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusOK, gin.H{
+			"user":          basicProfile(user),
+			"follow_status": status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":          user,
+		"follow_status": status,
+	})
+}
+
+// GetFollowRequests handles GET /api/v1/users/me/follow-requests
+// Pending requests to follow the current user, newest first
+func GetFollowRequests(followRepo *data.FollowRepository, userRepo *data.UserRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.Pagination
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid query parameters",
+			})
+			return
+		}
+
+		cursor, err := data.DecodeCursor(data.CursorScopeFollowRequests, req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor",
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		limit := data.GetDefaultLimit(req.Limit, 20, 100)
+
+		requests, err := followRepo.GetFollowRequests(ctx, userID, limit+1, cursor.Keyset)
+		if err != nil {
+			slog.Error("Failed to fetch follow requests", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch follow requests",
+			})
+			return
+		}
+
+		hasMore := len(requests) > limit
+		if hasMore {
+			requests = requests[:limit]
+		}
+
+		var nextCursor string
+		if hasMore {
+			last := requests[len(requests)-1]
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeFollowRequests,
+				Keyset: data.Keyset{CreatedAt: last.RequestedAt, ID: last.UserID},
+			})
+		}
+
+		userIDs := make([]string, len(requests))
+		for i, r := range requests {
+			userIDs[i] = r.UserID
+		}
+		users, _ := userRepo.GetUsersByIDs(ctx, userIDs)
+
+		// Requests from deleted accounts are skipped
+		result := make([]data.FollowRequest, 0, len(requests))
+		for _, r := range requests {
+			info, ok := users[r.UserID]
+			if !ok {
+				continue
+			}
+			r.Username = info.Username
+			r.ProfilePictureURL = storage.ResolveMediaURL(store, info.ProfilePictureURL)
+			result = append(result, r)
+		}
+
+		c.JSON(http.StatusOK, data.PaginatedResponse{
+			Data:       result,
+			Count:      len(result),
+			HasMore:    hasMore,
+			NextCursor: nextCursor,
+		})
+	}
+}
+
+// ApproveFollowRequest handles POST /api/v1/users/me/follow-requests/:id/approve
+// :id is the user who asked to follow
+func ApproveFollowRequest(followRepo *data.FollowRepository, timelineRepo *data.TimelineRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		requesterID := c.Param("id")
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		if err := followRepo.AcceptFollowRequest(c.Request.Context(), requesterID, userID); err != nil {
+			followRequestError(c, err, "approve")
+			return
+		}
+
+		backfillFollowedTimeline(timelineRepo, requesterID, userID)
+
+		if notifDispatcher != nil {
+			go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
+				EventID:     gocql.TimeUUID().String(),
+				EventType:   data.NotificationTypeFollowAccepted,
+				ActorID:     userID,
+				RecipientID: requesterID,
+				TargetType:  "user",
+				TargetID:    userID,
+				Message:     "accepted your follow request",
+				CreatedAt:   time.Now().Format(time.RFC3339),
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Follow request approved"})
+	}
+}
+
+// DenyFollowRequest handles DELETE /api/v1/users/me/follow-requests/:id
+// The requester is not told
+func DenyFollowRequest(followRepo *data.FollowRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		requesterID := c.Param("id")
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		if err := followRepo.DeleteFollowRequest(c.Request.Context(), requesterID, userID); err != nil {
+			followRequestError(c, err, "deny")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Follow request denied"})
+	}
+}
+
+// followRequestError maps a follow request error to a response
+func followRequestError(c *gin.Context, err error, action string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Follow request not found"})
+	case strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	default:
+		slog.Error("Failed to "+action+" follow request", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action + " follow request"})
+	}
+}
+
+// backfillFollowedTimeline seeds a new follower's home timeline with the
+// author's recent posts
+func backfillFollowedTimeline(timelineRepo *data.TimelineRepository, followerID, followingID string) {
+	if timelineRepo == nil {
+		return
+	}
+	go func() {
+		if err := timelineRepo.BackfillFromAuthor(context.Background(), followerID, followingID, 20); err != nil {
+			slog.Warn("Failed to backfill home timeline", "error", err, "follower_id", followerID, "following_id", followingID)
+		}
+	}()
+}
+
+// acceptPendingFollowRequests approves every pending request of a user who
+// made their account public
+func acceptPendingFollowRequests(ctx context.Context, followRepo *data.FollowRepository, timelineRepo *data.TimelineRepository, userID string) {
+	for {
+		requests, err := followRepo.GetFollowRequests(ctx, userID, 100, data.Keyset{})
+		if err != nil {
+			slog.Warn("Failed to list follow requests", "error", err, "user_id", userID)
+			return
+		}
+		if len(requests) == 0 {
+			return
+		}
+		// A failed request stays pending and would be listed again, so finish
+		// the page and stop rather than retrying it forever
+		failed := false
+		for _, r := range requests {
+			if err := followRepo.AcceptFollowRequest(ctx, r.UserID, userID); err != nil {
+				slog.Warn("Failed to accept follow request", "error", err, "user_id", userID, "requester_id", r.UserID)
+				failed = true
+				continue
+			}
+			backfillFollowedTimeline(timelineRepo, r.UserID, userID)
+		}
+		if failed {
+			return
+		}
+	}
+}
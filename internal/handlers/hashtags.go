@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// maxHashtagRadiusKM bounds the geo filter of a hashtag page
+const maxHashtagRadiusKM = 500
+
+// hashtagPostsRequest represents query parameters for a hashtag page. The
+// location is optional; with it, only posts within radius_km are listed.
+type hashtagPostsRequest struct {
+	Latitude  *float64 `form:"latitude"`
+	Longitude *float64 `form:"longitude"`
+	RadiusKM  float64  `form:"radius_km"`
+	Limit     int      `form:"limit"`
+	Cursor    string   `form:"cursor"`
+}
+
+// hashtagParam reads the :tag path parameter, writing a 400 response if it
+// is not a valid hashtag
+func hashtagParam(c *gin.Context) (string, bool) {
+	tag, err := data.NormalizeHashtag(c.Param("tag"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hashtag"})
+		return "", false
+	}
+	return tag, true
+}
+
+// GetHashtagPosts handles GET /api/v1/hashtags/:tag/posts
+func GetHashtagPosts(hashtagRepo *data.HashtagRepository, postRepo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag, ok := hashtagParam(c)
+		if !ok {
+			return
+		}
+
+		var req hashtagPostsRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+			return
+		}
+
+		var near *data.GeoFilter
+		if req.Latitude != nil || req.Longitude != nil {
+			if req.Latitude == nil || req.Longitude == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "latitude and longitude must be given together"})
+				return
+			}
+			if *req.Latitude < -90 || *req.Latitude > 90 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Latitude must be between -90 and 90"})
+				return
+			}
+			if *req.Longitude < -180 || *req.Longitude > 180 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Longitude must be between -180 and 180"})
+				return
+			}
+			if req.RadiusKM <= 0 {
+				req.RadiusKM = 10
+			}
+			if req.RadiusKM > maxHashtagRadiusKM {
+				req.RadiusKM = maxHashtagRadiusKM
+			}
+			near = &data.GeoFilter{Latitude: *req.Latitude, Longitude: *req.Longitude, RadiusKM: req.RadiusKM}
+		}
+
+		cursor, err := data.DecodeCursor(data.CursorScopeHashtag, req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+
+		limit := data.GetDefaultLimit(req.Limit, 20, 100)
+		currentUserID := auth.GetUserID(c)
+
+		excluded := make(map[string]bool)
+		if modRepo != nil && currentUserID != "" {
+			if ex, err := modRepo.GetBlockedAndMutedUsers(c.Request.Context(), currentUserID); err == nil {
+				excluded = ex
+			}
+		}
+
+		var posts []data.Post
+		var postKeys []data.Keyset
+		var next *data.Keyset
+		after := cursor.Keyset
+		for round := 0; round < maxVisibleFetchRounds; round++ {
+			keys, resume, err := hashtagRepo.GetHashtagPostKeys(c.Request.Context(), tag, limit+1, after, near)
+			if err != nil {
+				slog.Error("Failed to fetch hashtag posts", "error", err, "hashtag", tag)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+				return
+			}
+
+			postIDs := make([]string, len(keys))
+			keyByID := make(map[string]data.Keyset, len(keys))
+			for i, k := range keys {
+				postIDs[i] = k.ID
+				keyByID[k.ID] = k
+			}
+
+			batch, err := postRepo.GetPostsByIDs(c.Request.Context(), postIDs)
+			if err != nil {
+				slog.Error("Failed to fetch hashtag posts", "error", err, "hashtag", tag)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+				return
+			}
+
+			for _, p := range postRepo.FilterVisiblePosts(c.Request.Context(), batch, currentUserID) {
+				if excluded[p.UserID] {
+					continue
+				}
+				posts = append(posts, p)
+				postKeys = append(postKeys, keyByID[p.ID])
+			}
+
+			if len(posts) > limit {
+				posts = posts[:limit]
+				next = &postKeys[limit-1]
+				break
+			}
+			if resume.IsZero() {
+				break
+			}
+			after = resume
+			if round == maxVisibleFetchRounds-1 {
+				next = &after
+			}
+		}
+
+		var nextCursor string
+		if next != nil {
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeHashtag,
+				Keyset: *next,
+			})
+		}
+
+		EnrichPosts(c.Request.Context(), posts, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
+
+		if posts == nil {
+			posts = []data.Post{}
+		}
+		c.JSON(http.StatusOK, data.PaginatedResponse{
+			Data:       posts,
+			Count:      len(posts),
+			HasMore:    next != nil,
+			NextCursor: nextCursor,
+		})
+	}
+}
+
+// GetHashtag handles GET /api/v1/hashtags/:tag
+func GetHashtag(hashtagRepo *data.HashtagRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag, ok := hashtagParam(c)
+		if !ok {
+			return
+		}
+
+		following := false
+		if userID := auth.GetUserID(c); userID != "" {
+			var err error
+			following, err = hashtagRepo.IsFollowingHashtag(c.Request.Context(), userID, tag)
+			if err != nil {
+				slog.Error("Failed to check hashtag follow", "error", err, "hashtag", tag, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hashtag"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"hashtag":      tag,
+			"is_following": following,
+		})
+	}
+}
+
+// FollowHashtag handles POST /api/v1/hashtags/:tag/follow
+func FollowHashtag(hashtagRepo *data.HashtagRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		tag, ok := hashtagParam(c)
+		if !ok {
+			return
+		}
+
+		follow, err := hashtagRepo.FollowHashtag(c.Request.Context(), userID, tag)
+		if err != nil {
+			if strings.Contains(err.Error(), "too many") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You can follow at most %d hashtags", data.MaxFollowedHashtags)})
+				return
+			}
+			slog.Error("Failed to follow hashtag", "error", err, "hashtag", tag, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow hashtag"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Hashtag followed",
+			"follow":  follow,
+		})
+	}
+}
+
+// UnfollowHashtag handles DELETE /api/v1/hashtags/:tag/follow
+func UnfollowHashtag(hashtagRepo *data.HashtagRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		tag, ok := hashtagParam(c)
+		if !ok {
+			return
+		}
+
+		if err := hashtagRepo.UnfollowHashtag(c.Request.Context(), userID, tag); err != nil {
+			slog.Error("Failed to unfollow hashtag", "error", err, "hashtag", tag, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow hashtag"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Hashtag unfollowed"})
+	}
+}
+
+// GetFollowedHashtags handles GET /api/v1/users/me/hashtags
+func GetFollowedHashtags(hashtagRepo *data.HashtagRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		follows, err := hashtagRepo.GetFollowedHashtags(c.Request.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get followed hashtags", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get followed hashtags"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"hashtags": follows,
+			"count":    len(follows),
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"social-geo-go/internal/data"
 	"social-geo-go/internal/notifications"
 	"social-geo-go/internal/notifications/kafka"
+	"social-geo-go/internal/storage"
 )
 
 func truncateText(s string, max int) string {
@@ -54,6 +55,11 @@ func TogglePostLike(likeRepo *data.LikeRepository, postRepo *data.PostRepository
 		var req ToggleLikeRequest
 		_ = c.ShouldBindJSON(&req)
 
+		post, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to toggle like")
+		if !ok {
+			return
+		}
+
 		wantLiked, err := resolveWantLiked(c.Request.Context(), likeRepo, data.TargetTypePost, postID, userID, req.Like)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read like state"})
@@ -70,8 +76,7 @@ func TogglePostLike(likeRepo *data.LikeRepository, postRepo *data.PostRepository
 		}
 
 		if result.Changed && result.IsLiked && notifDispatcher != nil {
-			post, _ := postRepo.GetPostByID(c.Request.Context(), postID)
-			if post != nil && post.UserID != userID {
+			if post.UserID != userID {
 				go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
 					EventID:     gocql.TimeUUID().String(),
 					EventType:   data.NotificationTypeLike,
@@ -96,7 +101,7 @@ func TogglePostLike(likeRepo *data.LikeRepository, postRepo *data.PostRepository
 
 // ToggleCommentLike handles POST /api/v1/comments/:id/toggle-like
 // This is the idempotent version - safe for retries and double-clicks
-func ToggleCommentLike(likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+func ToggleCommentLike(likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		commentID := c.Param("id")
 		userID := auth.GetUserID(c)
@@ -109,6 +114,11 @@ func ToggleCommentLike(likeRepo *data.LikeRepository, commentRepo *data.CommentR
 		var req ToggleLikeRequest
 		_ = c.ShouldBindJSON(&req)
 
+		comment, ok := requireVisibleComment(c, commentRepo, postRepo, commentID, "Failed to toggle like")
+		if !ok {
+			return
+		}
+
 		wantLiked, err := resolveWantLiked(c.Request.Context(), likeRepo, data.TargetTypeComment, commentID, userID, req.Like)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read like state"})
@@ -125,8 +135,7 @@ func ToggleCommentLike(likeRepo *data.LikeRepository, commentRepo *data.CommentR
 		}
 
 		if result.Changed && result.IsLiked && notifDispatcher != nil {
-			comment, _ := commentRepo.GetCommentByID(c.Request.Context(), commentID)
-			if comment != nil && comment.UserID != userID {
+			if comment.UserID != userID {
 				go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
 					EventID:     gocql.TimeUUID().String(),
 					EventType:   data.NotificationTypeLike,
@@ -152,7 +161,7 @@ func ToggleCommentLike(likeRepo *data.LikeRepository, commentRepo *data.CommentR
 // ============== LEGACY ENDPOINTS (backward compatible) ==============
 
 // LikePost handles POST /api/v1/posts/:id/like
-func LikePost(likeRepo *data.LikeRepository) gin.HandlerFunc {
+func LikePost(likeRepo *data.LikeRepository, postRepo *data.PostRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Deprecation", "true")
 		c.Header("Sunset", "2026-08-01")
@@ -166,6 +175,10 @@ func LikePost(likeRepo *data.LikeRepository) gin.HandlerFunc {
 			return
 		}
 
+		if _, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to like post"); !ok {
+			return
+		}
+
 		result, err := likeRepo.ToggleLike(c.Request.Context(), data.TargetTypePost, postID, userID, true)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -214,7 +227,7 @@ func UnlikePost(likeRepo *data.LikeRepository) gin.HandlerFunc {
 }
 
 // LikeComment handles POST /api/v1/comments/:id/like
-func LikeComment(likeRepo *data.LikeRepository) gin.HandlerFunc {
+func LikeComment(likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, postRepo *data.PostRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Deprecation", "true")
 		c.Header("Sunset", "2026-08-01")
@@ -228,6 +241,10 @@ func LikeComment(likeRepo *data.LikeRepository) gin.HandlerFunc {
 			return
 		}
 
+		if _, ok := requireVisibleComment(c, commentRepo, postRepo, commentID, "Failed to like comment"); !ok {
+			return
+		}
+
 		result, err := likeRepo.ToggleLike(c.Request.Context(), data.TargetTypeComment, commentID, userID, true)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -276,17 +293,109 @@ func UnlikeComment(likeRepo *data.LikeRepository) gin.HandlerFunc {
 }
 
 // GetLikedPosts handles GET /api/v1/users/:id/liked-posts
-func GetLikedPosts(likeRepo *data.LikeRepository, postRepo *data.PostRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository) gin.HandlerFunc {
+// Posts are listed most recently liked first. Deleted posts and posts outside
+// the caller's audience are skipped.
+func GetLikedPosts(likeRepo *data.LikeRepository, followRepo *data.FollowRepository, postRepo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := c.Param("id")
 
-		c.JSON(http.StatusOK, gin.H{
-			"user_id":     userID,
-			"count":       0,
-			"data":        []data.Post{},
-			"has_more":    false,
-			"next_cursor": "",
-			"message":     "Reading from likes_by_user not fully implemented yet",
+		user, err := userRepo.GetUserByID(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		if !requireProfileAccess(c, followRepo, user) {
+			return
+		}
+
+		var req data.Pagination
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid query parameters",
+			})
+			return
+		}
+
+		// Decode cursor for pagination
+		cursor, err := data.DecodeCursor(data.CursorScopeLikedPosts, req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor",
+			})
+			return
+		}
+
+		limit := data.GetDefaultLimit(req.Limit, 20, 100)
+		currentUserID := auth.GetUserID(c)
+
+		// Pages are keyed by when the post was liked, so the like keysets (not
+		// the posts' own timestamps) drive the cursor. Refill like collectPosts
+		// when deleted or restricted posts thin out a page.
+		var posts []data.Post
+		var likedKeys []data.Keyset
+		var next *data.Keyset
+		after := cursor.Keyset
+		for round := 0; round < maxVisibleFetchRounds; round++ {
+			keys, err := likeRepo.GetLikedPostKeys(c.Request.Context(), userID, limit+1, after)
+			if err != nil {
+				slog.Error("Failed to fetch liked posts", "error", err, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to fetch liked posts",
+				})
+				return
+			}
+
+			postIDs := make([]string, len(keys))
+			keyByID := make(map[string]data.Keyset, len(keys))
+			for i, k := range keys {
+				postIDs[i] = k.ID
+				keyByID[k.ID] = k
+			}
+
+			batch, err := postRepo.GetPostsByIDs(c.Request.Context(), postIDs)
+			if err != nil {
+				slog.Error("Failed to fetch liked posts", "error", err, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to fetch liked posts",
+				})
+				return
+			}
+			for _, p := range postRepo.FilterVisiblePosts(c.Request.Context(), batch, currentUserID) {
+				posts = append(posts, p)
+				likedKeys = append(likedKeys, keyByID[p.ID])
+			}
+
+			if len(posts) > limit {
+				posts = posts[:limit]
+				next = &likedKeys[limit-1]
+				break
+			}
+			if len(keys) < limit+1 {
+				break
+			}
+			after = keys[len(keys)-1]
+			if round == maxVisibleFetchRounds-1 {
+				next = &after
+			}
+		}
+
+		var nextCursor string
+		if next != nil {
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeLikedPosts,
+				Keyset: *next,
+			})
+		}
+
+		EnrichPosts(c.Request.Context(), posts, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
+
+		c.JSON(http.StatusOK, data.PaginatedResponse{
+			Data:       posts,
+			Count:      len(posts),
+			HasMore:    next != nil,
+			NextCursor: nextCursor,
 		})
 	}
 }
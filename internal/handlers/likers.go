@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// likersPage is one page of a liker list and the cursor of the next page
+type likersPage struct {
+	keys      []data.Keyset
+	following map[string]bool
+	next      *data.Cursor
+}
+
+// GetPostLikers handles GET /api/v1/posts/:id/likes
+// Lists who liked a post: people the caller follows first, then everyone
+// else, each most recently liked first. Blocked users are left out.
+func GetPostLikers(likeRepo *data.LikeRepository, postRepo *data.PostRepository, userRepo *data.UserRepository, followRepo *data.FollowRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		if !canViewLikedPost(c, postRepo, postID, "Post not found") {
+			return
+		}
+		listLikers(c, data.TargetTypePost, postID, likeRepo, userRepo, followRepo, modRepo, store)
+	}
+}
+
+// GetCommentLikers handles GET /api/v1/comments/:id/likes
+// Same ordering as GetPostLikers
+func GetCommentLikers(likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, postRepo *data.PostRepository, userRepo *data.UserRepository, followRepo *data.FollowRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		commentID := c.Param("id")
+
+		comment, err := commentRepo.GetCommentByID(c.Request.Context(), commentID)
+		if err != nil || comment == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Comment not found",
+			})
+			return
+		}
+		if !canViewLikedPost(c, postRepo, comment.PostID, "Comment not found") {
+			return
+		}
+		listLikers(c, data.TargetTypeComment, commentID, likeRepo, userRepo, followRepo, modRepo, store)
+	}
+}
+
+// canViewLikedPost reports whether the caller may see the post, responding
+// with notFound when they may not
+func canViewLikedPost(c *gin.Context, postRepo *data.PostRepository, postID, notFound string) bool {
+	post, err := postRepo.GetPostByID(c.Request.Context(), postID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": notFound,
+			})
+			return false
+		}
+		slog.Error("Failed to fetch post", "error", err, "post_id", postID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch likes",
+		})
+		return false
+	}
+
+	// Posts outside the caller's audience look the same as missing ones
+	canView, err := postRepo.CanViewPost(c.Request.Context(), post, auth.GetUserID(c))
+	if err != nil {
+		slog.Error("Failed to check post visibility", "error", err, "post_id", postID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch likes",
+		})
+		return false
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": notFound,
+		})
+		return false
+	}
+	return true
+}
+
+// listLikers responds with a page of the users who liked a target
+func listLikers(c *gin.Context, targetType, targetID string, likeRepo *data.LikeRepository, userRepo *data.UserRepository, followRepo *data.FollowRepository, modRepo *data.ModerationRepository, store storage.MediaStore) {
+	var req data.Pagination
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid query parameters",
+		})
+		return
+	}
+
+	// A cursor continues either the followed section or everyone else
+	cursor, err := data.DecodeCursor(data.CursorScopeLikers, req.Cursor)
+	if err != nil {
+		cursor, err = data.DecodeCursor(data.CursorScopeLikersFollowed, req.Cursor)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid cursor",
+		})
+		return
+	}
+	if req.Cursor == "" {
+		cursor.Scope = data.CursorScopeLikersFollowed
+	}
+
+	ctx := c.Request.Context()
+	limit := data.GetDefaultLimit(req.Limit, 20, 100)
+	currentUserID := auth.GetUserID(c)
+
+	page, err := collectLikers(ctx, targetType, targetID, likeRepo, followRepo, modRepo, currentUserID, cursor, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid ID",
+			})
+			return
+		}
+		slog.Error("Failed to fetch likes", "error", err, "target_type", targetType, "target_id", targetID)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch likes",
+		})
+		return
+	}
+
+	userIDs := make([]string, len(page.keys))
+	for i, k := range page.keys {
+		userIDs[i] = k.ID
+	}
+	users, _ := userRepo.GetUsersByIDs(ctx, userIDs)
+
+	likers := make([]data.Liker, 0, len(page.keys))
+	for _, k := range page.keys {
+		// Deleted accounts are skipped
+		info, ok := users[k.ID]
+		if !ok {
+			continue
+		}
+		likers = append(likers, data.Liker{
+			UserID:            k.ID,
+			Username:          info.Username,
+			ProfilePictureURL: storage.ResolveMediaURL(store, info.ProfilePictureURL),
+			IsFollowing:       page.following[k.ID],
+			LikedAt:           k.CreatedAt,
+		})
+	}
+
+	var nextCursor string
+	if page.next != nil {
+		nextCursor = data.EncodeCursor(*page.next)
+	}
+
+	c.JSON(http.StatusOK, data.PaginatedResponse{
+		Data:       likers,
+		Count:      len(likers),
+		HasMore:    page.next != nil,
+		NextCursor: nextCursor,
+	})
+}
+
+// collectLikers reads a page of likers starting at cursor. The followed
+// section is read whole from like_state for the people the user follows; the
+// rest comes from likes_by_target, skipping people the user follows since
+// they were listed first.
+func collectLikers(ctx context.Context, targetType, targetID string, likeRepo *data.LikeRepository, followRepo *data.FollowRepository, modRepo *data.ModerationRepository, userID string, cursor data.Cursor, limit int) (*likersPage, error) {
+	page := &likersPage{following: make(map[string]bool)}
+
+	blocked := make(map[string]bool)
+	if modRepo != nil && userID != "" {
+		blocked = modRepo.GetBlockedEitherWay(ctx, userID)
+	}
+
+	after := cursor.Keyset
+	if cursor.Scope == data.CursorScopeLikersFollowed {
+		if userID != "" {
+			following, err := followRepo.GetAllFollowing(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			keys, err := likeRepo.GetLikerKeysAmong(ctx, targetType, targetID, following)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range keys {
+				if blocked[k.ID] || !after.Admits(k.CreatedAt, k.ID) {
+					continue
+				}
+				page.keys = append(page.keys, k)
+				page.following[k.ID] = true
+			}
+		}
+
+		if len(page.keys) >= limit {
+			page.keys = page.keys[:limit]
+			page.next = &data.Cursor{Scope: data.CursorScopeLikersFollowed, Keyset: page.keys[limit-1]}
+			return page, nil
+		}
+		after = data.Keyset{}
+	}
+
+	// Everyone else fills the rest of the page. Refill like collectPosts when
+	// blocked or followed users thin out a batch.
+	need := limit - len(page.keys)
+	var others []data.Keyset
+	for round := 0; round < maxVisibleFetchRounds; round++ {
+		batch, err := likeRepo.GetLikerKeys(ctx, targetType, targetID, need+1, after)
+		if err != nil {
+			return nil, err
+		}
+
+		followed := make(map[string]bool)
+		if userID != "" && len(batch) > 0 {
+			ids := make([]string, len(batch))
+			for i, k := range batch {
+				ids[i] = k.ID
+			}
+			if followed, err = followRepo.GetFollowingAmong(ctx, userID, ids); err != nil {
+				return nil, err
+			}
+		}
+		for _, k := range batch {
+			if !blocked[k.ID] && !followed[k.ID] {
+				others = append(others, k)
+			}
+		}
+
+		if len(others) > need {
+			others = others[:need]
+			page.next = &data.Cursor{Scope: data.CursorScopeLikers, Keyset: others[need-1]}
+			break
+		}
+		if len(batch) < need+1 {
+			break
+		}
+		after = batch[len(batch)-1]
+		if round == maxVisibleFetchRounds-1 {
+			page.next = &data.Cursor{Scope: data.CursorScopeLikers, Keyset: after}
+		}
+	}
+
+	page.keys = append(page.keys, others...)
+	return page, nil
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/search"
+)
+
+// GetMapPosts handles GET /api/v1/map/posts
+func GetMapPosts(svc search.Service, modRepo *data.ModerationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bbox, err := search.ParseBBox(c.Query("bbox"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		zoom, err := strconv.Atoi(c.Query("zoom"))
+		if err != nil || zoom < 0 || zoom > search.MaxMapZoom {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "zoom must be an integer between 0 and 22",
+			})
+			return
+		}
+
+		// Leave out blocked/muted authors, as the feed does
+		currentUserID := auth.GetUserID(c)
+		var excludeUserIDs []string
+		if modRepo != nil && currentUserID != "" {
+			excluded, _ := modRepo.GetBlockedAndMutedUsers(c.Request.Context(), currentUserID)
+			for id := range excluded {
+				excludeUserIDs = append(excludeUserIDs, id)
+			}
+		}
+
+		result, err := svc.MapPosts(c.Request.Context(), bbox, zoom, excludeUserIDs)
+		if err != nil {
+			slog.Error("Failed to fetch map posts", "error", err, "zoom", zoom)
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "map unavailable",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+	"social-geo-go/internal/storage"
+)
+
+// Mentioner resolves the @mentions in posts and comments, stores them and
+// notifies the users mentioned
+type Mentioner struct {
+	Users      *data.UserRepository
+	Posts      *data.PostRepository
+	Mentions   *data.MentionRepository
+	Moderation *data.ModerationRepository
+	Notifier   *notifications.NotificationDispatcher
+}
+
+// Resolve returns the mentions in content that name an existing user. Only
+// the first MaxMentions distinct usernames are looked up.
+func (m *Mentioner) Resolve(ctx context.Context, content string) []data.Mention {
+	parsed := data.ParseMentions(content)
+	if len(parsed) == 0 {
+		return nil
+	}
+
+	users := make(map[string]*data.User)
+	var mentions []data.Mention
+	for _, mention := range parsed {
+		user, looked := users[mention.Username]
+		if !looked {
+			if len(users) >= data.MaxMentions {
+				break
+			}
+			u, err := m.Users.GetUserByUsername(ctx, mention.Username)
+			if err == nil && !u.IsDeleted {
+				user = u
+			}
+			users[mention.Username] = user
+		}
+		if user == nil {
+			continue
+		}
+		mention.UserID = user.ID
+		mention.Username = user.Username
+		mentions = append(mentions, mention)
+	}
+	return mentions
+}
+
+// Record resolves and stores the mentions of a post or comment that was just
+// created or edited, and notifies users who were not mentioned before. Users
+// are skipped when they and the author block each other or when they cannot
+// see the post. post may be nil, in which case it is loaded. Failures are
+// logged, never returned: the text itself is already saved.
+func (m *Mentioner) Record(ctx context.Context, target data.MentionTarget, post *data.Post, content string, edited bool) []data.Mention {
+	var previous []data.Mention
+	if edited {
+		prev, err := m.Mentions.GetMentions(ctx, target.Type, []string{target.ID})
+		if err != nil {
+			slog.Warn("Failed to get previous mentions", "error", err, "target_id", target.ID)
+			return nil
+		}
+		previous = prev[target.ID]
+	}
+
+	mentions := m.Resolve(ctx, content)
+	if len(mentions) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	if post == nil {
+		p, err := m.Posts.GetPostByID(ctx, target.PostID)
+		if err != nil {
+			slog.Warn("Failed to get post for mentions", "error", err, "post_id", target.PostID)
+			return nil
+		}
+		post = p
+	}
+
+	// Mentions in an ephemeral post go away with it
+	if post.ExpiresAt != nil {
+		remaining := time.Until(*post.ExpiresAt)
+		if remaining <= 0 {
+			return nil
+		}
+		target.TTL = int(math.Ceil(remaining.Seconds()))
+	}
+
+	recipients := m.recipients(ctx, target, post, mentions, previous)
+	if err := m.Mentions.SaveMentions(ctx, target, mentions, recipients, len(previous) > 0); err != nil {
+		slog.Error("Failed to save mentions", "error", err, "target_id", target.ID)
+		return nil
+	}
+
+	m.notify(target, content, recipients)
+	return mentions
+}
+
+// commentMentionTarget returns the mention target of a comment
+func commentMentionTarget(comment *data.Comment) data.MentionTarget {
+	return data.MentionTarget{
+		Type:     data.TargetTypeComment,
+		ID:       comment.ID,
+		PostID:   comment.PostID,
+		AuthorID: comment.UserID,
+	}
+}
+
+// recipients returns the users in mentions to add to the mentions tab and
+// notify: each once, never the author or anyone already mentioned in
+// previous
+func (m *Mentioner) recipients(ctx context.Context, target data.MentionTarget, post *data.Post, mentions, previous []data.Mention) []string {
+	skip := map[string]bool{target.AuthorID: true}
+	for _, p := range previous {
+		skip[p.UserID] = true
+	}
+
+	var recipients []string
+	for _, mention := range mentions {
+		if skip[mention.UserID] {
+			continue
+		}
+		skip[mention.UserID] = true
+
+		if m.Moderation != nil {
+			blocked, err := m.Moderation.IsBlocked(ctx, target.AuthorID, mention.UserID)
+			if err != nil || blocked {
+				continue
+			}
+		}
+		canView, err := m.Posts.CanViewPost(ctx, post, mention.UserID)
+		if err != nil || !canView {
+			continue
+		}
+		recipients = append(recipients, mention.UserID)
+	}
+	return recipients
+}
+
+func (m *Mentioner) notify(target data.MentionTarget, content string, recipients []string) {
+	if m.Notifier == nil {
+		return
+	}
+
+	message := "mentioned you in a post"
+	previewKey := "post_preview"
+	if target.Type == data.TargetTypeComment {
+		message = "mentioned you in a comment"
+		previewKey = "comment_preview"
+	}
+
+	for _, recipientID := range recipients {
+		go m.Notifier.Dispatch(context.Background(), &kafka.NotificationEvent{
+			EventID:     gocql.TimeUUID().String(),
+			EventType:   data.NotificationTypeMention,
+			ActorID:     target.AuthorID,
+			RecipientID: recipientID,
+			TargetType:  target.Type,
+			TargetID:    target.ID,
+			Message:     message,
+			Payload: map[string]string{
+				"post_id":  target.PostID,
+				previewKey: truncateText(content, 100),
+			},
+			CreatedAt: time.Now().Format(time.RFC3339),
+		})
+	}
+}
+
+// attachMentions sets the mention entities of posts
+func attachMentions(ctx context.Context, posts []data.Post, postRepo *data.PostRepository) {
+	if len(posts) == 0 {
+		return
+	}
+	postIDs := make([]string, len(posts))
+	for i, p := range posts {
+		postIDs[i] = p.ID
+	}
+
+	mentions, err := postRepo.GetMentionsForPosts(ctx, postIDs)
+	if err != nil {
+		slog.Warn("Failed to get post mentions", "error", err)
+	}
+	for i := range posts {
+		posts[i].Mentions = mentions[posts[i].ID]
+	}
+}
+
+// Helper to enrich comments with mention entities
+func enrichCommentsWithMentions(ctx context.Context, comments []data.Comment, commentRepo *data.CommentRepository) {
+	if commentRepo == nil || len(comments) == 0 {
+		return
+	}
+	var commentIDs []string
+	var collectCommentIDs func([]data.Comment)
+	collectCommentIDs = func(comments []data.Comment) {
+		for _, c := range comments {
+			commentIDs = append(commentIDs, c.ID)
+			if len(c.Replies) > 0 {
+				collectCommentIDs(c.Replies)
+			}
+		}
+	}
+	collectCommentIDs(comments)
+
+	mentions, err := commentRepo.GetMentionsForComments(ctx, commentIDs)
+	if err != nil {
+		slog.Warn("Failed to get comment mentions", "error", err)
+	}
+
+	var enrich func([]data.Comment)
+	enrich = func(comments []data.Comment) {
+		for i := range comments {
+			comments[i].Mentions = mentions[comments[i].ID]
+			if len(comments[i].Replies) > 0 {
+				enrich(comments[i].Replies)
+			}
+		}
+	}
+	enrich(comments)
+}
+
+// GetMentions handles GET /api/v1/users/me/mentions
+func GetMentions(mentionRepo *data.MentionRepository, postRepo *data.PostRepository, commentRepo *data.CommentRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.Pagination
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+			return
+		}
+
+		cursor, err := data.DecodeCursor(data.CursorScopeMentions, req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+
+		limit := data.GetDefaultLimit(req.Limit, 20, 100)
+
+		excluded := make(map[string]bool)
+		if modRepo != nil {
+			if ex, err := modRepo.GetBlockedAndMutedUsers(c.Request.Context(), userID); err == nil {
+				excluded = ex
+			}
+		}
+
+		// Entries are checked when read: text edited since, deleted posts and
+		// comments, and posts the user can no longer see are left out
+		var items []data.MentionItem
+		var itemKeys []data.Keyset
+		var next *data.Keyset
+		seen := make(map[string]bool)
+		after := cursor.Keyset
+		for round := 0; round < maxVisibleFetchRounds; round++ {
+			refs, err := mentionRepo.GetMentionRefs(c.Request.Context(), userID, limit+1, after)
+			if err != nil {
+				slog.Error("Failed to fetch mentions", "error", err, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mentions"})
+				return
+			}
+
+			batch, err := loadMentionItems(c.Request.Context(), refs, userID, excluded, seen, mentionRepo, postRepo, commentRepo)
+			if err != nil {
+				slog.Error("Failed to fetch mentions", "error", err, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mentions"})
+				return
+			}
+			for _, b := range batch {
+				items = append(items, b.item)
+				itemKeys = append(itemKeys, b.key)
+			}
+
+			if len(items) > limit {
+				items = items[:limit]
+				next = &itemKeys[limit-1]
+				break
+			}
+			if len(refs) < limit+1 {
+				break
+			}
+			after = refs[len(refs)-1].Keyset()
+			if round == maxVisibleFetchRounds-1 {
+				next = &after
+			}
+		}
+
+		var nextCursor string
+		if next != nil {
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeMentions,
+				Keyset: *next,
+			})
+		}
+
+		posts := make([]data.Post, len(items))
+		var comments []data.Comment
+		for i, item := range items {
+			posts[i] = *item.Post
+			if item.Comment != nil {
+				comments = append(comments, *item.Comment)
+			}
+		}
+		EnrichPosts(c.Request.Context(), posts, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, userID, store)
+		enrichCommentsWithUserInfo(c.Request.Context(), comments, userRepo, store)
+		enrichCommentsWithLikeInfo(c.Request.Context(), comments, likeRepo, userID)
+		enrichCommentsWithMentions(c.Request.Context(), comments, commentRepo)
+
+		n := 0
+		for i := range items {
+			items[i].Post = &posts[i]
+			if items[i].Comment != nil {
+				items[i].Comment = &comments[n]
+				n++
+			}
+		}
+
+		if items == nil {
+			items = []data.MentionItem{}
+		}
+		c.JSON(http.StatusOK, data.PaginatedResponse{
+			Data:       items,
+			Count:      len(items),
+			HasMore:    next != nil,
+			NextCursor: nextCursor,
+		})
+	}
+}
+
+type keyedMentionItem struct {
+	item data.MentionItem
+	key  data.Keyset
+}
+
+// loadMentionItems turns entries of userID's mentions tab into items, keeping
+// only those whose post or comment still mentions the user, is not deleted,
+// is visible to them and is not by someone in excluded. Targets already in
+// seen are skipped, so a page lists each post or comment once.
+func loadMentionItems(ctx context.Context, refs []data.MentionRef, userID string, excluded, seen map[string]bool, mentionRepo *data.MentionRepository, postRepo *data.PostRepository, commentRepo *data.CommentRepository) ([]keyedMentionItem, error) {
+	var postIDs, postTargets, commentTargets []string
+	seenPosts := make(map[string]bool)
+	for _, ref := range refs {
+		if excluded[ref.AuthorID] {
+			continue
+		}
+		if !seenPosts[ref.PostID] {
+			postIDs = append(postIDs, ref.PostID)
+			seenPosts[ref.PostID] = true
+		}
+		if ref.TargetType == data.TargetTypeComment {
+			commentTargets = append(commentTargets, ref.TargetID)
+		} else {
+			postTargets = append(postTargets, ref.TargetID)
+		}
+	}
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	current, err := mentionRepo.GetMentions(ctx, data.TargetTypePost, postTargets)
+	if err != nil {
+		return nil, err
+	}
+	commentMentions, err := mentionRepo.GetMentions(ctx, data.TargetTypeComment, commentTargets)
+	if err != nil {
+		return nil, err
+	}
+	for id, m := range commentMentions {
+		current[id] = m
+	}
+
+	fetched, err := postRepo.GetPostsByIDs(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[string]data.Post)
+	for _, p := range postRepo.FilterVisiblePosts(ctx, fetched, userID) {
+		visible[p.ID] = p
+	}
+
+	var items []keyedMentionItem
+	for _, ref := range refs {
+		if excluded[ref.AuthorID] || seen[ref.TargetID] || !mentionsUser(current[ref.TargetID], userID) {
+			continue
+		}
+		post, ok := visible[ref.PostID]
+		if !ok || excluded[post.UserID] {
+			continue
+		}
+
+		item := data.MentionItem{TargetType: ref.TargetType, CreatedAt: ref.CreatedAt, Post: &post}
+		if ref.TargetType == data.TargetTypeComment {
+			comment, err := commentRepo.GetCommentByID(ctx, ref.TargetID)
+			if err != nil || comment.IsDeleted {
+				continue
+			}
+			item.Comment = comment
+		}
+
+		seen[ref.TargetID] = true
+		items = append(items, keyedMentionItem{item: item, key: ref.Keyset()})
+	}
+	return items, nil
+}
+
+func mentionsUser(mentions []data.Mention, userID string) bool {
+	for _, m := range mentions {
+		if m.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -260,3 +262,103 @@ func GetMutedUsers(modRepo *data.ModerationRepository) gin.HandlerFunc {
 		})
 	}
 }
+
+// ============== MUTED WORDS ==============
+
+// MuteWordRequest represents the request body for muting a word
+type MuteWordRequest struct {
+	Word string `json:"word" binding:"required"`
+}
+
+// MuteWord handles POST /api/v1/users/me/muted-words
+func MuteWord(modRepo *data.ModerationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req MuteWordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		word, err := data.NormalizeMutedWord(req.Word)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Muted words must be 1 to %d characters", data.MaxMutedWordLength),
+			})
+			return
+		}
+
+		if err := modRepo.MuteWord(c.Request.Context(), userID, word); err != nil {
+			if strings.Contains(err.Error(), "too many") {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("You can mute at most %d words", data.MaxMutedWords),
+				})
+				return
+			}
+			slog.Error("Failed to mute word", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to mute word",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Word muted", "word": word})
+	}
+}
+
+// UnmuteWord handles DELETE /api/v1/users/me/muted-words/:word
+func UnmuteWord(modRepo *data.ModerationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		word, err := data.NormalizeMutedWord(c.Param("word"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word"})
+			return
+		}
+
+		if err := modRepo.UnmuteWord(c.Request.Context(), userID, word); err != nil {
+			slog.Error("Failed to unmute word", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to unmute word",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Word unmuted"})
+	}
+}
+
+// GetMutedWords handles GET /api/v1/users/me/muted-words
+func GetMutedWords(modRepo *data.ModerationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		words, err := modRepo.GetMutedWords(c.Request.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get muted words", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get muted words",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"muted_words": words,
+			"count":       len(words),
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+)
+
+// pollCloseBatch caps how many polls one run of the poll closer closes
+const pollCloseBatch = 100
+
+// RunPollCloser closes due polls every interval until ctx is cancelled. Each
+// run stores the final results of polls past their closing time and notifies
+// their voters and author. A poll is closed exactly once, so several API
+// instances may run the poll closer.
+func RunPollCloser(ctx context.Context, pollRepo *data.PollRepository, notifDispatcher *notifications.NotificationDispatcher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			closeDuePolls(ctx, pollRepo, notifDispatcher)
+		}
+	}
+}
+
+func closeDuePolls(ctx context.Context, pollRepo *data.PollRepository, notifDispatcher *notifications.NotificationDispatcher) {
+	closed, err := pollRepo.ClosePolls(ctx, time.Now(), pollCloseBatch)
+	if err != nil {
+		slog.Error("Failed to close polls", "error", err, "closed", len(closed))
+	}
+	if len(closed) == 0 {
+		return
+	}
+	slog.Info("Closed polls", "count", len(closed))
+
+	if notifDispatcher == nil {
+		return
+	}
+	for _, poll := range closed {
+		notifyPollResults(ctx, notifDispatcher, poll)
+	}
+}
+
+// notifyPollResults tells a closed poll's voters and author its final results
+func notifyPollResults(ctx context.Context, notifDispatcher *notifications.NotificationDispatcher, poll data.ClosedPoll) {
+	payload := map[string]string{
+		"winning_option": strings.Join(pollWinners(poll.Options), " / "),
+		"total_votes":    strconv.FormatInt(poll.TotalVotes, 10),
+	}
+
+	// The author hears about their poll once, whether or not they voted
+	notified := make(map[string]bool, len(poll.Voters)+1)
+	for _, recipientID := range append([]string{poll.AuthorID}, poll.Voters...) {
+		if notified[recipientID] {
+			continue
+		}
+		notified[recipientID] = true
+
+		message := "A poll you voted in has ended"
+		if recipientID == poll.AuthorID {
+			message = "Your poll has ended"
+		}
+
+		err := notifDispatcher.Dispatch(ctx, &kafka.NotificationEvent{
+			EventID:     gocql.TimeUUID().String(),
+			EventType:   data.NotificationTypePollClosed,
+			ActorID:     poll.AuthorID,
+			RecipientID: recipientID,
+			TargetType:  data.TargetTypePost,
+			TargetID:    poll.PostID,
+			Message:     message,
+			Payload:     payload,
+			CreatedAt:   time.Now().Format(time.RFC3339),
+		})
+		if err != nil {
+			slog.Warn("failed to dispatch poll results",
+				"post_id", poll.PostID,
+				"recipient_id", recipientID,
+				"error", err,
+			)
+		}
+	}
+}
+
+// pollWinners returns the options with the most votes, or none if nobody voted
+func pollWinners(options []data.PollOption) []string {
+	var best int64
+	var winners []string
+	for _, option := range options {
+		switch {
+		case option.VoteCount > best:
+			best = option.VoteCount
+			winners = []string{option.Text}
+		case option.VoteCount == best && best > 0:
+			winners = append(winners, option.Text)
+		}
+	}
+	return winners
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+)
+
+// attachPolls fills in the polls of posts that have one, with their tallies
+// and the viewer's vote
+func attachPolls(ctx context.Context, posts []data.Post, pollRepo *data.PollRepository, currentUserID string) {
+	if len(posts) == 0 {
+		return
+	}
+
+	postIDs := make([]string, len(posts))
+	for i, p := range posts {
+		postIDs[i] = p.ID
+	}
+
+	polls, err := pollRepo.GetPollsForPosts(ctx, postIDs, currentUserID)
+	if err != nil {
+		slog.Warn("Failed to get polls", "error", err)
+	}
+	for i := range posts {
+		if poll, ok := polls[posts[i].ID]; ok {
+			posts[i].Poll = poll
+		}
+	}
+}
+
+// VotePoll handles POST /api/v1/posts/:id/poll/vote
+// Each user votes once; voting again returns the first vote unchanged, so
+// retries are safe
+func VotePoll(pollRepo *data.PollRepository, postRepo *data.PostRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.PollVoteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "option is required"})
+			return
+		}
+
+		// Polls on posts outside the caller's audience look the same as missing ones
+		post, err := postRepo.GetPostByID(c.Request.Context(), postID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+				return
+			}
+			slog.Error("Failed to fetch post", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to vote"})
+			return
+		}
+		canView, err := postRepo.CanViewPost(c.Request.Context(), post, userID)
+		if err != nil {
+			slog.Error("Failed to check post visibility", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to vote"})
+			return
+		}
+		if !canView {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+
+		result, err := pollRepo.Vote(c.Request.Context(), postID, userID, *req.Option)
+		if err != nil {
+			switch {
+			case strings.Contains(err.Error(), "not found"):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Poll not found"})
+			case strings.Contains(err.Error(), "closed"):
+				c.JSON(http.StatusConflict, gin.H{"error": "Poll has closed"})
+			case strings.Contains(err.Error(), "invalid option"):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid option"})
+			default:
+				slog.Error("VotePoll failed", "error", err, "post_id", postID, "user_id", userID)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to vote"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"social-geo-go/internal/data"
+)
+
+func TestValidatePostRequestPoll(t *testing.T) {
+	inHours := func(h float64) time.Time {
+		return time.Now().Add(time.Duration(h * float64(time.Hour)))
+	}
+
+	tests := []struct {
+		name      string
+		poll      data.CreatePollRequest
+		expiresIn int
+		wantErr   bool
+	}{
+		{"two options", data.CreatePollRequest{Options: []string{"Yes", "No"}, ClosesAt: inHours(24)}, 0, false},
+		{"four options", data.CreatePollRequest{Options: []string{"A", "B", "C", "D"}, ClosesAt: inHours(1)}, 0, false},
+		{"one option", data.CreatePollRequest{Options: []string{"Yes"}, ClosesAt: inHours(24)}, 0, true},
+		{"five options", data.CreatePollRequest{Options: []string{"A", "B", "C", "D", "E"}, ClosesAt: inHours(24)}, 0, true},
+		{"blank option", data.CreatePollRequest{Options: []string{"Yes", "  "}, ClosesAt: inHours(24)}, 0, true},
+		{"duplicate options", data.CreatePollRequest{Options: []string{"Yes", " yes"}, ClosesAt: inHours(24)}, 0, true},
+		{"closes too soon", data.CreatePollRequest{Options: []string{"Yes", "No"}, ClosesAt: inHours(0.01)}, 0, true},
+		{"closes too late", data.CreatePollRequest{Options: []string{"Yes", "No"}, ClosesAt: inHours(8 * 24)}, 0, true},
+		{"closes before the post expires", data.CreatePollRequest{Options: []string{"Yes", "No"}, ClosesAt: inHours(2)}, 3, false},
+		{"closes after the post expires", data.CreatePollRequest{Options: []string{"Yes", "No"}, ClosesAt: inHours(4)}, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := tt.poll
+			req := &data.CreatePostRequest{Content: "Poll", ExpiresIn: tt.expiresIn, Poll: &poll}
+			err := validatePostRequest(req)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var inputErr *postInputError
+			assert.True(t, errors.As(err, &inputErr), "want a postInputError, got %v", err)
+		})
+	}
+}
+
+func TestPollWinners(t *testing.T) {
+	options := func(counts ...int64) []data.PollOption {
+		var opts []data.PollOption
+		for i, c := range counts {
+			opts = append(opts, data.PollOption{Text: string(rune('A' + i)), VoteCount: c})
+		}
+		return opts
+	}
+
+	assert.Equal(t, []string{"B"}, pollWinners(options(1, 3, 2)))
+	assert.Equal(t, []string{"A", "C"}, pollWinners(options(2, 1, 2)))
+	assert.Empty(t, pollWinners(options(0, 0)))
+}
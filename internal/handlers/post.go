@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -8,21 +9,23 @@ import (
 
 	"context"
 	"github.com/gin-gonic/gin"
-	"github.com/gocql/gocql"
 	"time"
 
 	"social-geo-go/internal/auth"
+	"social-geo-go/internal/cache"
 	"social-geo-go/internal/data"
-	"social-geo-go/internal/notifications"
-	"social-geo-go/internal/notifications/kafka"
 	"social-geo-go/internal/search"
 	"social-geo-go/internal/storage"
 )
 
-// EnrichPosts adds author, location, and like fields to posts (same shape as GET /api/v1/feed items).
+// EnrichPosts adds author, location, like and repost fields and polls to
+// posts, and embeds quoted posts (same shape as GET /api/v1/feed items).
 func EnrichPosts(
 	ctx context.Context,
 	posts []data.Post,
+	postRepo *data.PostRepository,
+	pollRepo *data.PollRepository,
+	bookmarkRepo *data.BookmarkRepository,
 	userRepo *data.UserRepository,
 	locRepo *data.LocationRepository,
 	likeRepo *data.LikeRepository,
@@ -47,6 +50,13 @@ func EnrichPosts(
 			userIDs = append(userIDs, p.UserID)
 			seenUsers[p.UserID] = true
 		}
+		if p.RepostedBy != "" && !seenUsers[p.RepostedBy] {
+			userIDs = append(userIDs, p.RepostedBy)
+			seenUsers[p.RepostedBy] = true
+		}
+		if !p.HasLocation() {
+			continue
+		}
 		geohashPrefix := data.GetGeohashPrefix(p.Latitude, p.Longitude)
 		if !seenGeohashes[geohashPrefix] {
 			geohashes = append(geohashes, geohashPrefix)
@@ -62,16 +72,21 @@ func EnrichPosts(
 				posts[i].Username = info.Username
 				posts[i].ProfilePictureURL = storage.ResolveMediaURL(store, info.ProfilePictureURL)
 			}
+			if info, ok := userInfoMap[posts[i].RepostedBy]; ok {
+				posts[i].RepostedByUsername = info.Username
+			}
 		}
 	}
 
 	if locRepo != nil {
 		locInfoMap, _ := locRepo.GetLocationsByGeohashes(ctx, geohashes, latLngMap)
 		for i := range posts {
+			if !posts[i].HasLocation() {
+				continue
+			}
 			geohashPrefix := data.GetGeohashPrefix(posts[i].Latitude, posts[i].Longitude)
 			if loc, ok := locInfoMap[geohashPrefix]; ok {
-				posts[i].LocationName = loc.Name
-				posts[i].Address = &loc.Address
+				posts[i].SetLocation(loc)
 			}
 		}
 	}
@@ -82,6 +97,8 @@ func EnrichPosts(
 			if info, ok := likeInfoMap[posts[i].ID]; ok {
 				posts[i].LikeCount = info.LikeCount
 				posts[i].IsLiked = info.IsLiked
+				posts[i].ReactionCounts = info.ReactionCounts
+				posts[i].MyReaction = info.MyReaction
 			}
 		}
 	}
@@ -93,11 +110,25 @@ func EnrichPosts(
 		}
 	}
 
+	if postRepo != nil {
+		enrichRepostInfo(ctx, posts, postRepo, currentUserID)
+		attachQuotedPosts(ctx, posts, postRepo, userRepo, locRepo, currentUserID, store)
+		attachMentions(ctx, posts, postRepo)
+	}
+
+	if pollRepo != nil {
+		attachPolls(ctx, posts, pollRepo, currentUserID)
+	}
+
+	if bookmarkRepo != nil {
+		attachBookmarks(ctx, posts, bookmarkRepo, currentUserID)
+	}
+
 	ResolvePostsMediaURLs(store, posts)
 }
 
 // CreatePost handles POST /api/v1/posts
-func CreatePost(postRepo *data.PostRepository, userRepo *data.UserRepository, notifDispatcher *notifications.NotificationDispatcher, postIndexer search.PostIndexer, store storage.MediaStore) gin.HandlerFunc {
+func CreatePost(publisher *PostPublisher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req data.CreatePostRequest
 
@@ -108,29 +139,6 @@ func CreatePost(postRepo *data.PostRepository, userRepo *data.UserRepository, no
 			return
 		}
 
-		// Validate coordinates
-		if req.Latitude < -90 || req.Latitude > 90 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Latitude must be between -90 and 90",
-			})
-			return
-		}
-
-		if req.Longitude < -180 || req.Longitude > 180 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Longitude must be between -180 and 180",
-			})
-			return
-		}
-
-		// Validate media (max 4 total)
-		if !req.ValidateMedia() {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Maximum 4 media items allowed",
-			})
-			return
-		}
-
 		// Enforce that the post author is the authenticated user — never trust user_id from the request body
 		req.UserID = auth.GetUserID(c)
 		if req.UserID == "" {
@@ -138,70 +146,27 @@ func CreatePost(postRepo *data.PostRepository, userRepo *data.UserRepository, no
 			return
 		}
 
-		mediaURLs, err := preparePostMedia(store, req.UserID, req.MediaURLs, req.MediaKeys)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		req.MediaURLs = mediaURLs
-
 		// Capture IP address and user agent for tracking
 		req.IPAddress = c.ClientIP()
 		req.UserAgent = c.Request.UserAgent()
 
-		post, err := postRepo.CreatePost(c.Request.Context(), &req)
+		post, err := publisher.Publish(c.Request.Context(), &req)
 		if err != nil {
+			var inputErr *postInputError
+			if errors.As(err, &inputErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": inputErr.Error()})
+				return
+			}
+			slog.Error("Failed to create post", "error", err, "user_id", req.UserID)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "Failed to create post",
 			})
 			return
 		}
 
-		if notifDispatcher != nil {
-			contentTruncated := post.Content
-			if len(contentTruncated) > 100 {
-				contentTruncated = contentTruncated[:100] + "..."
-			}
-			go notifDispatcher.DispatchNearbyFanout(context.Background(), &kafka.NearbyFanoutJob{
-				EventID:   gocql.TimeUUID().String(),
-				PostID:    post.ID,
-				AuthorID:  post.UserID,
-				Geohash:   post.Geohash,
-				Content:   contentTruncated,
-				CreatedAt: time.Now().Format(time.RFC3339),
-			})
-		}
-
-		if postIndexer != nil {
-			username := ""
-			if user, err := userRepo.GetUserByID(context.Background(), post.UserID); err == nil && user != nil {
-				username = user.Username
-			}
-			event := &search.PostCreatedEvent{
-				PostID:    post.ID,
-				UserID:    post.UserID,
-				Username:  username,
-				Content:   post.Content,
-				Hashtags:  search.ExtractHashtags(post.Content),
-				Lat:       post.Latitude,
-				Lon:       post.Longitude,
-				Geohash:   post.Geohash,
-				CreatedAt: post.CreatedAt,
-				LikeCount: 0,
-			}
-			go func() {
-				if err := postIndexer.PublishPostCreated(context.Background(), event); err != nil {
-					slog.Warn("failed to publish post created event for search indexing",
-						"post_id", post.ID,
-						"error", err,
-					)
-				}
-			}()
-		}
-
 		c.JSON(http.StatusCreated, gin.H{
 			"message": "Post created successfully",
-			"post":    resolvePostForResponse(store, post),
+			"post":    resolvePostForResponse(publisher.Store, post),
 		})
 	}
 }
@@ -228,7 +193,7 @@ func preparePostMedia(store storage.MediaStore, userID string, urls, keys []stri
 }
 
 // GetFeed handles GET /api/v1/feed
-func GetFeed(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+func GetFeed(repo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, modRepo *data.ModerationRepository, rankSnapshots *cache.RankSnapshots, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req data.GetFeedRequest
 
@@ -254,8 +219,16 @@ func GetFeed(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 			return
 		}
 
+		scope, ok := feedCursorScope(req.Sort)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Sort must be one of recent, hot, nearest",
+			})
+			return
+		}
+
 		// Decode cursor for pagination
-		cursorTime, err := data.DecodeCursor(req.Cursor)
+		cursor, err := data.DecodeCursor(scope, req.Cursor)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "Invalid cursor",
@@ -263,6 +236,14 @@ func GetFeed(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 			return
 		}
 
+		// Continuation pages keep the origin and radius the first page was issued
+		// for, so a client that moves between pages does not skip or repeat posts.
+		if cursor.Origin != nil {
+			req.Latitude = cursor.Origin.Latitude
+			req.Longitude = cursor.Origin.Longitude
+			req.RadiusKM = cursor.Origin.RadiusKM
+		}
+
 		// Apply default limit
 		limit := data.GetDefaultLimit(req.Limit, 20, 100)
 
@@ -273,51 +254,102 @@ func GetFeed(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 			excludedUsers, _ = modRepo.GetBlockedAndMutedUsers(c.Request.Context(), currentUserID)
 		}
 
-		// Fetch extra to account for filtered posts + pagination
-		fetchLimit := limit + 1
-		if len(excludedUsers) > 0 {
-			fetchLimit = limit*2 + 1 // Fetch more if we expect to filter some out
+		origin := &data.CursorOrigin{
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+			RadiusKM:  req.RadiusKM,
 		}
 
-		posts, err := repo.GetNearbyPosts(
-			c.Request.Context(),
-			req.Latitude,
-			req.Longitude,
-			req.RadiusKM,
-			fetchLimit,
-			cursorTime,
-		)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to fetch feed",
+		var posts []data.Post
+		var hasMore bool
+		var nextCursor string
+		if scope == data.CursorScopeFeed {
+			// Fetch extra to account for filtered posts + pagination
+			fetchLimit := limit*2 + 1
+
+			var next *data.Keyset
+			posts, next, err = collectPosts(func(after data.Keyset, n int) ([]data.Post, data.Keyset, error) {
+				return repo.GetNearbyPosts(c.Request.Context(), req.Latitude, req.Longitude, req.RadiusKM, n, after)
+			}, cursor.Keyset, fetchLimit, limit, func(batch []data.Post) []data.Post {
+				return repo.FilterVisiblePosts(c.Request.Context(), excludePostAuthors(batch, excludedUsers), currentUserID)
 			})
-			return
-		}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to fetch feed",
+				})
+				return
+			}
+
+			// Generate next cursor from where this page stopped
+			hasMore = next != nil
+			if hasMore {
+				nextCursor = data.EncodeCursor(data.Cursor{
+					Scope:  scope,
+					Keyset: *next,
+					Origin: origin,
+				})
+			}
+		} else {
+			// Ranked modes score the newest posts as of the first page. Later pages
+			// re-rank the posts published before AsOf with the first page's scores,
+			// so they stay comparable with the cursor; posts published after AsOf
+			// appear on a fresh load.
+			asOf := time.UnixMilli(time.Now().UnixMilli())
+			if cursor.Rank != nil {
+				asOf = cursor.Rank.AsOf
+			}
+
+			pool, _, err := repo.GetNearbyPosts(
+				c.Request.Context(),
+				req.Latitude,
+				req.Longitude,
+				req.RadiusKM,
+				rankedFeedPool,
+				data.Keyset{CreatedAt: asOf},
+			)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to fetch feed",
+				})
+				return
+			}
+			pool = repo.FilterVisiblePosts(c.Request.Context(), excludePostAuthors(pool, excludedUsers), currentUserID)
+
+			snapshotKey := rankSnapshotKey(scope, fmt.Sprintf("%g,%g,%g", req.Latitude, req.Longitude, req.RadiusKM), currentUserID, asOf)
+			var snapshot map[string]float64
+			if req.Sort == FeedSortHot {
+				snapshot = loadRankSnapshot(c.Request.Context(), rankSnapshots, cursor, snapshotKey)
+			}
+
+			ranked := rankFeedPosts(c.Request.Context(), pool, req.Sort, asOf, snapshot, likeRepo, commentRepo)
+			page, more := pageRankedPosts(ranked, cursor, limit)
+			hasMore = more
 
-		// Filter out posts from blocked/muted users
-		if len(excludedUsers) > 0 {
-			var filtered []data.Post
-			for _, p := range posts {
-				if !excludedUsers[p.UserID] {
-					filtered = append(filtered, p)
+			if req.Sort == FeedSortHot && cursor.Rank == nil && hasMore {
+				scores := make(map[string]float64, len(ranked))
+				for _, r := range ranked {
+					scores[r.post.ID] = r.score
 				}
+				saveRankSnapshot(c.Request.Context(), rankSnapshots, snapshotKey, scores)
 			}
-			posts = filtered
-		}
 
-		// Determine if there are more posts
-		hasMore := len(posts) > limit
-		if hasMore {
-			posts = posts[:limit]
-		}
+			posts = make([]data.Post, len(page))
+			for i, r := range page {
+				posts[i] = r.post
+			}
 
-		// Generate next cursor from last post's created_at
-		var nextCursor string
-		if hasMore && len(posts) > 0 {
-			nextCursor = data.EncodeCursor(posts[len(posts)-1].CreatedAt)
+			if hasMore && len(page) > 0 {
+				last := page[len(page)-1]
+				nextCursor = data.EncodeCursor(data.Cursor{
+					Scope:  scope,
+					Keyset: data.Keyset{CreatedAt: last.post.CreatedAt, ID: last.post.ID},
+					Origin: origin,
+					Rank:   &data.CursorRank{AsOf: asOf, Score: last.score},
+				})
+			}
 		}
 
-		EnrichPosts(c.Request.Context(), posts, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
+		EnrichPosts(c.Request.Context(), posts, repo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
 
 		c.JSON(http.StatusOK, data.PaginatedResponse{
 			Data:       posts,
@@ -328,8 +360,47 @@ func GetFeed(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 	}
 }
 
+// enrichPostDetails fills in everything GetPost shows about a single post for
+// the current user: location, likes and reactions, counts, the quoted post,
+// mentions, the poll and bookmark state
+func enrichPostDetails(ctx context.Context, post *data.Post, postRepo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, currentUserID string, store storage.MediaStore) *data.Post {
+	// Enrich with location name
+	if locRepo != nil && post.HasLocation() {
+		geohashPrefix := data.GetGeohashPrefix(post.Latitude, post.Longitude)
+		locName, err := locRepo.GetOrFetch(ctx, geohashPrefix, post.Latitude, post.Longitude)
+		if err == nil && locName != nil {
+			post.SetLocation(locName)
+		}
+	}
+
+	// Enrich with like and reaction info
+	if likeRepo != nil {
+		likeInfoMap, _ := likeRepo.GetLikesForPosts(ctx, []string{post.ID}, currentUserID)
+		if info, ok := likeInfoMap[post.ID]; ok {
+			post.LikeCount = info.LikeCount
+			post.IsLiked = info.IsLiked
+			post.ReactionCounts = info.ReactionCounts
+			post.MyReaction = info.MyReaction
+		}
+	}
+
+	if commentRepo != nil {
+		commentCount, _ := commentRepo.GetCommentCount(ctx, post.ID)
+		post.CommentCount = commentCount
+	}
+
+	// Enrich with repost info, the quoted post, mentions, the poll and bookmark state
+	enriched := []data.Post{*post}
+	enrichRepostInfo(ctx, enriched, postRepo, currentUserID)
+	attachQuotedPosts(ctx, enriched, postRepo, userRepo, locRepo, currentUserID, store)
+	attachMentions(ctx, enriched, postRepo)
+	attachPolls(ctx, enriched, pollRepo, currentUserID)
+	attachBookmarks(ctx, enriched, bookmarkRepo, currentUserID)
+	return &enriched[0]
+}
+
 // GetPost handles GET /api/v1/posts/:id
-func GetPost(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
+func GetPost(repo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
@@ -348,6 +419,23 @@ func GetPost(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 			return
 		}
 
+		// Posts outside the caller's audience look the same as missing ones
+		currentUserID := auth.GetUserID(c)
+		canView, err := repo.CanViewPost(c.Request.Context(), post, currentUserID)
+		if err != nil {
+			slog.Error("Failed to check post visibility", "error", err, "post_id", id)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch post",
+			})
+			return
+		}
+		if !canView {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Post not found",
+			})
+			return
+		}
+
 		// Fetch user details
 		user, err := userRepo.GetUserByID(c.Request.Context(), post.UserID)
 		if err != nil {
@@ -357,32 +445,7 @@ func GetPost(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 			_ = err // SA9003 empty branch workaround
 		}
 
-		// Enrich with location name
-		if locRepo != nil {
-			geohashPrefix := data.GetGeohashPrefix(post.Latitude, post.Longitude)
-			locName, err := locRepo.GetOrFetch(c.Request.Context(), geohashPrefix, post.Latitude, post.Longitude)
-			if err == nil && locName != nil {
-				post.LocationName = locName.Name
-				post.Address = &locName.Address
-			}
-		}
-
-		// Enrich with like info
-		currentUserID := auth.GetUserID(c)
-		if likeRepo != nil {
-			likeCount, _ := likeRepo.GetLikeCount(c.Request.Context(), data.TargetTypePost, id)
-			post.LikeCount = likeCount
-
-			if currentUserID != "" {
-				isLiked, _ := likeRepo.HasUserLiked(c.Request.Context(), data.TargetTypePost, id, currentUserID)
-				post.IsLiked = isLiked
-			}
-		}
-
-		if commentRepo != nil {
-			commentCount, _ := commentRepo.GetCommentCount(c.Request.Context(), id)
-			post.CommentCount = commentCount
-		}
+		post = enrichPostDetails(c.Request.Context(), post, repo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
 
 		response := gin.H{}
 
@@ -404,7 +467,7 @@ func GetPost(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *
 }
 
 // GetUserPosts handles GET /api/v1/users/:id/posts
-func GetUserPosts(repo *data.PostRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
+func GetUserPosts(repo *data.PostRepository, followRepo *data.FollowRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := c.Param("id")
 
@@ -416,6 +479,9 @@ func GetUserPosts(repo *data.PostRepository, userRepo *data.UserRepository, locR
 			})
 			return
 		}
+		if !requireProfileAccess(c, followRepo, user) {
+			return
+		}
 
 		// Parse query parameters
 		cursor := c.Query("cursor")
@@ -424,7 +490,7 @@ func GetUserPosts(repo *data.PostRepository, userRepo *data.UserRepository, locR
 		fmt.Sscanf(limitStr, "%d", &limit) //nolint:errcheck
 
 		// Decode cursor for pagination
-		cursorTime, err := data.DecodeCursor(cursor)
+		decoded, err := data.DecodeCursor(data.CursorScopeUserPosts, cursor)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": "Invalid cursor",
@@ -435,8 +501,17 @@ func GetUserPosts(repo *data.PostRepository, userRepo *data.UserRepository, locR
 		// Apply default limit
 		limit = data.GetDefaultLimit(limit, 20, 100)
 
-		// Fetch one extra to determine if there are more posts
-		posts, err := repo.GetPostsByUser(c.Request.Context(), userID, limit+1, cursorTime)
+		// Get current user ID for like status and audience checks
+		currentUserID := auth.GetUserID(c)
+
+		// Fetch one extra to determine if there are more posts, skipping posts
+		// outside the caller's audience
+		posts, next, err := collectPosts(func(after data.Keyset, n int) ([]data.Post, data.Keyset, error) {
+			batch, err := repo.GetPostsByUser(c.Request.Context(), userID, n, after)
+			return batch, batchResume(batch, n), err
+		}, decoded.Keyset, limit+1, limit, func(batch []data.Post) []data.Post {
+			return repo.FilterVisiblePosts(c.Request.Context(), batch, currentUserID)
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "Failed to fetch user posts",
@@ -444,66 +519,17 @@ func GetUserPosts(repo *data.PostRepository, userRepo *data.UserRepository, locR
 			return
 		}
 
-		// Determine if there are more posts
-		hasMore := len(posts) > limit
-		if hasMore {
-			posts = posts[:limit]
-		}
-
-		// Generate next cursor from last post's created_at
+		// Generate next cursor from where this page stopped
+		hasMore := next != nil
 		var nextCursor string
-		if hasMore && len(posts) > 0 {
-			nextCursor = data.EncodeCursor(posts[len(posts)-1].CreatedAt)
-		}
-
-		// Get current user ID for like status
-		currentUserID := auth.GetUserID(c)
-
-		// Enrich posts with location and like info
-		if len(posts) > 0 {
-			geohashes := make([]string, 0, len(posts))
-			latLngMap := make(map[string][2]float64)
-			postIDs := make([]string, 0, len(posts))
-
-			for _, p := range posts {
-				postIDs = append(postIDs, p.ID)
-				geohashPrefix := data.GetGeohashPrefix(p.Latitude, p.Longitude)
-				geohashes = append(geohashes, geohashPrefix)
-				latLngMap[geohashPrefix] = [2]float64{p.Latitude, p.Longitude}
-			}
-
-			// Enrich with location info
-			if locRepo != nil {
-				locInfoMap, _ := locRepo.GetLocationsByGeohashes(c.Request.Context(), geohashes, latLngMap)
-				for i := range posts {
-					geohashPrefix := data.GetGeohashPrefix(posts[i].Latitude, posts[i].Longitude)
-					if loc, ok := locInfoMap[geohashPrefix]; ok {
-						posts[i].LocationName = loc.Name
-						posts[i].Address = &loc.Address
-					}
-				}
-			}
-
-			// Enrich with like info
-			if likeRepo != nil {
-				likeInfoMap, _ := likeRepo.GetLikesForPosts(c.Request.Context(), postIDs, currentUserID)
-				for i := range posts {
-					if info, ok := likeInfoMap[posts[i].ID]; ok {
-						posts[i].LikeCount = info.LikeCount
-						posts[i].IsLiked = info.IsLiked
-					}
-				}
-			}
-
-			if commentRepo != nil {
-				commentCounts, _ := commentRepo.GetCommentCountsForPosts(c.Request.Context(), postIDs)
-				for i := range posts {
-					posts[i].CommentCount = commentCounts[posts[i].ID]
-				}
-			}
+		if hasMore {
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeUserPosts,
+				Keyset: *next,
+			})
 		}
 
-		ResolvePostsMediaURLs(store, posts)
+		EnrichPosts(c.Request.Context(), posts, repo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
 		ResolveUserMediaURLs(store, user)
 
 		c.JSON(http.StatusOK, gin.H{
@@ -521,8 +547,145 @@ func GetUserPosts(repo *data.PostRepository, userRepo *data.UserRepository, locR
 	}
 }
 
+// UpdatePost handles PUT /api/v1/posts/:id
+func UpdatePost(postRepo *data.PostRepository, mentioner *Mentioner, postIndexer search.PostIndexer, store storage.MediaStore, editWindow time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.UpdatePostRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		// Validate media (max 4 total)
+		if !req.ValidateMedia() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Maximum 4 media items allowed",
+			})
+			return
+		}
+
+		if req.ReplacesMedia() {
+			mediaURLs, err := preparePostMedia(store, userID, req.MediaURLs, req.MediaKeys)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			req.MediaURLs = mediaURLs
+		}
+
+		post, err := postRepo.UpdatePost(c.Request.Context(), postID, userID, &req, editWindow)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+				return
+			}
+			if strings.Contains(err.Error(), "forbidden") {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+				return
+			}
+			if strings.Contains(err.Error(), "edit window") {
+				c.JSON(http.StatusForbidden, gin.H{"error": "This post can no longer be edited"})
+				return
+			}
+			if strings.Contains(err.Error(), "invalid post_id") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+				return
+			}
+			slog.Error("Failed to update post", "error", err, "post_id", postID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+			return
+		}
+
+		// Only users newly mentioned by the edit are notified
+		if mentioner != nil {
+			post.Mentions = mentioner.Record(c.Request.Context(), data.MentionTarget{
+				Type:     data.TargetTypePost,
+				ID:       post.ID,
+				PostID:   post.ID,
+				AuthorID: post.UserID,
+			}, post, post.Content, true)
+		}
+
+		// Only posts open to everyone are searchable
+		open := false
+		if postIndexer != nil {
+			if open, err = postRepo.IsOpenToEveryone(c.Request.Context(), post); err != nil {
+				slog.Warn("failed to check author privacy", "post_id", post.ID, "error", err)
+			}
+		}
+		if open {
+			event := &search.PostUpdatedEvent{
+				PostID:   post.ID,
+				UserID:   post.UserID,
+				Content:  post.Content,
+				Hashtags: search.ExtractHashtags(post.Content),
+				EditedAt: *post.EditedAt,
+			}
+			go func() {
+				if err := postIndexer.PublishPostUpdated(context.Background(), event); err != nil {
+					slog.Warn("failed to publish post updated event for search indexing",
+						"post_id", post.ID,
+						"error", err,
+					)
+				}
+			}()
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Post updated successfully",
+			"post":    resolvePostForResponse(store, post),
+		})
+	}
+}
+
+// GetPostRevisions handles GET /api/v1/posts/:id/revisions
+// A post's edit history is as visible as the post itself
+func GetPostRevisions(postRepo *data.PostRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+
+		if _, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to fetch post revisions"); !ok {
+			return
+		}
+
+		limit := 20
+		if l := c.Query("limit"); l != "" {
+			fmt.Sscanf(l, "%d", &limit) //nolint:errcheck
+		}
+
+		revisions, err := postRepo.GetPostRevisions(c.Request.Context(), postID, limit)
+		if err != nil {
+			slog.Error("Failed to fetch post revisions", "error", err, "post_id", postID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch post revisions"})
+			return
+		}
+
+		for i := range revisions {
+			revisions[i].MediaURLs = storage.ResolveMediaURLs(store, revisions[i].MediaURLs)
+		}
+		if revisions == nil {
+			revisions = []data.PostRevision{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":  revisions,
+			"count": len(revisions),
+		})
+	}
+}
+
 // DeletePost handles DELETE /api/v1/posts/:id
-func DeletePost(postRepo *data.PostRepository) gin.HandlerFunc {
+func DeletePost(postRepo *data.PostRepository, postIndexer search.PostIndexer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		postID := c.Param("id")
 		userID := auth.GetUserID(c)
@@ -547,6 +710,22 @@ func DeletePost(postRepo *data.PostRepository) gin.HandlerFunc {
 			return
 		}
 
+		if postIndexer != nil {
+			event := &search.PostDeletedEvent{
+				PostID:    postID,
+				UserID:    userID,
+				DeletedAt: time.Now(),
+			}
+			go func() {
+				if err := postIndexer.PublishPostDeleted(context.Background(), event); err != nil {
+					slog.Warn("failed to publish post deleted event for search indexing",
+						"post_id", postID,
+						"error", err,
+					)
+				}
+			}()
+		}
+
 		c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
 	}
 }
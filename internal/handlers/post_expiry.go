@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/search"
+)
+
+// expirySweepBatch caps how many expired posts one sweep cleans up
+const expirySweepBatch = 500
+
+// RunPostExpirySweeper cleans up expired ephemeral posts every interval until
+// ctx is cancelled. The TTL removes the posts themselves; each sweep removes
+// their likes, comments and counters and publishes a post deleted event so the
+// search document goes too. Sweeps are idempotent, so several API instances
+// may run one.
+func RunPostExpirySweeper(ctx context.Context, postRepo *data.PostRepository, postIndexer search.PostIndexer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepExpiredPosts(ctx, postRepo, postIndexer)
+		}
+	}
+}
+
+func sweepExpiredPosts(ctx context.Context, postRepo *data.PostRepository, postIndexer search.PostIndexer) {
+	swept, err := postRepo.SweepExpiredPosts(ctx, time.Now(), expirySweepBatch)
+	if err != nil {
+		slog.Error("Failed to sweep expired posts", "error", err, "swept", len(swept))
+	}
+	if len(swept) == 0 {
+		return
+	}
+	slog.Info("Swept expired posts", "count", len(swept))
+
+	if postIndexer == nil {
+		return
+	}
+	for _, p := range swept {
+		event := &search.PostDeletedEvent{
+			PostID:    p.PostID,
+			UserID:    p.UserID,
+			DeletedAt: p.ExpiresAt,
+		}
+		if err := postIndexer.PublishPostDeleted(ctx, event); err != nil {
+			slog.Warn("failed to publish post deleted event for expired post",
+				"post_id", p.PostID,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/cache"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+	"social-geo-go/internal/search"
+	"social-geo-go/internal/storage"
+)
+
+// PostPublisher creates posts together with their side effects: home timeline
+// fan-out, mentions, nearby and hashtag notifications, trending counts and
+// search indexing. POST /api/v1/posts and the scheduled post worker both
+// publish through it.
+type PostPublisher struct {
+	Posts     *data.PostRepository
+	Users     *data.UserRepository
+	Zones     *data.PrivacyZoneRepository
+	Timeline  *data.TimelineRepository
+	Notifier  *notifications.NotificationDispatcher
+	Indexer   search.PostIndexer
+	Store     storage.MediaStore
+	Mentioner *Mentioner
+	Trending  *cache.TrendingCounter
+}
+
+// postInputError is a problem with a post that its author has to fix
+type postInputError struct{ msg string }
+
+func (e *postInputError) Error() string { return e.msg }
+
+// validatePostRequest checks the fields of a post and fills in the defaults
+// for visibility and location precision
+func validatePostRequest(req *data.CreatePostRequest) error {
+	if req.Latitude < -90 || req.Latitude > 90 {
+		return &postInputError{"Latitude must be between -90 and 90"}
+	}
+	if req.Longitude < -180 || req.Longitude > 180 {
+		return &postInputError{"Longitude must be between -180 and 180"}
+	}
+
+	// Validate media (max 4 total)
+	if !req.ValidateMedia() {
+		return &postInputError{"Maximum 4 media items allowed"}
+	}
+
+	if req.Visibility == "" {
+		req.Visibility = data.VisibilityPublic
+	}
+	if !data.ValidVisibilities[req.Visibility] {
+		return &postInputError{"Visibility must be one of public, followers, close_friends, only_me"}
+	}
+
+	if req.LocationPrecision == "" {
+		req.LocationPrecision = data.LocationPrecisionExact
+	}
+	if !data.ValidLocationPrecision(req.LocationPrecision) {
+		return &postInputError{"Location precision must be one of exact, neighbourhood, city, hidden"}
+	}
+
+	if req.ExpiresIn != 0 {
+		lifetime := time.Duration(req.ExpiresIn) * time.Hour
+		if lifetime < data.MinPostLifetime || lifetime > data.MaxPostLifetime {
+			return &postInputError{"expires_in must be between 1 and 48 hours"}
+		}
+	}
+
+	if req.QuotedPostID != "" {
+		if _, err := gocql.ParseUUID(req.QuotedPostID); err != nil {
+			return &postInputError{"Invalid quoted_post_id"}
+		}
+	}
+
+	if req.Poll != nil {
+		for i, option := range req.Poll.Options {
+			req.Poll.Options[i] = strings.TrimSpace(option)
+		}
+		now := time.Now()
+		if err := req.Poll.Validate(now); err != nil {
+			return &postInputError{"Invalid poll: " + err.Error()}
+		}
+		if req.ExpiresIn != 0 && req.Poll.ClosesAt.After(now.Add(time.Duration(req.ExpiresIn)*time.Hour)) {
+			return &postInputError{"Invalid poll: closes_at must not be after the post expires"}
+		}
+	}
+	return nil
+}
+
+// Publish validates and creates req's post. req.UserID must already be the
+// authenticated author. Errors the author has to fix are *postInputError.
+func (p *PostPublisher) Publish(ctx context.Context, req *data.CreatePostRequest) (*data.Post, error) {
+	if err := validatePostRequest(req); err != nil {
+		return nil, err
+	}
+
+	// A quote post may only quote a post its author is allowed to share
+	var quoted *data.Post
+	if req.QuotedPostID != "" {
+		var err error
+		quoted, err = p.Posts.GetPostByID(ctx, req.QuotedPostID)
+		if err == nil {
+			err = p.Posts.CheckCanShare(ctx, quoted, req.UserID)
+		}
+		switch {
+		case err == nil:
+		case strings.Contains(err.Error(), "not found"):
+			return nil, &postInputError{"Quoted post not found"}
+		case strings.Contains(err.Error(), "forbidden"):
+			return nil, &postInputError{"Only public posts can be quoted"}
+		default:
+			return nil, fmt.Errorf("failed to check quoted post: %w", err)
+		}
+	}
+
+	mediaURLs, err := preparePostMedia(p.Store, req.UserID, req.MediaURLs, req.MediaKeys)
+	if err != nil {
+		return nil, &postInputError{err.Error()}
+	}
+	req.MediaURLs = mediaURLs
+	req.MediaKeys = nil
+
+	// Posts inside one of the author's privacy zones are coarsened further.
+	// If the zones cannot be read, the post is not published at all.
+	if p.Zones != nil {
+		precision, err := p.Zones.ResolveLocationPrecision(ctx, req.UserID, req.Latitude, req.Longitude, req.LocationPrecision)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check privacy zones: %w", err)
+		}
+		req.LocationPrecision = precision
+	}
+
+	post, err := p.Posts.CreatePost(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Mentioner != nil {
+		post.Mentions = p.Mentioner.Record(ctx, data.MentionTarget{
+			Type:     data.TargetTypePost,
+			ID:       post.ID,
+			PostID:   post.ID,
+			AuthorID: post.UserID,
+		}, post, post.Content, false)
+	}
+
+	// only_me posts have no audience to fan out to
+	if p.Timeline != nil && post.Visibility != data.VisibilityOnlyMe {
+		go func() {
+			if err := p.Timeline.FanOutPost(context.Background(), post); err != nil {
+				slog.Warn("failed to fan out post to home timelines",
+					"post_id", post.ID,
+					"error", err,
+				)
+			}
+		}()
+	}
+
+	// Public posts of private accounts only reach followers. If the account
+	// cannot be read, the post is kept away from strangers.
+	open, err := p.Posts.IsOpenToEveryone(ctx, post)
+	if err != nil {
+		slog.Warn("failed to check author privacy",
+			"post_id", post.ID,
+			"error", err,
+		)
+	}
+
+	// Nearby notifications go to strangers, so only public posts with a
+	// location trigger them. The job carries the coarsened geohash.
+	if p.Notifier != nil && open && post.HasLocation() {
+		contentTruncated := post.Content
+		if len(contentTruncated) > 100 {
+			contentTruncated = contentTruncated[:100] + "..."
+		}
+		go p.Notifier.DispatchNearbyFanout(context.Background(), &kafka.NearbyFanoutJob{
+			EventID:    gocql.TimeUUID().String(),
+			PostID:     post.ID,
+			AuthorID:   post.UserID,
+			Geohash:    post.Geohash,
+			Content:    contentTruncated,
+			Visibility: post.Visibility,
+			CreatedAt:  time.Now().Format(time.RFC3339),
+		})
+	}
+
+	// Followers of the post's hashtags are notified of public posts, the
+	// ones hashtag pages list
+	if p.Notifier != nil && open {
+		if tags := data.PostHashtags(post.Content); len(tags) > 0 {
+			go p.Notifier.DispatchHashtagFanout(context.Background(), &kafka.HashtagFanoutJob{
+				EventID:   gocql.TimeUUID().String(),
+				PostID:    post.ID,
+				AuthorID:  post.UserID,
+				Hashtags:  tags,
+				Content:   truncateText(post.Content, 100),
+				CreatedAt: time.Now().Format(time.RFC3339),
+			})
+		}
+	}
+
+	if p.Notifier != nil && quoted != nil && quoted.UserID != post.UserID {
+		go p.Notifier.Dispatch(context.Background(), &kafka.NotificationEvent{
+			EventID:     gocql.TimeUUID().String(),
+			EventType:   data.NotificationTypeQuote,
+			ActorID:     post.UserID,
+			RecipientID: quoted.UserID,
+			TargetType:  data.TargetTypePost,
+			TargetID:    post.ID,
+			Message:     "quoted your post",
+			Payload: map[string]string{
+				"post_preview":   truncateText(post.Content, 100),
+				"quoted_post_id": quoted.ID,
+			},
+			CreatedAt: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	// Search and trending are open to everyone, so only posts open to everyone
+	// feed them
+	if open && (p.Indexer != nil || p.Trending != nil) {
+		event := &search.PostCreatedEvent{
+			PostID:    post.ID,
+			UserID:    post.UserID,
+			Content:   post.Content,
+			Hashtags:  search.ExtractHashtags(post.Content),
+			Lat:       post.Latitude,
+			Lon:       post.Longitude,
+			Geohash:   post.Geohash,
+			CreatedAt: post.CreatedAt,
+			LikeCount: 0,
+		}
+
+		// Trending counts the post in its region by its coarsened location
+		if p.Trending != nil && post.HasLocation() && len(event.Hashtags) > 0 {
+			tags := event.Hashtags
+			if len(tags) > data.MaxPostHashtags {
+				tags = tags[:data.MaxPostHashtags]
+			}
+			cells := data.TrendingCells(event.Lat, event.Lon)
+			go func() {
+				if err := p.Trending.RecordHashtags(context.Background(), cells, tags, event.CreatedAt); err != nil {
+					slog.Warn("failed to record trending hashtags",
+						"post_id", post.ID,
+						"error", err,
+					)
+				}
+			}()
+		}
+
+		if p.Indexer != nil {
+			if user, err := p.Users.GetUserByID(context.Background(), post.UserID); err == nil && user != nil {
+				event.Username = user.Username
+			}
+			go func() {
+				if err := p.Indexer.PublishPostCreated(context.Background(), event); err != nil {
+					slog.Warn("failed to publish post created event for search indexing",
+						"post_id", post.ID,
+						"error", err,
+					)
+				}
+			}()
+		}
+	}
+
+	return post, nil
+}
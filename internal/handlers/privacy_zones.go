@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+)
+
+// CreatePrivacyZone handles POST /api/v1/users/me/privacy-zones
+func CreatePrivacyZone(zoneRepo *data.PrivacyZoneRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.CreatePrivacyZoneRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if req.Latitude < -90 || req.Latitude > 90 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Latitude must be between -90 and 90"})
+			return
+		}
+		if req.Longitude < -180 || req.Longitude > 180 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Longitude must be between -180 and 180"})
+			return
+		}
+
+		zone, err := zoneRepo.CreatePrivacyZone(c.Request.Context(), userID, &req)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "too many") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			slog.Error("Failed to create privacy zone", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create privacy zone"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"privacy_zone": zone})
+	}
+}
+
+// GetPrivacyZones handles GET /api/v1/users/me/privacy-zones
+func GetPrivacyZones(zoneRepo *data.PrivacyZoneRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		zones, err := zoneRepo.GetPrivacyZones(c.Request.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to get privacy zones", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get privacy zones"})
+			return
+		}
+
+		if zones == nil {
+			zones = []data.PrivacyZone{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"privacy_zones": zones,
+			"count":         len(zones),
+		})
+	}
+}
+
+// DeletePrivacyZone handles DELETE /api/v1/users/me/privacy-zones/:id
+func DeletePrivacyZone(zoneRepo *data.PrivacyZoneRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		err := zoneRepo.DeletePrivacyZone(c.Request.Context(), userID, c.Param("id"))
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Privacy zone not found"})
+				return
+			}
+			slog.Error("Failed to delete privacy zone", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete privacy zone"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Privacy zone deleted"})
+	}
+}
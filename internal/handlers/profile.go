@@ -17,6 +17,7 @@ import (
 func UpdateProfile(
 	userRepo *data.UserRepository,
 	followRepo *data.FollowRepository,
+	timelineRepo *data.TimelineRepository,
 	searchIndexer search.SearchIndexer,
 	store storage.MediaStore,
 ) gin.HandlerFunc {
@@ -87,6 +88,24 @@ func UpdateProfile(
 			return
 		}
 
+		if req.IsPrivate != nil && *req.IsPrivate != existing.IsPrivate {
+			if err := userRepo.SetPrivate(c.Request.Context(), userID, *req.IsPrivate); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to update profile",
+				})
+				return
+			}
+			// Going public lets everyone who asked in
+			if !*req.IsPrivate && followRepo != nil {
+				acceptPendingFollowRequests(c.Request.Context(), followRepo, timelineRepo, userID)
+			}
+		}
+		if req.IsPrivate != nil {
+			user.IsPrivate = *req.IsPrivate
+		} else {
+			user.IsPrivate = existing.IsPrivate
+		}
+
 		followerCount := 0
 		if followRepo != nil {
 			if counts, err := followRepo.GetFollowCounts(c.Request.Context(), userID); err == nil && counts != nil {
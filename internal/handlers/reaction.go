@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+)
+
+// reactionResponse renders a reaction result. like_count and is_liked keep
+// the like endpoints' fields for clients that only know likes.
+func reactionResponse(result *data.ReactionResult) gin.H {
+	return gin.H{
+		"my_reaction":     result.MyReaction,
+		"reaction_counts": result.ReactionCounts,
+		"like_count":      result.LikeCount(),
+		"is_liked":        result.MyReaction == data.ReactionLove,
+		"changed":         result.Changed,
+	}
+}
+
+// bindReaction reads and validates the reaction of a request body
+func bindReaction(c *gin.Context) (string, bool) {
+	var req data.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reaction is required"})
+		return "", false
+	}
+	reaction, err := data.NormalizeReaction(req.Reaction)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "Invalid reaction",
+			"reactions": data.ValidReactions,
+		})
+		return "", false
+	}
+	return reaction, true
+}
+
+// reactionNotification builds the notification for a first reaction to a
+// target. ❤️ is reported as a like.
+func reactionNotification(actorID, recipientID, targetType, targetID, reaction string, payload map[string]string) *kafka.NotificationEvent {
+	eventType, message := data.NotificationTypeReaction, "reacted "+reaction+" to your "+targetType
+	if reaction == data.ReactionLove {
+		eventType, message = data.NotificationTypeLike, "liked your "+targetType
+	}
+	return &kafka.NotificationEvent{
+		EventID:     gocql.TimeUUID().String(),
+		EventType:   eventType,
+		ActorID:     actorID,
+		RecipientID: recipientID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Message:     message,
+		Payload:     payload,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// SetPostReaction handles PUT /api/v1/posts/:id/reaction
+// Replaces any earlier reaction by the user; safe for retries
+func SetPostReaction(likeRepo *data.LikeRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		reaction, ok := bindReaction(c)
+		if !ok {
+			return
+		}
+
+		post, ok := requireVisiblePost(c, postRepo, postID, "Post not found", "Failed to react to post")
+		if !ok {
+			return
+		}
+
+		result, err := likeRepo.SetReaction(c.Request.Context(), data.TargetTypePost, postID, userID, reaction)
+		if err != nil {
+			slog.Error("SetPostReaction failed", "error", err, "post_id", postID, "user_id", userID, "reaction", reaction)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to react to post"})
+			return
+		}
+
+		// Changing one reaction for another does not notify again
+		if result.Changed && result.Previous == "" && post.UserID != userID && notifDispatcher != nil {
+			go notifDispatcher.Dispatch(context.Background(), reactionNotification(userID, post.UserID, data.TargetTypePost, postID, reaction,
+				map[string]string{"post_preview": truncateText(post.Content, 100)}))
+		}
+
+		c.JSON(http.StatusOK, reactionResponse(result))
+	}
+}
+
+// RemovePostReaction handles DELETE /api/v1/posts/:id/reaction
+func RemovePostReaction(likeRepo *data.LikeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		result, err := likeRepo.SetReaction(c.Request.Context(), data.TargetTypePost, postID, userID, "")
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+				return
+			}
+			slog.Error("RemovePostReaction failed", "error", err, "post_id", postID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reactionResponse(result))
+	}
+}
+
+// SetCommentReaction handles PUT /api/v1/comments/:id/reaction
+// Replaces any earlier reaction by the user; safe for retries
+func SetCommentReaction(likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, postRepo *data.PostRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		commentID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		reaction, ok := bindReaction(c)
+		if !ok {
+			return
+		}
+
+		comment, ok := requireVisibleComment(c, commentRepo, postRepo, commentID, "Failed to react to comment")
+		if !ok {
+			return
+		}
+
+		result, err := likeRepo.SetReaction(c.Request.Context(), data.TargetTypeComment, commentID, userID, reaction)
+		if err != nil {
+			slog.Error("SetCommentReaction failed", "error", err, "comment_id", commentID, "user_id", userID, "reaction", reaction)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to react to comment"})
+			return
+		}
+
+		// Changing one reaction for another does not notify again
+		if result.Changed && result.Previous == "" && comment.UserID != userID && notifDispatcher != nil {
+			go notifDispatcher.Dispatch(context.Background(), reactionNotification(userID, comment.UserID, data.TargetTypeComment, commentID, reaction,
+				map[string]string{"comment_preview": truncateText(comment.Content, 100)}))
+		}
+
+		c.JSON(http.StatusOK, reactionResponse(result))
+	}
+}
+
+// RemoveCommentReaction handles DELETE /api/v1/comments/:id/reaction
+func RemoveCommentReaction(likeRepo *data.LikeRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		commentID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		result, err := likeRepo.SetReaction(c.Request.Context(), data.TargetTypeComment, commentID, userID, "")
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+				return
+			}
+			slog.Error("RemoveCommentReaction failed", "error", err, "comment_id", commentID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reactionResponse(result))
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/notifications"
+	"social-geo-go/internal/notifications/kafka"
+	"social-geo-go/internal/storage"
+)
+
+// enrichRepostInfo fills repost counts and the viewer's repost state
+func enrichRepostInfo(ctx context.Context, posts []data.Post, postRepo *data.PostRepository, currentUserID string) {
+	if len(posts) == 0 {
+		return
+	}
+
+	postIDs := make([]string, len(posts))
+	for i, p := range posts {
+		postIDs[i] = p.ID
+	}
+
+	repostInfo, err := postRepo.GetRepostsForPosts(ctx, postIDs, currentUserID)
+	if err != nil {
+		slog.Warn("Failed to get repost info", "error", err)
+	}
+	for i := range posts {
+		if info, ok := repostInfo[posts[i].ID]; ok {
+			posts[i].RepostCount = info.RepostCount
+			posts[i].IsReposted = info.IsReposted
+		}
+	}
+}
+
+// attachQuotedPosts embeds the post each quote post quotes, with its author
+// and its own location. Quoted posts the viewer may not see (deleted,
+// expired, outside their audience or blocked) are marked unavailable instead.
+func attachQuotedPosts(ctx context.Context, posts []data.Post, postRepo *data.PostRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, currentUserID string, store storage.MediaStore) {
+	quotedByID, err := postRepo.GetQuotedPosts(ctx, posts, currentUserID)
+	if err != nil {
+		slog.Warn("Failed to get quoted posts", "error", err)
+	}
+
+	// Quoted posts are enriched like any other, minus their own quotes and counts
+	quoted := make([]data.Post, 0, len(quotedByID))
+	for _, q := range quotedByID {
+		quoted = append(quoted, q)
+	}
+	EnrichPosts(ctx, quoted, nil, nil, nil, userRepo, locRepo, nil, nil, currentUserID, store)
+	attachMentions(ctx, quoted, postRepo)
+	for _, q := range quoted {
+		quotedByID[q.ID] = q
+	}
+
+	for i := range posts {
+		if posts[i].QuotedPostID == "" {
+			continue
+		}
+		if q, ok := quotedByID[posts[i].QuotedPostID]; ok {
+			posts[i].QuotedPost = &q
+		} else {
+			posts[i].QuotedPostUnavailable = true
+		}
+	}
+}
+
+// respondShareError writes the response for a post that cannot be reposted or quoted
+func respondShareError(c *gin.Context, err error, postID string) {
+	switch {
+	case strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	case strings.Contains(err.Error(), "forbidden"):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only public posts can be reposted"})
+	default:
+		slog.Error("Failed to repost", "error", err, "post_id", postID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to repost"})
+	}
+}
+
+// RepostPost handles POST /api/v1/posts/:id/repost
+// Reposting an already reposted post is a no-op, so retries are safe
+func RepostPost(postRepo *data.PostRepository, timelineRepo *data.TimelineRepository, notifDispatcher *notifications.NotificationDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		post, err := postRepo.GetPostByID(c.Request.Context(), postID)
+		if err != nil {
+			respondShareError(c, err, postID)
+			return
+		}
+		if err := postRepo.CheckCanShare(c.Request.Context(), post, userID); err != nil {
+			respondShareError(c, err, postID)
+			return
+		}
+
+		result, err := postRepo.Repost(c.Request.Context(), postID, userID)
+		if err != nil {
+			respondShareError(c, err, postID)
+			return
+		}
+
+		if result.Changed {
+			if timelineRepo != nil {
+				go func() {
+					if err := timelineRepo.FanOutRepost(context.Background(), postID, userID, result.RepostedAt); err != nil {
+						slog.Warn("failed to fan out repost to home timelines",
+							"post_id", postID,
+							"user_id", userID,
+							"error", err,
+						)
+					}
+				}()
+			}
+
+			if notifDispatcher != nil && post.UserID != userID {
+				go notifDispatcher.Dispatch(context.Background(), &kafka.NotificationEvent{
+					EventID:     gocql.TimeUUID().String(),
+					EventType:   data.NotificationTypeRepost,
+					ActorID:     userID,
+					RecipientID: post.UserID,
+					TargetType:  data.TargetTypePost,
+					TargetID:    postID,
+					Message:     "reposted your post",
+					Payload:     map[string]string{"post_preview": truncateText(post.Content, 100)},
+					CreatedAt:   time.Now().Format(time.RFC3339),
+				})
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// UnrepostPost handles DELETE /api/v1/posts/:id/repost
+func UnrepostPost(postRepo *data.PostRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID := c.Param("id")
+		userID := auth.GetUserID(c)
+
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		result, err := postRepo.Unrepost(c.Request.Context(), postID, userID)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+				return
+			}
+			slog.Error("Failed to remove repost", "error", err, "post_id", postID, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove repost"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"social-geo-go/internal/data"
+)
+
+// scheduledPostBatch caps how many due drafts one tick publishes
+const scheduledPostBatch = 100
+
+// RunScheduledPostWorker publishes scheduled drafts that are due every
+// interval until ctx is cancelled. Drafts are published through the same
+// PostPublisher as POST /api/v1/posts. Each due draft is locked while it is
+// published, so several API instances may run a worker.
+func RunScheduledPostWorker(ctx context.Context, draftRepo *data.DraftRepository, publisher *PostPublisher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			publishDueDrafts(ctx, draftRepo, publisher)
+		}
+	}
+}
+
+func publishDueDrafts(ctx context.Context, draftRepo *data.DraftRepository, publisher *PostPublisher) {
+	now := time.Now()
+	due, err := draftRepo.ClaimDueDrafts(ctx, now, scheduledPostBatch)
+	if err != nil {
+		slog.Error("Failed to claim scheduled drafts", "error", err, "claimed", len(due))
+	}
+
+	for _, d := range due {
+		publishScheduledDraft(ctx, draftRepo, publisher, d, now)
+	}
+}
+
+func publishScheduledDraft(ctx context.Context, draftRepo *data.DraftRepository, publisher *PostPublisher, d data.ScheduledDraft, now time.Time) {
+	draft, err := draftRepo.GetDraft(ctx, d.UserID, d.DraftID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			// Deleted or already published; drop the stale schedule entry
+			_ = draftRepo.Unschedule(ctx, d.UserID, d.DraftID)
+			return
+		}
+		slog.Error("Failed to load scheduled draft", "error", err, "draft_id", d.DraftID)
+		return
+	}
+
+	// Unscheduled or moved to a later time since it was claimed
+	if draft.PublishAt == nil || draft.PublishAt.After(now) {
+		return
+	}
+
+	saved := draft.SaveRequest()
+	if !saved.IsComplete() {
+		recordDraftPublishError(ctx, draftRepo, draft, "Drafts need content, latitude and longitude to be published")
+		return
+	}
+
+	post, err := publishDraftOnce(ctx, draftRepo, publisher, draft, saved.PostRequest(draft.UserID))
+	if errors.Is(err, errDraftPublished) {
+		// An earlier run published it but could not clean up
+		if err := draftRepo.DeleteDraft(ctx, draft.UserID, draft.ID); err != nil {
+			slog.Warn("failed to delete published draft", "draft_id", draft.ID, "error", err)
+		}
+		return
+	}
+	if err != nil {
+		var inputErr *postInputError
+		if errors.As(err, &inputErr) {
+			recordDraftPublishError(ctx, draftRepo, draft, inputErr.Error())
+			return
+		}
+		// Left scheduled; retried once the claim lock expires
+		slog.Error("Failed to publish scheduled draft", "error", err, "draft_id", draft.ID, "user_id", draft.UserID)
+		return
+	}
+
+	if err := draftRepo.DeleteDraft(ctx, draft.UserID, draft.ID); err != nil {
+		slog.Warn("failed to delete published draft",
+			"draft_id", draft.ID,
+			"post_id", post.ID,
+			"error", err,
+		)
+	}
+	slog.Info("Published scheduled post", "draft_id", draft.ID, "post_id", post.ID, "user_id", draft.UserID)
+}
+
+// errDraftPublished is returned for a draft whose post already exists
+var errDraftPublished = errors.New("draft already published")
+
+// publishDraftOnce publishes draft as the post ID recorded on it, recording a
+// new one first if there is none. A retry after a failure or a lost cleanup
+// reuses the ID, so the draft never becomes two posts.
+func publishDraftOnce(ctx context.Context, draftRepo *data.DraftRepository, publisher *PostPublisher, draft *data.PostDraft, req *data.CreatePostRequest) (*data.Post, error) {
+	proposed := gocql.TimeUUID().String()
+	postID, err := draftRepo.ClaimPublish(ctx, draft.UserID, draft.ID, draft.CreatedAt, proposed)
+	if err != nil {
+		return nil, err
+	}
+	if postID != proposed {
+		_, err := publisher.Posts.GetPostByID(ctx, postID)
+		if err == nil {
+			return nil, errDraftPublished
+		}
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+	}
+
+	req.PostID = postID
+	return publisher.Publish(ctx, req)
+}
+
+func recordDraftPublishError(ctx context.Context, draftRepo *data.DraftRepository, draft *data.PostDraft, reason string) {
+	slog.Warn("scheduled draft cannot be published",
+		"draft_id", draft.ID,
+		"user_id", draft.UserID,
+		"reason", reason,
+	)
+	if err := draftRepo.SetPublishError(ctx, draft.UserID, draft.ID, reason); err != nil {
+		slog.Error("Failed to record draft publish error", "error", err, "draft_id", draft.ID)
+	}
+}
@@ -58,7 +58,7 @@ func SearchUsers(userRepo *data.UserRepository, store storage.MediaStore) gin.Ha
 }
 
 // SearchPosts handles GET /api/v1/search/posts (legacy Cassandra-backed search)
-func SearchPosts(postRepo *data.PostRepository, userRepo *data.UserRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
+func SearchPosts(postRepo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		query := strings.TrimSpace(c.Query("q"))
 		if query == "" {
@@ -83,42 +83,10 @@ func SearchPosts(postRepo *data.PostRepository, userRepo *data.UserRepository, l
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
 			return
 		}
+		currentUserID := auth.GetUserID(c)
+		posts = postRepo.FilterVisiblePosts(c.Request.Context(), posts, currentUserID)
 
-		// Enrich posts with author info
-		if len(posts) > 0 {
-			postIDs := make([]string, 0, len(posts))
-			userIDs := make([]string, 0, len(posts))
-			for _, p := range posts {
-				userIDs = append(userIDs, p.UserID)
-				postIDs = append(postIDs, p.ID)
-			}
-			userInfoMap, _ := userRepo.GetUsersByIDs(c.Request.Context(), userIDs)
-
-			currentUserID, _ := c.Get("user_id")
-			var uid string
-			if id, ok := currentUserID.(string); ok {
-				uid = id
-			}
-
-			likeInfo, _ := likeRepo.GetLikesForPosts(c.Request.Context(), postIDs, uid)
-			commentCounts := map[string]int64{}
-			if commentRepo != nil {
-				commentCounts, _ = commentRepo.GetCommentCountsForPosts(c.Request.Context(), postIDs)
-			}
-
-			for i := range posts {
-				if info, ok := userInfoMap[posts[i].UserID]; ok {
-					posts[i].Username = info.Username
-					posts[i].ProfilePictureURL = storage.ResolveMediaURL(store, info.ProfilePictureURL)
-				}
-				if info, ok := likeInfo[posts[i].ID]; ok {
-					posts[i].IsLiked = info.IsLiked
-					posts[i].LikeCount = info.LikeCount
-				}
-				posts[i].CommentCount = commentCounts[posts[i].ID]
-			}
-			ResolvePostsMediaURLs(store, posts)
-		}
+		EnrichPosts(c.Request.Context(), posts, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
 
 		c.JSON(http.StatusOK, gin.H{
 			"query":   query,
@@ -130,19 +98,24 @@ func SearchPosts(postRepo *data.PostRepository, userRepo *data.UserRepository, l
 
 // NewSearchHandler holds dependencies for the new ES-backed search routes.
 type NewSearchHandler struct {
-	svc         search.Service
-	session     *gocql.Session
-	userRepo    *data.UserRepository
-	locRepo     *data.LocationRepository
-	likeRepo    *data.LikeRepository
-	commentRepo *data.CommentRepository
-	mediaStore  storage.MediaStore
+	svc          search.Service
+	session      *gocql.Session
+	postRepo     *data.PostRepository
+	pollRepo     *data.PollRepository
+	bookmarkRepo *data.BookmarkRepository
+	userRepo     *data.UserRepository
+	locRepo      *data.LocationRepository
+	likeRepo     *data.LikeRepository
+	commentRepo  *data.CommentRepository
+	mediaStore   storage.MediaStore
 }
 
 // NewNewSearchHandler creates a new NewSearchHandler.
 func NewNewSearchHandler(
 	svc search.Service,
 	session *gocql.Session,
+	pollRepo *data.PollRepository,
+	bookmarkRepo *data.BookmarkRepository,
 	userRepo *data.UserRepository,
 	locRepo *data.LocationRepository,
 	likeRepo *data.LikeRepository,
@@ -150,13 +123,16 @@ func NewNewSearchHandler(
 	mediaStore storage.MediaStore,
 ) *NewSearchHandler {
 	return &NewSearchHandler{
-		svc:         svc,
-		session:     session,
-		userRepo:    userRepo,
-		locRepo:     locRepo,
-		likeRepo:    likeRepo,
-		commentRepo: commentRepo,
-		mediaStore:  mediaStore,
+		svc:          svc,
+		session:      session,
+		postRepo:     data.NewPostRepository(session, nil),
+		pollRepo:     pollRepo,
+		bookmarkRepo: bookmarkRepo,
+		userRepo:     userRepo,
+		locRepo:      locRepo,
+		likeRepo:     likeRepo,
+		commentRepo:  commentRepo,
+		mediaStore:   mediaStore,
 	}
 }
 
@@ -175,8 +151,8 @@ func (h *NewSearchHandler) hydrateAndEnrichPosts(
 		}
 	}
 
-	hydratedPosts, _ := search.HydratePosts(ctx, postIDs, h.session)
-	EnrichPosts(ctx, hydratedPosts, h.userRepo, h.locRepo, h.likeRepo, h.commentRepo, currentUserID, h.mediaStore)
+	hydratedPosts, _ := search.HydratePosts(ctx, postIDs, h.session, currentUserID)
+	EnrichPosts(ctx, hydratedPosts, h.postRepo, h.pollRepo, h.bookmarkRepo, h.userRepo, h.locRepo, h.likeRepo, h.commentRepo, currentUserID, h.mediaStore)
 
 	if len(distanceByPostID) > 0 {
 		for i := range hydratedPosts {
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// Suggestion scoring. Each followed account who follows a candidate counts
+// the most; nearby posts count little and are capped so a prolific local
+// poster doesn't outrank mutual friends.
+const (
+	suggestionMutualWeight     = 3.0
+	suggestionFollowsYouWeight = 2.0
+	suggestionLocationWeight   = 2.0
+	suggestionNearbyWeight     = 0.5
+	suggestionMaxNearbyPosts   = 5
+)
+
+// Limits on how much of the graph one computation reads
+const (
+	suggestionFanout          = 100 // Followed accounts whose follows are read
+	suggestionFollowsPerUser  = 100 // Follows read per followed account
+	suggestionMaxLocations    = 10  // Followed locations considered
+	suggestionMaxAreas        = 3   // Areas the user posts from that are searched
+	suggestionNearbyRadiusKM  = 2.0
+	suggestionNearbyPostLimit = 50
+	suggestionRefreshBatch    = 50
+)
+
+// suggestionMaxAge is how old stored suggestions get before a read queues a
+// refresh
+const suggestionMaxAge = 24 * time.Hour
+
+// suggestionCandidate gathers the signals pointing at one account
+type suggestionCandidate struct {
+	userID      string
+	mutuals     []string // Followed accounts who follow them
+	followsYou  bool
+	places      []string // Followed locations they follow too
+	nearbyPosts int
+	nearbyPlace string
+}
+
+func (c *suggestionCandidate) score() float64 {
+	score := suggestionMutualWeight*float64(len(c.mutuals)) +
+		suggestionLocationWeight*float64(len(c.places)) +
+		suggestionNearbyWeight*float64(min(c.nearbyPosts, suggestionMaxNearbyPosts))
+	if c.followsYou {
+		score += suggestionFollowsYouWeight
+	}
+	return score
+}
+
+// suggestionFor turns a candidate into a suggestion explained by its
+// strongest signal
+func (c *suggestionCandidate) suggestionFor() data.Suggestion {
+	s := data.Suggestion{UserID: c.userID, Score: c.score()}
+	switch {
+	case len(c.mutuals) > 0:
+		s.Reason = data.SuggestionReasonMutual
+		s.MutualID = c.mutuals[0]
+		s.MutualCount = len(c.mutuals)
+	case c.followsYou:
+		s.Reason = data.SuggestionReasonFollowsYou
+	case len(c.places) > 0:
+		s.Reason = data.SuggestionReasonLocation
+		s.Place = c.places[0]
+	default:
+		s.Reason = data.SuggestionReasonNearby
+		s.Place = c.nearbyPlace
+	}
+	return s
+}
+
+// rankSuggestions orders candidates by score, highest first, leaving out
+// excluded accounts and those without any signal
+func rankSuggestions(candidates map[string]*suggestionCandidate, excluded map[string]bool, limit int) []data.Suggestion {
+	suggestions := make([]data.Suggestion, 0, len(candidates))
+	for id, c := range candidates {
+		if excluded[id] || c.score() <= 0 {
+			continue
+		}
+		suggestions = append(suggestions, c.suggestionFor())
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].UserID < suggestions[j].UserID
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// suggestionExplanation says why an account is suggested. mutualName is the
+// username behind MutualID.
+func suggestionExplanation(s data.Suggestion, mutualName string) string {
+	switch s.Reason {
+	case data.SuggestionReasonMutual:
+		if mutualName == "" {
+			return fmt.Sprintf("Followed by %d people you follow", s.MutualCount)
+		}
+		switch s.MutualCount {
+		case 1:
+			return "Followed by " + mutualName
+		case 2:
+			return fmt.Sprintf("Followed by %s and 1 other", mutualName)
+		default:
+			return fmt.Sprintf("Followed by %s and %d others", mutualName, s.MutualCount-1)
+		}
+	case data.SuggestionReasonFollowsYou:
+		return "Follows you"
+	case data.SuggestionReasonLocation:
+		return "Also follows " + s.Place
+	default:
+		if s.Place == "" {
+			return "Posts near you"
+		}
+		return "Posts near " + s.Place
+	}
+}
+
+// RunSuggestionWorker computes the suggestions of queued users every interval
+// until ctx is cancelled. Reads of missing or stale suggestions queue the
+// user. Computing a user twice is harmless, so several API instances may run
+// the worker.
+func RunSuggestionWorker(ctx context.Context, suggestionRepo *data.SuggestionRepository, followRepo *data.FollowRepository, locFollowRepo *data.LocationFollowRepository, postRepo *data.PostRepository, locRepo *data.LocationRepository, modRepo *data.ModerationRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshQueuedSuggestions(ctx, suggestionRepo, followRepo, locFollowRepo, postRepo, locRepo, modRepo)
+		}
+	}
+}
+
+func refreshQueuedSuggestions(ctx context.Context, suggestionRepo *data.SuggestionRepository, followRepo *data.FollowRepository, locFollowRepo *data.LocationFollowRepository, postRepo *data.PostRepository, locRepo *data.LocationRepository, modRepo *data.ModerationRepository) {
+	userIDs, err := suggestionRepo.GetRefreshRequests(ctx, suggestionRefreshBatch)
+	if err != nil {
+		slog.Error("Failed to fetch suggestion refreshes", "error", err)
+	}
+
+	for _, userID := range userIDs {
+		if err := computeSuggestions(ctx, suggestionRepo, followRepo, locFollowRepo, postRepo, locRepo, modRepo, userID); err != nil {
+			// Left queued for the next run
+			slog.Warn("Failed to compute suggestions", "error", err, "user_id", userID)
+			continue
+		}
+		if err := suggestionRepo.ClearRefreshRequest(ctx, userID); err != nil {
+			slog.Warn("Failed to clear suggestion refresh", "error", err, "user_id", userID)
+		}
+	}
+	if len(userIDs) > 0 {
+		slog.Info("Computed suggestions", "count", len(userIDs))
+	}
+}
+
+// computeSuggestions ranks accounts for userID to follow from friends of
+// friends, followers not followed back, shared followed locations and people
+// posting near them, then stores the result
+func computeSuggestions(ctx context.Context, suggestionRepo *data.SuggestionRepository, followRepo *data.FollowRepository, locFollowRepo *data.LocationFollowRepository, postRepo *data.PostRepository, locRepo *data.LocationRepository, modRepo *data.ModerationRepository, userID string) error {
+	following, err := followRepo.GetAllFollowing(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	excluded := map[string]bool{userID: true}
+	if modRepo != nil {
+		excluded, _ = modRepo.GetBlockedAndMutedUsers(ctx, userID)
+		excluded[userID] = true
+	}
+	for _, id := range following {
+		excluded[id] = true
+	}
+
+	// Accounts the user already asked to follow are waiting on an answer
+	requested, err := followRepo.GetRequestedFollows(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range requested {
+		excluded[id] = true
+	}
+
+	candidates := make(map[string]*suggestionCandidate)
+	candidate := func(id string) *suggestionCandidate {
+		c, ok := candidates[id]
+		if !ok {
+			c = &suggestionCandidate{userID: id}
+			candidates[id] = c
+		}
+		return c
+	}
+
+	// Friends of friends
+	for _, followedID := range following[:min(len(following), suggestionFanout)] {
+		theirs, err := followRepo.GetFollowing(ctx, followedID, suggestionFollowsPerUser)
+		if err != nil {
+			slog.Warn("Failed to fetch following", "error", err, "user_id", followedID)
+			continue
+		}
+		for _, id := range theirs {
+			if !excluded[id] {
+				candidate(id).mutuals = append(candidate(id).mutuals, followedID)
+			}
+		}
+	}
+
+	// Followers not followed back
+	followers, err := followRepo.GetFollowers(ctx, userID, 100)
+	if err != nil {
+		slog.Warn("Failed to fetch followers", "error", err, "user_id", userID)
+	}
+	for _, id := range followers {
+		if !excluded[id] {
+			candidate(id).followsYou = true
+		}
+	}
+
+	// Shared followed locations
+	if locFollowRepo != nil {
+		locations, err := locFollowRepo.GetFollowedLocations(ctx, userID)
+		if err != nil {
+			slog.Warn("Failed to fetch followed locations", "error", err, "user_id", userID)
+		}
+		for _, loc := range locations[:min(len(locations), suggestionMaxLocations)] {
+			ids, err := locFollowRepo.GetUsersFollowingLocation(ctx, loc.GeohashPrefix)
+			if err != nil {
+				slog.Warn("Failed to fetch location followers", "error", err, "geohash", loc.GeohashPrefix)
+				continue
+			}
+			for _, id := range ids {
+				if !excluded[id] {
+					candidate(id).places = append(candidate(id).places, loc.Name)
+				}
+			}
+		}
+	}
+
+	// People posting near where the user posts
+	if postRepo != nil {
+		addNearbyPosters(ctx, postRepo, locRepo, userID, excluded, candidate)
+	}
+
+	return suggestionRepo.SaveSuggestions(ctx, userID, rankSuggestions(candidates, excluded, data.MaxStoredSuggestions))
+}
+
+// addNearbyPosters counts recent posts open to everyone by others around the
+// areas userID recently posted from. Accounts userID follows are already
+// excluded, so private accounts never count.
+func addNearbyPosters(ctx context.Context, postRepo *data.PostRepository, locRepo *data.LocationRepository, userID string, excluded map[string]bool, candidate func(string) *suggestionCandidate) {
+	posts, err := postRepo.GetPostsByUser(ctx, userID, 20, data.Keyset{})
+	if err != nil {
+		slog.Warn("Failed to fetch posts", "error", err, "user_id", userID)
+		return
+	}
+
+	seen := make(map[string]bool)
+	// Whether each author's posts are open to everyone, read once per author
+	open := make(map[string]bool)
+	for _, post := range posts {
+		if len(seen) >= suggestionMaxAreas {
+			break
+		}
+		if !post.HasLocation() {
+			continue
+		}
+		area := data.GetGeohashPrefix(post.Latitude, post.Longitude)
+		if seen[area] {
+			continue
+		}
+		seen[area] = true
+
+		place := ""
+		if locRepo != nil {
+			if loc, err := locRepo.GetOrFetch(ctx, area, post.Latitude, post.Longitude); err == nil && loc != nil {
+				place = loc.Name
+				if place == "" {
+					place = loc.DisplayName
+				}
+			}
+		}
+
+		nearby, _, err := postRepo.GetNearbyPosts(ctx, post.Latitude, post.Longitude, suggestionNearbyRadiusKM, suggestionNearbyPostLimit, data.Keyset{})
+		if err != nil {
+			slog.Warn("Failed to fetch nearby posts", "error", err, "geohash", area)
+			continue
+		}
+		for _, p := range nearby {
+			// Only posts open to everyone, so suggestions don't reveal where
+			// anyone posts to a narrower audience or from a private account
+			if excluded[p.UserID] || p.Visibility != data.VisibilityPublic || !p.HasLocation() {
+				continue
+			}
+			isOpen, checked := open[p.UserID]
+			if !checked {
+				isOpen, err = postRepo.IsOpenToEveryone(ctx, &p)
+				if err != nil {
+					slog.Warn("Failed to check author privacy", "error", err, "user_id", p.UserID)
+					continue
+				}
+				open[p.UserID] = isOpen
+			}
+			if !isOpen {
+				continue
+			}
+			c := candidate(p.UserID)
+			c.nearbyPosts++
+			if c.nearbyPlace == "" {
+				c.nearbyPlace = place
+			}
+		}
+	}
+}
+
+// GetSuggestions handles GET /api/v1/users/suggestions
+// Accounts suggested for the current user to follow, best first. They are
+// computed in the background; missing or stale ones are queued for it.
+func GetSuggestions(suggestionRepo *data.SuggestionRepository, userRepo *data.UserRepository, followRepo *data.FollowRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := auth.GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		limit := 20
+		if l := c.Query("limit"); l != "" {
+			parsed, err := strconv.Atoi(l)
+			if err != nil || parsed < 1 || parsed > data.MaxStoredSuggestions {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "limit must be between 1 and 50",
+				})
+				return
+			}
+			limit = parsed
+		}
+
+		ctx := c.Request.Context()
+		suggestions, computedAt, err := suggestionRepo.GetSuggestions(ctx, userID)
+		if err != nil {
+			slog.Error("Failed to fetch suggestions", "error", err, "user_id", userID)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch suggestions",
+			})
+			return
+		}
+
+		if computedAt.IsZero() || time.Since(computedAt) > suggestionMaxAge {
+			if err := suggestionRepo.RequestRefresh(ctx, userID); err != nil {
+				slog.Warn("Failed to request suggestion refresh", "error", err, "user_id", userID)
+			}
+		}
+
+		// Follows, blocks and mutes since the suggestions were computed
+		ids := make([]string, len(suggestions))
+		for i, s := range suggestions {
+			ids[i] = s.UserID
+		}
+		excluded := make(map[string]bool)
+		if modRepo != nil {
+			excluded, _ = modRepo.GetBlockedAndMutedUsers(ctx, userID)
+		}
+		if following, err := followRepo.GetFollowingAmong(ctx, userID, ids); err == nil {
+			for id := range following {
+				excluded[id] = true
+			}
+		}
+
+		var lookup []string
+		for _, s := range suggestions {
+			if !excluded[s.UserID] {
+				lookup = append(lookup, s.UserID)
+				if s.MutualID != "" {
+					lookup = append(lookup, s.MutualID)
+				}
+			}
+		}
+		users, _ := userRepo.GetUsersByIDs(ctx, lookup)
+
+		result := make([]data.Suggestion, 0, limit)
+		for _, s := range suggestions {
+			if len(result) == limit {
+				break
+			}
+			// Deleted accounts are skipped
+			info, ok := users[s.UserID]
+			if excluded[s.UserID] || !ok {
+				continue
+			}
+			s.Username = info.Username
+			s.ProfilePictureURL = storage.ResolveMediaURL(store, info.ProfilePictureURL)
+			mutualName := ""
+			if !excluded[s.MutualID] {
+				mutualName = users[s.MutualID].Username
+			}
+			s.Explanation = suggestionExplanation(s, mutualName)
+			result = append(result, s)
+		}
+
+		var computed *time.Time
+		if !computedAt.IsZero() {
+			computed = &computedAt
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"data":        result,
+			"count":       len(result),
+			"computed_at": computed,
+		})
+	}
+}
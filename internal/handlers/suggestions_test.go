@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"social-geo-go/internal/data"
+)
+
+func TestRankSuggestions(t *testing.T) {
+	candidates := map[string]*suggestionCandidate{
+		"mutual":   {userID: "mutual", mutuals: []string{"x", "y"}},
+		"fan":      {userID: "fan", followsYou: true},
+		"local":    {userID: "local", places: []string{"Kemang"}},
+		"poster":   {userID: "poster", nearbyPosts: 40, nearbyPlace: "Kemang"},
+		"blocked":  {userID: "blocked", mutuals: []string{"x", "y", "z"}},
+		"nothing":  {userID: "nothing"},
+		"mutual-2": {userID: "mutual-2", mutuals: []string{"y", "x"}},
+	}
+
+	ranked := rankSuggestions(candidates, map[string]bool{"blocked": true}, 10)
+	ids := make([]string, len(ranked))
+	for i, s := range ranked {
+		ids[i] = s.UserID
+	}
+	// Ties break by user ID; nearby posts are capped below a single mutual
+	assert.Equal(t, []string{"mutual", "mutual-2", "poster", "fan", "local"}, ids)
+
+	require.Len(t, ranked, 5)
+	assert.Equal(t, data.SuggestionReasonMutual, ranked[0].Reason)
+	assert.Equal(t, "x", ranked[0].MutualID)
+	assert.Equal(t, 2, ranked[0].MutualCount)
+	assert.Equal(t, data.SuggestionReasonNearby, ranked[2].Reason)
+	assert.Equal(t, 2.5, ranked[2].Score)
+	assert.Equal(t, data.SuggestionReasonFollowsYou, ranked[3].Reason)
+	assert.Equal(t, data.SuggestionReasonLocation, ranked[4].Reason)
+	assert.Equal(t, "Kemang", ranked[4].Place)
+
+	assert.Len(t, rankSuggestions(candidates, nil, 2), 2)
+}
+
+func TestSuggestionExplanation(t *testing.T) {
+	mutual := func(n int) data.Suggestion {
+		return data.Suggestion{Reason: data.SuggestionReasonMutual, MutualCount: n}
+	}
+	assert.Equal(t, "Followed by alice", suggestionExplanation(mutual(1), "alice"))
+	assert.Equal(t, "Followed by alice and 1 other", suggestionExplanation(mutual(2), "alice"))
+	assert.Equal(t, "Followed by alice and 3 others", suggestionExplanation(mutual(4), "alice"))
+	assert.Equal(t, "Followed by 4 people you follow", suggestionExplanation(mutual(4), ""))
+
+	assert.Equal(t, "Follows you", suggestionExplanation(data.Suggestion{Reason: data.SuggestionReasonFollowsYou}, ""))
+	assert.Equal(t, "Also follows Kemang", suggestionExplanation(data.Suggestion{Reason: data.SuggestionReasonLocation, Place: "Kemang"}, ""))
+	assert.Equal(t, "Posts near Kemang", suggestionExplanation(data.Suggestion{Reason: data.SuggestionReasonNearby, Place: "Kemang"}, ""))
+	assert.Equal(t, "Posts near you", suggestionExplanation(data.Suggestion{Reason: data.SuggestionReasonNearby}, ""))
+}
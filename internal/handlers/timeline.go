@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/storage"
+)
+
+// GetFollowingFeed handles GET /api/v1/feed/following
+func GetFollowingFeed(timelineRepo *data.TimelineRepository, postRepo *data.PostRepository, pollRepo *data.PollRepository, bookmarkRepo *data.BookmarkRepository, userRepo *data.UserRepository, locRepo *data.LocationRepository, likeRepo *data.LikeRepository, commentRepo *data.CommentRepository, modRepo *data.ModerationRepository, store storage.MediaStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentUserID := auth.GetUserID(c)
+		if currentUserID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var req data.Pagination
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid query parameters",
+			})
+			return
+		}
+
+		// Decode cursor for pagination
+		cursor, err := data.DecodeCursor(data.CursorScopeFollowing, req.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor",
+			})
+			return
+		}
+
+		// Apply default limit
+		limit := data.GetDefaultLimit(req.Limit, 20, 100)
+
+		var excludedUsers map[string]bool
+		if modRepo != nil {
+			excludedUsers, _ = modRepo.GetBlockedAndMutedUsers(c.Request.Context(), currentUserID)
+		}
+
+		// Fetch extra to account for filtered posts + pagination
+		fetchLimit := limit + 1
+		if len(excludedUsers) > 0 {
+			fetchLimit = limit*2 + 1
+		}
+
+		posts, err := timelineRepo.GetHomeTimeline(c.Request.Context(), currentUserID, fetchLimit, cursor.Keyset)
+		if err != nil {
+			slog.Error("Failed to fetch home timeline", "error", err, "user_id", currentUserID)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch feed",
+			})
+			return
+		}
+
+		// Filter out posts from blocked/muted users
+		posts = excludePostAuthors(posts, excludedUsers)
+
+		// Determine if there are more posts
+		hasMore := len(posts) > limit
+		if hasMore {
+			posts = posts[:limit]
+		}
+
+		// Reposts are positioned by when they were reposted
+		var nextCursor string
+		if hasMore && len(posts) > 0 {
+			nextCursor = data.EncodeCursor(data.Cursor{
+				Scope:  data.CursorScopeFollowing,
+				Keyset: posts[len(posts)-1].Keyset(),
+			})
+		}
+
+		if posts == nil {
+			posts = []data.Post{}
+		}
+
+		EnrichPosts(c.Request.Context(), posts, postRepo, pollRepo, bookmarkRepo, userRepo, locRepo, likeRepo, commentRepo, currentUserID, store)
+
+		c.JSON(http.StatusOK, data.PaginatedResponse{
+			Data:       posts,
+			Count:      len(posts),
+			HasMore:    hasMore,
+			NextCursor: nextCursor,
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social-geo-go/internal/auth"
+	"social-geo-go/internal/cache"
+	"social-geo-go/internal/data"
+	"social-geo-go/internal/search"
+)
+
+// maxTrendingRadiusKM bounds the region trending hashtags are computed for
+const maxTrendingRadiusKM = 100
+
+// Trending sources
+const (
+	trendingSourceRedis         = "redis"
+	trendingSourceElasticsearch = "elasticsearch"
+)
+
+// trendingRequest represents query parameters for trending hashtags
+type trendingRequest struct {
+	Latitude  *float64 `form:"lat" binding:"required"`
+	Longitude *float64 `form:"lng" binding:"required"`
+	RadiusKM  float64  `form:"radius"`
+	Limit     int      `form:"limit"`
+}
+
+// GetTrending handles GET /api/v1/trending. Hashtag usage comes from the
+// Redis counts kept as posts are created, or from an Elasticsearch
+// aggregation when Redis is unavailable.
+func GetTrending(trendingCounter *cache.TrendingCounter, svc search.Service, modRepo *data.ModerationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req trendingRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng query parameters are required"})
+			return
+		}
+		lat, lng := *req.Latitude, *req.Longitude
+		if lat < -90 || lat > 90 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Latitude must be between -90 and 90"})
+			return
+		}
+		if lng < -180 || lng > 180 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Longitude must be between -180 and 180"})
+			return
+		}
+		if req.RadiusKM <= 0 {
+			req.RadiusKM = 25
+		}
+		if req.RadiusKM > maxTrendingRadiusKM {
+			req.RadiusKM = maxTrendingRadiusKM
+		}
+		limit := data.GetDefaultLimit(req.Limit, 10, 50)
+
+		ctx := c.Request.Context()
+		var usage []data.HashtagUsage
+		source := ""
+		if trendingCounter != nil {
+			cells := data.TrendingCover(lat, lng, req.RadiusKM)
+			recent, baseline, err := trendingCounter.GetHashtagCounts(ctx, cells, time.Now(), data.TrendingRecentHours, data.TrendingBaselineDays)
+			if err != nil {
+				slog.Warn("Failed to read trending counts, falling back to search", "error", err)
+			} else {
+				source = trendingSourceRedis
+				for tag, counts := range recent {
+					usage = append(usage, data.HashtagUsage{Hashtag: tag, Recent: counts, Baseline: baseline[tag]})
+				}
+			}
+		}
+		if source == "" {
+			var err error
+			usage, err = svc.TrendingHashtags(ctx, lat, lng, req.RadiusKM)
+			if err != nil {
+				slog.Error("Failed to fetch trending hashtags", "error", err)
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "trending unavailable"})
+				return
+			}
+			source = trendingSourceElasticsearch
+		}
+
+		// Hashtags matching one of the user's muted words are left out
+		muted := make(map[string]bool)
+		if userID := auth.GetUserID(c); modRepo != nil && userID != "" {
+			words, err := modRepo.GetMutedWords(ctx, userID)
+			if err != nil {
+				slog.Warn("Failed to fetch muted words", "error", err, "user_id", userID)
+			}
+			for _, w := range words {
+				muted[w] = true
+			}
+		}
+
+		trending := data.RankTrending(usage, muted, limit)
+		c.JSON(http.StatusOK, gin.H{
+			"trending": trending,
+			"count":    len(trending),
+			"source":   source,
+		})
+	}
+}
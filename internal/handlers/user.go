@@ -79,7 +79,8 @@ func CreateUser(repo *data.UserRepository) gin.HandlerFunc {
 }
 
 // GetUser handles GET /api/v1/users/:id
-func GetUser(repo *data.UserRepository, store storage.MediaStore) gin.HandlerFunc {
+// Non-followers of a private account get its basic profile only
+func GetUser(repo *data.UserRepository, followRepo *data.FollowRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 
@@ -106,15 +107,12 @@ func GetUser(repo *data.UserRepository, store storage.MediaStore) gin.HandlerFun
 		}
 
 		ResolveUserMediaURLs(store, user)
-
-		c.JSON(http.StatusOK, gin.H{
-			"user": user,
-		})
+		respondWithUserProfile(c, followRepo, user)
 	}
 }
 
 // GetUserByUsername handles GET /api/v1/users/username/:username
-func GetUserByUsername(repo *data.UserRepository, store storage.MediaStore) gin.HandlerFunc {
+func GetUserByUsername(repo *data.UserRepository, followRepo *data.FollowRepository, store storage.MediaStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		username := c.Param("username")
 
@@ -134,9 +132,6 @@ func GetUserByUsername(repo *data.UserRepository, store storage.MediaStore) gin.
 		}
 
 		ResolveUserMediaURLs(store, user)
-
-		c.JSON(http.StatusOK, gin.H{
-			"user": user,
-		})
+		respondWithUserProfile(c, followRepo, user)
 	}
 }
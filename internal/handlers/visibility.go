@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"social-geo-go/internal/data"
+)
+
+// maxVisibleFetchRounds bounds how many times a page that lost posts to
+// filtering is refilled before it is returned short.
+const maxVisibleFetchRounds = 3
+
+// collectPosts reads keyset-ordered posts from fetch, applies keep to each
+// batch, and refills until more than limit posts survive or the source runs
+// out, so restricted or excluded posts do not end pagination early. fetch
+// returns a batch and where the next read continues, zero once the source has
+// run out. It returns at most limit posts and the keyset the next page should
+// start after, or nil when there is nothing more. If the refill budget runs
+// out first, the page is short and next points at the last row scanned.
+func collectPosts(fetch func(after data.Keyset, n int) ([]data.Post, data.Keyset, error), after data.Keyset, fetchLimit, limit int, keep func([]data.Post) []data.Post) ([]data.Post, *data.Keyset, error) {
+	var posts []data.Post
+	for round := 0; round < maxVisibleFetchRounds; round++ {
+		batch, resume, err := fetch(after, fetchLimit)
+		if err != nil {
+			return nil, nil, err
+		}
+		posts = append(posts, keep(batch)...)
+
+		if len(posts) > limit {
+			posts = posts[:limit]
+			last := posts[len(posts)-1]
+			return posts, &data.Keyset{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+		}
+		if resume.IsZero() {
+			return posts, nil, nil
+		}
+		after = resume
+	}
+	return posts, &after, nil
+}
+
+// batchResume is where a source that only returns a short batch once it has
+// run out continues after batch, a read of n posts
+func batchResume(batch []data.Post, n int) data.Keyset {
+	if len(batch) < n {
+		return data.Keyset{}
+	}
+	last := batch[len(batch)-1]
+	return data.Keyset{CreatedAt: last.CreatedAt, ID: last.ID}
+}
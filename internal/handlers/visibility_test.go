@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"social-geo-go/internal/data"
+)
+
+// keysetSource serves posts newest-first after a keyset, like the post repositories
+func keysetSource(posts []data.Post) func(after data.Keyset, n int) ([]data.Post, data.Keyset, error) {
+	return func(after data.Keyset, n int) ([]data.Post, data.Keyset, error) {
+		var out []data.Post
+		for _, p := range posts {
+			if after.Admits(p.CreatedAt, p.ID) && len(out) < n {
+				out = append(out, p)
+			}
+		}
+		return out, batchResume(out, n), nil
+	}
+}
+
+// cappedSource serves at most scanCap rows per read, like a proximity scan
+// that stops early, and reports the last row it scanned
+func cappedSource(posts []data.Post, scanCap int) func(after data.Keyset, n int) ([]data.Post, data.Keyset, error) {
+	return func(after data.Keyset, n int) ([]data.Post, data.Keyset, error) {
+		var out []data.Post
+		var last data.Keyset
+		scanned := 0
+		for _, p := range posts {
+			if !after.Admits(p.CreatedAt, p.ID) {
+				continue
+			}
+			if len(out) == n || scanned == scanCap {
+				return out, last, nil
+			}
+			scanned++
+			last = p.Keyset()
+			if p.Visibility != "" {
+				out = append(out, p)
+			}
+		}
+		return out, data.Keyset{}, nil
+	}
+}
+
+func TestCollectPosts(t *testing.T) {
+	base := time.UnixMilli(1767609000000)
+	var posts []data.Post
+	for i := 0; i < 20; i++ {
+		posts = append(posts, data.Post{
+			ID:        fmt.Sprintf("p%02d", i),
+			CreatedAt: base.Add(-time.Duration(i) * time.Minute),
+			// Only every fifth post is visible
+			Visibility: map[bool]string{true: data.VisibilityPublic, false: data.VisibilityOnlyMe}[i%5 == 0],
+		})
+	}
+	keepPublic := func(batch []data.Post) []data.Post {
+		var out []data.Post
+		for _, p := range batch {
+			if p.Visibility == data.VisibilityPublic {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+
+	t.Run("Refills Filtered Pages", func(t *testing.T) {
+		page, next, err := collectPosts(keysetSource(posts), data.Keyset{}, 6, 2, keepPublic)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, "p00", page[0].ID)
+		assert.Equal(t, "p05", page[1].ID)
+		require.NotNil(t, next)
+		assert.Equal(t, "p05", next.ID)
+	})
+
+	t.Run("Exhausted Source Has No Next", func(t *testing.T) {
+		page, next, err := collectPosts(keysetSource(posts), data.Keyset{}, 21, 10, keepPublic)
+		require.NoError(t, err)
+		assert.Len(t, page, 4)
+		assert.Nil(t, next)
+	})
+
+	t.Run("Refill Budget Returns Short Page With Cursor", func(t *testing.T) {
+		page, next, err := collectPosts(keysetSource(posts), data.Keyset{}, 2, 5, keepPublic)
+		require.NoError(t, err)
+		// Three rounds of two rows reach p05 only
+		assert.Len(t, page, 2)
+		require.NotNil(t, next)
+		assert.Equal(t, "p05", next.ID)
+	})
+
+	t.Run("Capped Source Resumes Past Empty Reads", func(t *testing.T) {
+		sparse := make([]data.Post, len(posts))
+		copy(sparse, posts)
+		// Only p00 and p12 are in range; the reads in between come back empty
+		for i := range sparse {
+			sparse[i].Visibility = ""
+		}
+		sparse[0].Visibility = data.VisibilityPublic
+		sparse[12].Visibility = data.VisibilityPublic
+
+		// Three reads of four rows reach p11 and the page resumes there
+		// instead of ending
+		page, next, err := collectPosts(cappedSource(sparse, 4), data.Keyset{}, 10, 1, keepPublic)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "p00", page[0].ID)
+		require.NotNil(t, next)
+		assert.Equal(t, "p11", next.ID)
+
+		page, _, err = collectPosts(cappedSource(sparse, 4), *next, 10, 1, keepPublic)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "p12", page[0].ID)
+	})
+}
@@ -56,3 +56,12 @@ func (d *NotificationDispatcher) DispatchNearbyFanout(ctx context.Context, job *
 	// Fallback for nearby fanout is omitted in Phase 1 as it would be too slow inline
 	return nil
 }
+
+func (d *NotificationDispatcher) DispatchHashtagFanout(ctx context.Context, job *kafka.HashtagFanoutJob) error {
+	if d.kafkaEnabled {
+		return d.kafkaProducer.ProduceHashtagFanout(ctx, job)
+	}
+
+	// Like nearby fanout, hashtag followers are only notified through Kafka
+	return nil
+}
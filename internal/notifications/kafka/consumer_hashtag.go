@@ -0,0 +1,70 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gocql/gocql"
+	kafkago "github.com/segmentio/kafka-go"
+	"social-geo-go/internal/data"
+)
+
+type HashtagFanoutHandler struct {
+	hashtagRepo   *data.HashtagRepository
+	kafkaProducer NotificationEventProducer
+}
+
+func NewHashtagFanoutHandler(hashtagRepo *data.HashtagRepository, producer NotificationEventProducer) *HashtagFanoutHandler {
+	return &HashtagFanoutHandler{
+		hashtagRepo:   hashtagRepo,
+		kafkaProducer: producer,
+	}
+}
+
+// Handle notifies the followers of a new public post's hashtags. A user
+// following several of them is notified once, for the first.
+func (h *HashtagFanoutHandler) Handle(ctx context.Context, msg kafkago.Message) error {
+	var job HashtagFanoutJob
+	if err := json.Unmarshal(msg.Value, &job); err != nil {
+		return fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	notifiedUsers := make(map[string]bool)
+	notifiedUsers[job.AuthorID] = true // Don't notify the author
+
+	for _, tag := range job.Hashtags {
+		users, err := h.hashtagRepo.GetHashtagFollowers(ctx, tag)
+		if err != nil {
+			slog.Warn("failed to fetch followers for hashtag", "hashtag", tag, "error", err)
+			continue
+		}
+
+		for _, userID := range users {
+			if notifiedUsers[userID] {
+				continue
+			}
+			notifiedUsers[userID] = true
+
+			event := &NotificationEvent{
+				EventID:     gocql.TimeUUID().String(),
+				EventType:   data.NotificationTypeHashtagPost,
+				ActorID:     job.AuthorID,
+				RecipientID: userID,
+				TargetType:  data.TargetTypePost,
+				TargetID:    job.PostID,
+				Message:     "New post in #" + tag,
+				Payload:     map[string]string{"post_preview": job.Content, "hashtag": tag},
+				CreatedAt:   time.Now().Format(time.RFC3339),
+			}
+
+			if err := h.kafkaProducer.ProduceNotificationEvent(ctx, event); err != nil {
+				slog.Error("failed to produce hashtag notification", "user", userID, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -31,13 +31,31 @@ func (h *NearbyFanoutHandler) Handle(ctx context.Context, msg kafkago.Message) e
 		return fmt.Errorf("unmarshal error: %w", err)
 	}
 
+	// Location followers are not the author's audience; restricted posts stay quiet
+	if job.Visibility != "" && job.Visibility != data.VisibilityPublic {
+		return nil
+	}
+
+	// Posts with a hidden location carry no geohash
+	if job.Geohash == "" {
+		return nil
+	}
+
 	geohashPrefix := job.Geohash
 	if len(geohashPrefix) > 5 {
 		geohashPrefix = geohashPrefix[:5]
 	}
-	
-	neighbors := geohash.Neighbors(geohashPrefix)
-	allGeohashes := append([]string{geohashPrefix}, neighbors...)
+
+	// Location follows are kept per 5-char cell. A city-level post only has a
+	// coarser cell, so it reaches followers of every 5-char cell inside it
+	// rather than a guessed point and its neighbours.
+	var allGeohashes []string
+	if len(geohashPrefix) < 5 {
+		allGeohashes = geohashChildren(geohashPrefix, 5)
+	} else {
+		neighbors := geohash.Neighbors(geohashPrefix)
+		allGeohashes = append([]string{geohashPrefix}, neighbors...)
+	}
 
 	notifiedUsers := make(map[string]bool)
 	notifiedUsers[job.AuthorID] = true // Don't notify the author
@@ -75,3 +93,21 @@ func (h *NearbyFanoutHandler) Handle(ctx context.Context, msg kafkago.Message) e
 
 	return nil
 }
+
+// geohashBase32 is the geohash alphabet
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashChildren lists every cell of the given length inside prefix
+func geohashChildren(prefix string, length int) []string {
+	cells := []string{prefix}
+	for l := len(prefix); l < length; l++ {
+		next := make([]string, 0, len(cells)*len(geohashBase32))
+		for _, cell := range cells {
+			for _, c := range geohashBase32 {
+				next = append(next, cell+string(c))
+			}
+		}
+		cells = next
+	}
+	return cells
+}
@@ -37,10 +37,21 @@ type PushRetryJob struct {
 
 // NearbyFanoutJob is for notification.nearby.fanout
 type NearbyFanoutJob struct {
-	EventID   string `json:"event_id"`
-	PostID    string `json:"post_id"`
-	AuthorID  string `json:"author_id"`
-	Geohash   string `json:"geohash"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"created_at"`
+	EventID    string `json:"event_id"`
+	PostID     string `json:"post_id"`
+	AuthorID   string `json:"author_id"`
+	Geohash    string `json:"geohash"`
+	Content    string `json:"content"`
+	Visibility string `json:"visibility,omitempty"` // Empty on jobs queued before visibility existed (public)
+	CreatedAt  string `json:"created_at"`
+}
+
+// HashtagFanoutJob is for notification.hashtag.fanout
+type HashtagFanoutJob struct {
+	EventID   string   `json:"event_id"`
+	PostID    string   `json:"post_id"`
+	AuthorID  string   `json:"author_id"`
+	Hashtags  []string `json:"hashtags"`
+	Content   string   `json:"content"`
+	CreatedAt string   `json:"created_at"`
 }
@@ -13,16 +13,18 @@ import (
 type NotificationEventProducer interface {
 	ProduceNotificationEvent(ctx context.Context, event *NotificationEvent) error
 	ProduceNearbyFanout(ctx context.Context, job *NearbyFanoutJob) error
+	ProduceHashtagFanout(ctx context.Context, job *HashtagFanoutJob) error
 	ProducePushDispatch(ctx context.Context, job *PushDispatchJob) error
 	ProducePushRetry(ctx context.Context, job *PushRetryJob) error
 	Close() error
 }
 
 type kafkaProducer struct {
-	eventsWriter *kafkago.Writer
-	nearbyWriter *kafkago.Writer
-	pushWriter   *kafkago.Writer
-	retryWriter  *kafkago.Writer
+	eventsWriter  *kafkago.Writer
+	nearbyWriter  *kafkago.Writer
+	hashtagWriter *kafkago.Writer
+	pushWriter    *kafkago.Writer
+	retryWriter   *kafkago.Writer
 }
 
 func NewNotificationEventProducer(brokers []string) NotificationEventProducer {
@@ -42,10 +44,11 @@ func NewNotificationEventProducer(brokers []string) NotificationEventProducer {
 	}
 
 	return &kafkaProducer{
-		eventsWriter: makeWriter("notification.events", true),         // async
-		nearbyWriter: makeWriter("notification.nearby.fanout", false), // sync
-		pushWriter:   makeWriter("notification.push.dispatch", true),
-		retryWriter:  makeWriter("notification.push.retry", true),
+		eventsWriter:  makeWriter("notification.events", true),          // async
+		nearbyWriter:  makeWriter("notification.nearby.fanout", false),  // sync
+		hashtagWriter: makeWriter("notification.hashtag.fanout", false), // sync
+		pushWriter:    makeWriter("notification.push.dispatch", true),
+		retryWriter:   makeWriter("notification.push.retry", true),
 	}
 }
 
@@ -71,6 +74,17 @@ func (p *kafkaProducer) ProduceNearbyFanout(ctx context.Context, job *NearbyFano
 	})
 }
 
+func (p *kafkaProducer) ProduceHashtagFanout(ctx context.Context, job *HashtagFanoutJob) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return p.hashtagWriter.WriteMessages(ctx, kafkago.Message{
+		Key:   []byte(job.PostID),
+		Value: value,
+	})
+}
+
 func (p *kafkaProducer) ProducePushDispatch(ctx context.Context, job *PushDispatchJob) error {
 	value, err := json.Marshal(job)
 	if err != nil {
@@ -101,6 +115,9 @@ func (p *kafkaProducer) Close() error {
 	if e := p.nearbyWriter.Close(); e != nil {
 		err = e
 	}
+	if e := p.hashtagWriter.Close(); e != nil {
+		err = e
+	}
 	if e := p.pushWriter.Close(); e != nil {
 		err = e
 	}
@@ -106,6 +106,98 @@ func (c *ESClient) IndexDocument(ctx context.Context, index, id string, document
 	return nil
 }
 
+// UpdateDocument applies a partial update to an existing document. The fields in
+// partial are merged into the stored _source.
+func (c *ESClient) UpdateDocument(ctx context.Context, index, id string, partial interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"doc": partial})
+	if err != nil {
+		return fmt.Errorf("failed to marshal partial document: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_update/%s", c.baseURL, index, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create update request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("es update request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("es update returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// DeleteDocument removes a document by id. A missing document is not an error,
+// so replayed delete events are harmless.
+func (c *ESClient) DeleteDocument(ctx context.Context, index, id string) error {
+	url := fmt.Sprintf("%s/%s/_doc/%s", c.baseURL, index, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("es delete request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("es delete returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// BulkDelete removes documents by id in a single bulk request.
+func (c *ESClient) BulkDelete(ctx context.Context, index string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		action := map[string]interface{}{
+			"delete": map[string]interface{}{
+				"_index": index,
+				"_id":    id,
+			},
+		}
+		actionBytes, _ := json.Marshal(action)
+		buf.Write(actionBytes)
+		buf.WriteByte('\n')
+	}
+
+	url := fmt.Sprintf("%s/_bulk", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("es bulk request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("es bulk returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
 // BulkIndex performs a bulk indexing operation. The documents parameter is a slice
 // of BulkDocument, each containing an ID and the document body.
 func (c *ESClient) BulkIndex(ctx context.Context, index string, documents []BulkDocument) error {
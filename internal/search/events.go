@@ -1,9 +1,9 @@
 package search
 
 import (
-	"regexp"
-	"strings"
 	"time"
+
+	"social-geo-go/internal/data"
 )
 
 // UserIndexedEvent is published when a user should be indexed or re-indexed in Elasticsearch.
@@ -30,27 +30,33 @@ type PostCreatedEvent struct {
 	LikeCount int       `json:"like_count"`
 }
 
-var hashtagPattern = regexp.MustCompile(`#([A-Za-z0-9_]+)`)
+// PostUpdatedEvent is published when a post's content is edited so the search
+// document can be updated in place.
+type PostUpdatedEvent struct {
+	PostID   string    `json:"post_id"`
+	UserID   string    `json:"user_id"`
+	Content  string    `json:"content"`
+	Hashtags []string  `json:"hashtags"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+// PostDeletedEvent is published when a post is removed so its search document
+// can be deleted.
+type PostDeletedEvent struct {
+	PostID    string    `json:"post_id"`
+	UserID    string    `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// UserDeletedEvent is published when an account is deleted. Username is the
+// name before anonymization, used to drop it from autocomplete.
+type UserDeletedEvent struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
 
 // ExtractHashtags returns lowercase hashtag tokens without the leading '#'.
 func ExtractHashtags(content string) []string {
-	matches := hashtagPattern.FindAllStringSubmatch(content, -1)
-	if len(matches) == 0 {
-		return nil
-	}
-
-	seen := make(map[string]struct{}, len(matches))
-	hashtags := make([]string, 0, len(matches))
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
-		tag := strings.ToLower(match[1])
-		if _, ok := seen[tag]; ok {
-			continue
-		}
-		seen[tag] = struct{}{}
-		hashtags = append(hashtags, tag)
-	}
-	return hashtags
+	return data.ExtractHashtags(content)
 }
@@ -15,7 +15,9 @@ import (
 // HydratePosts takes a slice of post_ids and returns full Post objects
 // by querying posts_by_id in Cassandra. Uses concurrent queries with a
 // semaphore of max 10 in-flight requests. Preserves ordering of input IDs.
-func HydratePosts(ctx context.Context, ids []string, session *gocql.Session) ([]data.Post, error) {
+// Posts whose audience does not include viewerID are dropped, so a stale or
+// mis-indexed document can never expose a restricted post.
+func HydratePosts(ctx context.Context, ids []string, session *gocql.Session, viewerID string) ([]data.Post, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
@@ -72,7 +74,7 @@ func HydratePosts(ctx context.Context, ids []string, session *gocql.Session) ([]
 		return nil, fmt.Errorf("hydrate posts: all queries failed, last error: %w", firstErr)
 	}
 
-	return posts, nil
+	return data.NewPostRepository(session, nil).FilterVisiblePosts(ctx, posts, viewerID), nil
 }
 
 // HydrateUsers takes a slice of user_ids and returns full User objects
@@ -144,16 +146,16 @@ func getPostByID(ctx context.Context, session *gocql.Session, id string) (*data.
 	}
 
 	var post data.Post
-	var userID gocql.UUID
+	var userID, quotedPostID gocql.UUID
 	var mediaURLs []string
 
 	err = session.Query(`
-		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, created_at
+		SELECT post_id, user_id, content, media_urls, latitude, longitude, geohash, visibility, location_precision, quoted_post_id, created_at
 		FROM posts_by_id
 		WHERE post_id = ?
 	`, postID).WithContext(ctx).Scan(
 		&postID, &userID, &post.Content, &mediaURLs,
-		&post.Latitude, &post.Longitude, &post.Geohash, &post.CreatedAt,
+		&post.Latitude, &post.Longitude, &post.Geohash, &post.Visibility, &post.LocationPrecision, &quotedPostID, &post.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -162,6 +164,15 @@ func getPostByID(ctx context.Context, session *gocql.Session, id string) (*data.
 	post.ID = postID.String()
 	post.UserID = userID.String()
 	post.MediaURLs = mediaURLs
+	if post.Visibility == "" {
+		post.Visibility = data.VisibilityPublic
+	}
+	if post.LocationPrecision == "" {
+		post.LocationPrecision = data.LocationPrecisionExact
+	}
+	if quotedPostID != (gocql.UUID{}) {
+		post.QuotedPostID = quotedPostID.String()
+	}
 
 	return &post, nil
 }
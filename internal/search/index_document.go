@@ -4,14 +4,15 @@ import "time"
 
 // PostDocument is the Elasticsearch document shape for a post.
 type PostDocument struct {
-	PostID    string    `json:"post_id"`
-	UserID    string    `json:"user_id"`
-	Content   string    `json:"content"`
-	Hashtags  []string  `json:"hashtags,omitempty"`
-	Location  *GeoPoint `json:"location,omitempty"`
-	Geohash   string    `json:"geohash"`
-	CreatedAt time.Time `json:"created_at"`
-	LikeCount int       `json:"like_count"`
+	PostID    string     `json:"post_id"`
+	UserID    string     `json:"user_id"`
+	Content   string     `json:"content"`
+	Hashtags  []string   `json:"hashtags,omitempty"`
+	Location  *GeoPoint  `json:"location,omitempty"`
+	Geohash   string     `json:"geohash"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	LikeCount int        `json:"like_count"`
 }
 
 type GeoPoint struct {
@@ -36,6 +37,16 @@ func PostDocumentFromEvent(event PostCreatedEvent) PostDocument {
 	return doc
 }
 
+// PostUpdateFromEvent builds the partial document applied to an edited post.
+// Fields not set by an edit (location, like_count, ...) are left untouched.
+func PostUpdateFromEvent(event PostUpdatedEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"content":   event.Content,
+		"hashtags":  event.Hashtags,
+		"edited_at": event.EditedAt,
+	}
+}
+
 // UserDocument is the Elasticsearch document shape for a user.
 type UserDocument struct {
 	UserID        string `json:"user_id"`
@@ -54,6 +54,46 @@ func IndexUserFromEvent(ctx context.Context, es *ESClient, rdb *redis.Client, us
 	return nil
 }
 
+// RemoveUserFromEvent deletes a user document from Elasticsearch and drops the
+// former username from autocomplete in Redis.
+func RemoveUserFromEvent(ctx context.Context, es *ESClient, rdb *redis.Client, usersIndex string, event UserDeletedEvent) error {
+	if event.UserID == "" {
+		return nil
+	}
+
+	if err := es.DeleteDocument(ctx, usersIndex, event.UserID); err != nil {
+		return err
+	}
+
+	if rdb != nil && event.Username != "" {
+		if err := rdb.ZRem(ctx, "users:autocomplete", event.Username+"\xff").Err(); err != nil {
+			slog.Warn("failed to remove username from redis autocomplete",
+				"username", event.Username,
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// PublishUserDeletedAsync publishes a user deleted event without blocking the HTTP handler.
+func PublishUserDeletedAsync(indexer SearchIndexer, event UserDeletedEvent) {
+	if indexer == nil || event.UserID == "" {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		if err := indexer.PublishUserDeleted(ctx, &event); err != nil {
+			slog.Warn("search: failed to publish user deleted",
+				"user_id", event.UserID,
+				"error", err,
+			)
+		}
+	}()
+}
+
 // PublishUserIndexedAsync publishes a user index event without blocking the HTTP handler.
 func PublishUserIndexedAsync(indexer SearchIndexer, event UserIndexedEvent) {
 	if indexer == nil || event.UserID == "" || event.Username == "" {
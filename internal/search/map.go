@@ -0,0 +1,288 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MarkerZoom is the zoom level from which the map returns individual post
+	// markers instead of geohash clusters.
+	MarkerZoom = 16
+	// MaxMapZoom is the highest zoom level accepted by the map endpoint.
+	MaxMapZoom = 22
+
+	maxMapClusters      = 1000
+	maxMapMarkers       = 500
+	mapClusterSampleIDs = 3
+)
+
+// BBox is a map viewport in degrees. West may be greater than East when the
+// viewport crosses the antimeridian.
+type BBox struct {
+	West  float64
+	South float64
+	East  float64
+	North float64
+}
+
+// ParseBBox parses a "min_lon,min_lat,max_lon,max_lat" viewport.
+func ParseBBox(s string) (BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BBox{}, fmt.Errorf("bbox must be min_lon,min_lat,max_lon,max_lat")
+	}
+
+	var vals [4]float64
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return BBox{}, fmt.Errorf("bbox must be min_lon,min_lat,max_lon,max_lat")
+		}
+		vals[i] = v
+	}
+
+	b := BBox{West: vals[0], South: vals[1], East: vals[2], North: vals[3]}
+	if b.West < -180 || b.West > 180 || b.East < -180 || b.East > 180 {
+		return BBox{}, fmt.Errorf("bbox longitudes must be between -180 and 180")
+	}
+	if b.South < -90 || b.North > 90 || b.South > b.North {
+		return BBox{}, fmt.Errorf("bbox latitudes must be between -90 and 90 with min_lat <= max_lat")
+	}
+	return b, nil
+}
+
+// GeohashPrecisionForZoom maps a web map zoom level to the geohash precision
+// used for clustering, so each cluster cell spans roughly a few dozen pixels.
+func GeohashPrecisionForZoom(zoom int) int {
+	switch {
+	case zoom <= 2:
+		return 1
+	case zoom <= 4:
+		return 2
+	case zoom <= 6:
+		return 3
+	case zoom <= 8:
+		return 4
+	case zoom <= 11:
+		return 5
+	case zoom <= 13:
+		return 6
+	case zoom <= 15:
+		return 7
+	default:
+		return 8
+	}
+}
+
+// MapCluster is the number of posts in one geohash cell of the viewport.
+type MapCluster struct {
+	Geohash       string   `json:"geohash"`
+	Count         int      `json:"count"`
+	Latitude      float64  `json:"latitude"`
+	Longitude     float64  `json:"longitude"`
+	SamplePostIDs []string `json:"sample_post_ids"`
+}
+
+// MapMarker is a single post on the map.
+type MapMarker struct {
+	PostID    string    `json:"post_id"`
+	UserID    string    `json:"user_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MapResult holds either clusters or markers, depending on Mode. The list for
+// the other mode is always empty.
+type MapResult struct {
+	Mode      string       `json:"mode"`
+	Zoom      int          `json:"zoom"`
+	Precision int          `json:"precision,omitempty"`
+	Clusters  []MapCluster `json:"clusters"`
+	Markers   []MapMarker  `json:"markers"`
+	Truncated bool         `json:"truncated"`
+}
+
+// Map result modes
+const (
+	MapModeClusters = "clusters"
+	MapModeMarkers  = "markers"
+)
+
+// mapFilterQuery restricts posts to the viewport and drops excluded authors.
+func mapFilterQuery(bbox BBox, excludeUserIDs []string) map[string]interface{} {
+	boolQuery := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"geo_bounding_box": map[string]interface{}{
+				"location": map[string]interface{}{
+					"top_left":     map[string]float64{"lat": bbox.North, "lon": bbox.West},
+					"bottom_right": map[string]float64{"lat": bbox.South, "lon": bbox.East},
+				},
+			},
+		},
+	}
+	if len(excludeUserIDs) > 0 {
+		boolQuery["must_not"] = map[string]interface{}{
+			"terms": map[string]interface{}{"user_id": excludeUserIDs},
+		}
+	}
+	return map[string]interface{}{"bool": boolQuery}
+}
+
+// mapClusterQuery buckets viewport posts by geohash cell, with each cell's
+// centroid and its newest post IDs as samples.
+func mapClusterQuery(bbox BBox, precision int, excludeUserIDs []string) map[string]interface{} {
+	return map[string]interface{}{
+		"size":  0,
+		"query": mapFilterQuery(bbox, excludeUserIDs),
+		"aggs": map[string]interface{}{
+			"cells": map[string]interface{}{
+				"geohash_grid": map[string]interface{}{
+					"field":     "location",
+					"precision": precision,
+					"size":      maxMapClusters,
+				},
+				"aggs": map[string]interface{}{
+					"centroid": map[string]interface{}{
+						"geo_centroid": map[string]interface{}{"field": "location"},
+					},
+					"sample": map[string]interface{}{
+						"top_hits": map[string]interface{}{
+							"size":    mapClusterSampleIDs,
+							"sort":    []interface{}{map[string]interface{}{"created_at": map[string]string{"order": "desc"}}},
+							"_source": []string{"post_id"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// mapMarkerQuery returns the newest viewport posts as individual markers.
+func mapMarkerQuery(bbox BBox, excludeUserIDs []string) map[string]interface{} {
+	return map[string]interface{}{
+		"size":  maxMapMarkers,
+		"query": mapFilterQuery(bbox, excludeUserIDs),
+		"sort": []interface{}{
+			map[string]interface{}{"created_at": map[string]string{"order": "desc"}},
+		},
+		"_source": []string{"post_id", "user_id", "location", "created_at"},
+	}
+}
+
+// parseMapClusters reads the cells aggregation of a mapClusterQuery response.
+func parseMapClusters(body []byte) ([]MapCluster, error) {
+	var resp struct {
+		Aggregations struct {
+			Cells struct {
+				Buckets []struct {
+					Key      string `json:"key"`
+					DocCount int    `json:"doc_count"`
+					Centroid struct {
+						Location struct {
+							Lat float64 `json:"lat"`
+							Lon float64 `json:"lon"`
+						} `json:"location"`
+					} `json:"centroid"`
+					Sample struct {
+						Hits struct {
+							Hits []struct {
+								Source struct {
+									PostID string `json:"post_id"`
+								} `json:"_source"`
+							} `json:"hits"`
+						} `json:"hits"`
+					} `json:"sample"`
+				} `json:"buckets"`
+			} `json:"cells"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse es aggregation: %w", err)
+	}
+
+	clusters := make([]MapCluster, 0, len(resp.Aggregations.Cells.Buckets))
+	for _, b := range resp.Aggregations.Cells.Buckets {
+		ids := make([]string, 0, len(b.Sample.Hits.Hits))
+		for _, h := range b.Sample.Hits.Hits {
+			ids = append(ids, h.Source.PostID)
+		}
+		clusters = append(clusters, MapCluster{
+			Geohash:       b.Key,
+			Count:         b.DocCount,
+			Latitude:      b.Centroid.Location.Lat,
+			Longitude:     b.Centroid.Location.Lon,
+			SamplePostIDs: ids,
+		})
+	}
+	return clusters, nil
+}
+
+// MapPosts returns the posts inside a viewport, clustered by geohash cell below
+// MarkerZoom and as individual markers from MarkerZoom up. Posts by
+// excludeUserIDs (blocked and muted authors) are left out of both.
+func (s *searchService) MapPosts(ctx context.Context, bbox BBox, zoom int, excludeUserIDs []string) (*MapResult, error) {
+	start := time.Now()
+	defer func() {
+		slog.Debug("map posts latency", "zoom", zoom, "elapsed_ms", time.Since(start).Milliseconds())
+	}()
+
+	if zoom >= MarkerZoom {
+		data, err := s.es.Search(ctx, s.postsIndex, mapMarkerQuery(bbox, excludeUserIDs))
+		if err != nil {
+			return nil, fmt.Errorf("map markers: %w", err)
+		}
+		esResp, err := ParseESResponse(data)
+		if err != nil {
+			return nil, fmt.Errorf("map markers parse: %w", err)
+		}
+
+		markers := make([]MapMarker, 0, len(esResp.Hits.Hits))
+		for _, hit := range esResp.Hits.Hits {
+			m := MapMarker{
+				PostID: safeString(hit.Source, "post_id"),
+				UserID: safeString(hit.Source, "user_id"),
+			}
+			if l, ok := hit.Source["location"].(map[string]interface{}); ok {
+				m.Latitude, _ = l["lat"].(float64)
+				m.Longitude, _ = l["lon"].(float64)
+			}
+			m.CreatedAt, _ = time.Parse(time.RFC3339, safeString(hit.Source, "created_at"))
+			markers = append(markers, m)
+		}
+
+		return &MapResult{
+			Mode:      MapModeMarkers,
+			Zoom:      zoom,
+			Clusters:  []MapCluster{},
+			Markers:   markers,
+			Truncated: esResp.Hits.Total.Value > len(markers),
+		}, nil
+	}
+
+	precision := GeohashPrecisionForZoom(zoom)
+	data, err := s.es.Aggregate(ctx, s.postsIndex, mapClusterQuery(bbox, precision, excludeUserIDs))
+	if err != nil {
+		return nil, fmt.Errorf("map clusters: %w", err)
+	}
+	clusters, err := parseMapClusters(data)
+	if err != nil {
+		return nil, fmt.Errorf("map clusters parse: %w", err)
+	}
+
+	return &MapResult{
+		Mode:      MapModeClusters,
+		Zoom:      zoom,
+		Precision: precision,
+		Clusters:  clusters,
+		Markers:   []MapMarker{},
+		Truncated: len(clusters) >= maxMapClusters,
+	}, nil
+}
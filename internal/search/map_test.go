@@ -0,0 +1,93 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseBBox(t *testing.T) {
+	b, err := ParseBBox("106.80,-6.38,106.85,-6.35")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.West != 106.80 || b.South != -6.38 || b.East != 106.85 || b.North != -6.35 {
+		t.Errorf("unexpected bbox %+v", b)
+	}
+
+	// Crossing the antimeridian is allowed
+	if _, err := ParseBBox("179,-10,-179,10"); err != nil {
+		t.Errorf("expected antimeridian bbox to parse, got %v", err)
+	}
+
+	for _, bad := range []string{"", "1,2,3", "a,2,3,4", "1,20,3,10", "1,-91,3,4", "181,2,3,4"} {
+		if _, err := ParseBBox(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestGeohashPrecisionForZoom(t *testing.T) {
+	prev := 0
+	for zoom := 0; zoom <= MaxMapZoom; zoom++ {
+		p := GeohashPrecisionForZoom(zoom)
+		if p < prev {
+			t.Errorf("precision decreased at zoom %d: %d < %d", zoom, p, prev)
+		}
+		if p < 1 || p > 8 {
+			t.Errorf("zoom %d: precision %d out of range", zoom, p)
+		}
+		prev = p
+	}
+	if got := GeohashPrecisionForZoom(12); got != 6 {
+		t.Errorf("zoom 12: expected precision 6, got %d", got)
+	}
+}
+
+func TestMapFilterQueryExcludesUsers(t *testing.T) {
+	q := mapFilterQuery(BBox{West: 1, South: 2, East: 3, North: 4}, nil)
+	if _, ok := q["bool"].(map[string]interface{})["must_not"]; ok {
+		t.Error("expected no must_not without excluded users")
+	}
+
+	q = mapFilterQuery(BBox{West: 1, South: 2, East: 3, North: 4}, []string{"u1"})
+	mustNot, ok := q["bool"].(map[string]interface{})["must_not"]
+	if !ok {
+		t.Fatal("expected must_not for excluded users")
+	}
+	body, _ := json.Marshal(mustNot)
+	if string(body) != `{"terms":{"user_id":["u1"]}}` {
+		t.Errorf("unexpected must_not %s", body)
+	}
+}
+
+func TestParseMapClusters(t *testing.T) {
+	body := []byte(`{
+		"hits": {"total": {"value": 5}, "hits": []},
+		"aggregations": {"cells": {"buckets": [
+			{
+				"key": "qqggyx",
+				"doc_count": 5,
+				"centroid": {"location": {"lat": -6.3651, "lon": 106.8272}, "count": 5},
+				"sample": {"hits": {"hits": [
+					{"_id": "p1", "_source": {"post_id": "p1"}},
+					{"_id": "p2", "_source": {"post_id": "p2"}}
+				]}}
+			}
+		]}}
+	}`)
+
+	clusters, err := parseMapClusters(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("expected 1 cluster, got %d", len(clusters))
+	}
+	c := clusters[0]
+	if c.Geohash != "qqggyx" || c.Count != 5 || c.Latitude != -6.3651 || c.Longitude != 106.8272 {
+		t.Errorf("unexpected cluster %+v", c)
+	}
+	if len(c.SamplePostIDs) != 2 || c.SamplePostIDs[0] != "p1" {
+		t.Errorf("unexpected samples %v", c.SamplePostIDs)
+	}
+}
@@ -10,14 +10,39 @@ import (
 )
 
 const (
-	postsCreatedTopic = "posts.created"
-	usersIndexedTopic = "users.indexed"
+	postsLifecycleTopic = "posts.lifecycle"
+	usersIndexedTopic   = "users.indexed"
+	usersDeletedTopic   = "users.deleted"
 )
 
+// Post lifecycle events share one topic keyed by post ID, so the indexer reads
+// a post's create, update and delete in the order they were published. The
+// event_type header tells them apart.
+const (
+	postEventTypeHeader = "event_type"
+
+	PostEventCreated = "created"
+	PostEventUpdated = "updated"
+	PostEventDeleted = "deleted"
+)
+
+// PostEventType returns the type of a posts.lifecycle message
+func PostEventType(msg kafkago.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == postEventTypeHeader {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
 // SearchIndexer publishes search indexing events for the indexer workers.
 type SearchIndexer interface {
 	PublishPostCreated(ctx context.Context, event *PostCreatedEvent) error
+	PublishPostUpdated(ctx context.Context, event *PostUpdatedEvent) error
+	PublishPostDeleted(ctx context.Context, event *PostDeletedEvent) error
 	PublishUserIndexed(ctx context.Context, event *UserIndexedEvent) error
+	PublishUserDeleted(ctx context.Context, event *UserDeletedEvent) error
 	Close() error
 }
 
@@ -25,8 +50,9 @@ type SearchIndexer interface {
 type PostIndexer = SearchIndexer
 
 type kafkaSearchIndexer struct {
-	postsWriter *kafkago.Writer
-	usersWriter *kafkago.Writer
+	postsWriter       *kafkago.Writer
+	usersWriter       *kafkago.Writer
+	userDeletesWriter *kafkago.Writer
 }
 
 func newKafkaWriter(brokers []string, topic string) *kafkago.Writer {
@@ -44,11 +70,12 @@ func newKafkaWriter(brokers []string, topic string) *kafkago.Writer {
 	}
 }
 
-// NewSearchIndexer creates Kafka producers for posts.created and users.indexed.
+// NewSearchIndexer creates Kafka producers for the post and user lifecycle topics.
 func NewSearchIndexer(brokers []string) SearchIndexer {
 	return &kafkaSearchIndexer{
-		postsWriter: newKafkaWriter(brokers, postsCreatedTopic),
-		usersWriter: newKafkaWriter(brokers, usersIndexedTopic),
+		postsWriter:       newKafkaWriter(brokers, postsLifecycleTopic),
+		usersWriter:       newKafkaWriter(brokers, usersIndexedTopic),
+		userDeletesWriter: newKafkaWriter(brokers, usersDeletedTopic),
 	}
 }
 
@@ -57,31 +84,49 @@ func NewPostIndexer(brokers []string) PostIndexer {
 	return NewSearchIndexer(brokers)
 }
 
-func (p *kafkaSearchIndexer) PublishPostCreated(ctx context.Context, event *PostCreatedEvent) error {
+// publish marshals an event and writes it keyed by the post or user ID.
+func publish(ctx context.Context, w *kafkago.Writer, key string, event interface{}, headers ...kafkago.Header) error {
 	value, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
-	return p.postsWriter.WriteMessages(ctx, kafkago.Message{
-		Key:   []byte(event.PostID),
-		Value: value,
+	return w.WriteMessages(ctx, kafkago.Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: headers,
 	})
 }
 
+// publishPostEvent writes a post lifecycle event of the given type
+func (p *kafkaSearchIndexer) publishPostEvent(ctx context.Context, eventType, postID string, event interface{}) error {
+	return publish(ctx, p.postsWriter, postID, event, kafkago.Header{Key: postEventTypeHeader, Value: []byte(eventType)})
+}
+
+func (p *kafkaSearchIndexer) PublishPostCreated(ctx context.Context, event *PostCreatedEvent) error {
+	return p.publishPostEvent(ctx, PostEventCreated, event.PostID, event)
+}
+
+func (p *kafkaSearchIndexer) PublishPostUpdated(ctx context.Context, event *PostUpdatedEvent) error {
+	return p.publishPostEvent(ctx, PostEventUpdated, event.PostID, event)
+}
+
+func (p *kafkaSearchIndexer) PublishPostDeleted(ctx context.Context, event *PostDeletedEvent) error {
+	return p.publishPostEvent(ctx, PostEventDeleted, event.PostID, event)
+}
+
 func (p *kafkaSearchIndexer) PublishUserIndexed(ctx context.Context, event *UserIndexedEvent) error {
-	value, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-	return p.usersWriter.WriteMessages(ctx, kafkago.Message{
-		Key:   []byte(event.UserID),
-		Value: value,
-	})
+	return publish(ctx, p.usersWriter, event.UserID, event)
+}
+
+func (p *kafkaSearchIndexer) PublishUserDeleted(ctx context.Context, event *UserDeletedEvent) error {
+	return publish(ctx, p.userDeletesWriter, event.UserID, event)
 }
 
 func (p *kafkaSearchIndexer) Close() error {
-	if err := p.postsWriter.Close(); err != nil {
-		return err
+	for _, w := range []*kafkago.Writer{p.postsWriter, p.usersWriter, p.userDeletesWriter} {
+		if err := w.Close(); err != nil {
+			return err
+		}
 	}
-	return p.usersWriter.Close()
+	return nil
 }
@@ -73,6 +73,31 @@ func RankPosts(results []PostResult, userLat, userLon float64) []PostResult {
 	return ranked
 }
 
+// HotScore ranks a feed post by engagement, age and distance using the same
+// weights as RankPosts, with engagement in place of the ES relevance score:
+//
+//	hot_score = (0.5 × engagement_score)
+//	          + (0.3 × recency_score)
+//	          + (0.2 × proximity_score)
+//
+// Engagement is log-scaled (comments count double) and mapped into [0, 1)
+// without normalizing against other posts, so a post's score does not depend on
+// which page it is ranked with. Age is measured at asOf.
+func HotScore(likes, comments int64, createdAt, asOf time.Time, distanceKm float64) float64 {
+	interactions := math.Log1p(float64(likes + 2*comments))
+	engagementScore := interactions / (1.0 + interactions)
+
+	hoursSincePosted := asOf.Sub(createdAt).Hours()
+	if hoursSincePosted < 0 {
+		hoursSincePosted = 0
+	}
+	recencyScore := 1.0 / (1.0 + hoursSincePosted)
+
+	proximityScore := 1.0 / (1.0 + distanceKm)
+
+	return 0.5*engagementScore + 0.3*recencyScore + 0.2*proximityScore
+}
+
 // HaversineDistance calculates the great-circle distance between two points
 // on the Earth (specified in decimal degrees) in kilometers.
 func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
@@ -237,3 +237,41 @@ func TestRankPosts_MaxScoreNormalization(t *testing.T) {
 		}
 	}
 }
+
+func TestHotScore_EngagementOutweighsSmallAgeGap(t *testing.T) {
+	asOf := time.Now()
+
+	popular := HotScore(120, 30, asOf.Add(-3*time.Hour), asOf, 2.0)
+	quiet := HotScore(0, 0, asOf.Add(-2*time.Hour), asOf, 2.0)
+
+	if popular <= quiet {
+		t.Errorf("expected popular post (%f) to outrank quiet post (%f)", popular, quiet)
+	}
+}
+
+func TestHotScore_CloserRanksHigher(t *testing.T) {
+	asOf := time.Now()
+	createdAt := asOf.Add(-1 * time.Hour)
+
+	near := HotScore(5, 1, createdAt, asOf, 0.5)
+	far := HotScore(5, 1, createdAt, asOf, 9.5)
+
+	if near <= far {
+		t.Errorf("expected near post (%f) to outrank far post (%f)", near, far)
+	}
+}
+
+func TestHotScore_DeterministicAtFixedTime(t *testing.T) {
+	asOf := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	createdAt := asOf.Add(-90 * time.Minute)
+
+	first := HotScore(10, 2, createdAt, asOf, 3.0)
+	second := HotScore(10, 2, createdAt, asOf, 3.0)
+
+	if first != second {
+		t.Errorf("expected identical scores, got %f and %f", first, second)
+	}
+	if first <= 0 || first >= 1 {
+		t.Errorf("expected score in (0, 1), got %f", first)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"social-geo-go/internal/data"
 )
 
 // PostResult is the result from an ES post search, containing indexed fields only.
@@ -42,6 +44,8 @@ type Service interface {
 	SearchUsers(ctx context.Context, q string) ([]UserResult, error)
 	AutocompleteUsernames(ctx context.Context, prefix string) ([]string, error)
 	AutocompleteHashtags(ctx context.Context, prefix string) ([]string, error)
+	MapPosts(ctx context.Context, bbox BBox, zoom int, excludeUserIDs []string) (*MapResult, error)
+	TrendingHashtags(ctx context.Context, lat, lon, radiusKm float64) ([]data.HashtagUsage, error)
 }
 
 type searchService struct {
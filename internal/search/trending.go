@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"social-geo-go/internal/data"
+)
+
+// maxTrendingTerms caps how many hashtags the fallback aggregation scores
+const maxTrendingTerms = 100
+
+// trendingQuery counts the hashtags of posts within radiusKm of (lat, lon):
+// per hour over the recent window and in total over the baseline days. The
+// hashtags used most in the recent window are returned.
+func trendingQuery(lat, lon, radiusKm float64, now time.Time) map[string]interface{} {
+	recentStart := now.UTC().Truncate(time.Hour).Add(-(data.TrendingRecentHours - 1) * time.Hour)
+	today := now.UTC().Truncate(24 * time.Hour)
+	baselineStart := today.AddDate(0, 0, -data.TrendingBaselineDays)
+
+	return map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{
+						"geo_distance": map[string]interface{}{
+							"distance": fmt.Sprintf("%.1fkm", radiusKm),
+							"location": map[string]float64{"lat": lat, "lon": lon},
+						},
+					},
+					map[string]interface{}{
+						"range": map[string]interface{}{
+							"created_at": map[string]string{"gte": baselineStart.Format(time.RFC3339)},
+						},
+					},
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"hashtags": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "hashtags",
+					"size":  maxTrendingTerms,
+					"order": map[string]string{"recent": "desc"},
+				},
+				"aggs": map[string]interface{}{
+					"recent": map[string]interface{}{
+						"filter": map[string]interface{}{
+							"range": map[string]interface{}{
+								"created_at": map[string]string{"gte": recentStart.Format(time.RFC3339)},
+							},
+						},
+						"aggs": map[string]interface{}{
+							"hours": map[string]interface{}{
+								"date_histogram": map[string]interface{}{
+									"field":          "created_at",
+									"fixed_interval": "1h",
+								},
+							},
+						},
+					},
+					"baseline": map[string]interface{}{
+						"filter": map[string]interface{}{
+							"range": map[string]interface{}{
+								"created_at": map[string]string{"lt": today.Format(time.RFC3339)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseTrendingUsage reads the hashtags aggregation of a trendingQuery
+// response into hashtag usage
+func parseTrendingUsage(body []byte, now time.Time) ([]data.HashtagUsage, error) {
+	var resp struct {
+		Aggregations struct {
+			Hashtags struct {
+				Buckets []struct {
+					Key    string `json:"key"`
+					Recent struct {
+						Hours struct {
+							Buckets []struct {
+								Key      int64 `json:"key"`
+								DocCount int64 `json:"doc_count"`
+							} `json:"buckets"`
+						} `json:"hours"`
+					} `json:"recent"`
+					Baseline struct {
+						DocCount int64 `json:"doc_count"`
+					} `json:"baseline"`
+				} `json:"buckets"`
+			} `json:"hashtags"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse es aggregation: %w", err)
+	}
+
+	hour := now.UTC().Truncate(time.Hour)
+	usage := make([]data.HashtagUsage, 0, len(resp.Aggregations.Hashtags.Buckets))
+	for _, b := range resp.Aggregations.Hashtags.Buckets {
+		u := data.HashtagUsage{
+			Hashtag:  b.Key,
+			Recent:   make([]int64, data.TrendingRecentHours),
+			Baseline: b.Baseline.DocCount,
+		}
+		for _, h := range b.Recent.Hours.Buckets {
+			hoursAgo := int(hour.Sub(time.UnixMilli(h.Key)) / time.Hour)
+			if hoursAgo >= 0 && hoursAgo < data.TrendingRecentHours {
+				u.Recent[hoursAgo] += h.DocCount
+			}
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// TrendingHashtags returns the usage of the hashtags posted within radiusKm
+// of (lat, lon), counted from the posts index. It backs trending when the
+// Redis counts are unavailable.
+func (s *searchService) TrendingHashtags(ctx context.Context, lat, lon, radiusKm float64) ([]data.HashtagUsage, error) {
+	start := time.Now()
+	defer func() {
+		slog.Debug("trending hashtags latency", "lat", lat, "lon", lon, "elapsed_ms", time.Since(start).Milliseconds())
+	}()
+
+	body, err := s.es.Aggregate(ctx, s.postsIndex, trendingQuery(lat, lon, radiusKm, start))
+	if err != nil {
+		return nil, fmt.Errorf("trending hashtags: %w", err)
+	}
+	usage, err := parseTrendingUsage(body, start)
+	if err != nil {
+		return nil, fmt.Errorf("trending hashtags parse: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,58 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"social-geo-go/internal/data"
+)
+
+func TestTrendingQueryWindows(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 25, 0, 0, time.UTC)
+	body, err := json.Marshal(trendingQuery(-6.2, 106.8, 25, now))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		`"distance":"25.0km"`,
+		`"gte":"2026-03-03T00:00:00Z"`, // Baseline start
+		`"gte":"2026-03-10T09:00:00Z"`, // Recent window start
+		`"lt":"2026-03-10T00:00:00Z"`,  // Baseline end
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected query to contain %s, got %s", want, body)
+		}
+	}
+}
+
+func TestParseTrendingUsage(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 25, 0, 0, time.UTC)
+	hour := now.Truncate(time.Hour)
+	body := fmt.Sprintf(`{"aggregations":{"hashtags":{"buckets":[
+		{"key":"banjir","doc_count":9,
+		 "recent":{"doc_count":7,"hours":{"buckets":[
+			{"key":%d,"doc_count":2},
+			{"key":%d,"doc_count":5}]}},
+		 "baseline":{"doc_count":2}}]}}}`,
+		hour.Add(-2*time.Hour).UnixMilli(), hour.UnixMilli())
+
+	usage, err := parseTrendingUsage([]byte(body), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(usage) != 1 {
+		t.Fatalf("expected 1 hashtag, got %d", len(usage))
+	}
+
+	u := usage[0]
+	if u.Hashtag != "banjir" || u.Baseline != 2 {
+		t.Errorf("unexpected usage %+v", u)
+	}
+	if len(u.Recent) != data.TrendingRecentHours || u.Recent[0] != 5 || u.Recent[2] != 2 {
+		t.Errorf("unexpected recent counts %v", u.Recent)
+	}
+}